@@ -2,18 +2,30 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/ONSdigital/dp-authorisation/v2/authorisation"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-interactives-api/config"
 	"github.com/ONSdigital/dp-interactives-api/event"
 	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/internal/limiter"
+	"github.com/ONSdigital/dp-interactives-api/internal/progress"
+	"github.com/ONSdigital/dp-interactives-api/internal/zip"
 	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/pagination"
 	"github.com/ONSdigital/dp-interactives-api/schema"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
+	"github.com/ONSdigital/dp-net/v2/request"
 	"github.com/ONSdigital/dp-net/v2/responder"
+	permsdk "github.com/ONSdigital/dp-permissions-api/sdk"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/gorilla/mux"
@@ -24,20 +36,30 @@ const (
 	InteractivesReadPermission   string = "interactives:read"
 	InteractivesUpdatePermission string = "interactives:update"
 	InteractivesDeletePermission string = "interactives:delete"
+	// InteractivesAdminPermission allows the slug of a published interactive to be changed
+	InteractivesAdminPermission string = "interactives:admin"
 )
 
 type API struct {
-	cfg           *config.Config
-	Router        *mux.Router
-	mongoDB       MongoServer
-	filesService  FilesService
-	auth          authorisation.Middleware
-	producer      *event.AvroProducer
-	s3            S3Interface
-	newUUID       data.Generator
-	newResourceID data.Generator
-	newSlug       data.Generator
-	respond       *responder.Responder
+	cfg             *config.Config
+	Router          *mux.Router
+	mongoDB         MongoServer
+	filesService    FilesService
+	scanner         Scanner
+	content         ContentStore
+	auth            authorisation.Middleware
+	permissions     PermissionsChecker
+	producer        *event.AvroProducer
+	outbox          *event.OutboxRelay
+	s3              S3Interface
+	newUUID         data.Generator
+	newResourceID   data.Generator
+	newSlug         data.Generator
+	respond         *responder.Responder
+	progress        *progress.Broker
+	uploads         *limiter.Limiter
+	zipLimits       zip.Limits
+	contentScanners []zip.ContentScanner
 }
 
 // Setup creates the API struct and its endpoints with corresponding handlers
@@ -45,10 +67,13 @@ func Setup(ctx context.Context,
 	cfg *config.Config,
 	r *mux.Router,
 	auth authorisation.Middleware,
+	permissions PermissionsChecker,
 	mongoDB MongoServer,
 	kafkaProducer kafka.IProducer,
 	s3 S3Interface,
 	filesService FilesService,
+	scanner Scanner,
+	content ContentStore,
 	newUUID data.Generator,
 	newResourceID data.Generator,
 	newSlug data.Generator,
@@ -56,37 +81,83 @@ func Setup(ctx context.Context,
 
 	var kProducer *event.AvroProducer
 	if kafkaProducer != nil {
-		kProducer = event.NewAvroProducer(kafkaProducer.Channels().Output, schema.InteractiveUploadedEvent)
+		uploadedSchema, err := schema.InteractiveUploadedVersions.Get(cfg.UploadedSchemaVersion)
+		if err != nil {
+			log.Error(ctx, "api setup error - using latest interactive uploaded schema", err)
+			uploadedSchema = schema.InteractiveUploadedEvent
+		}
+		channels := kafkaProducer.Channels()
+		kProducer = event.NewAvroProducerWithDelivery(ctx, channels.Output, channels.Errors, uploadedSchema, cfg.KafkaSendTimeout, cfg.KafkaDeliveryTimeout)
 	} else {
 		log.Error(ctx, "api setup error - no kafka producer", nil)
 	}
 
+	// state changes are announced to event stream subscribers from wherever the api writes them
+	broker := progress.NewBroker()
+	mongoDB = &notifyingStore{MongoServer: mongoDB, progress: broker}
+
 	api := &API{
 		cfg:           cfg,
 		Router:        r,
 		mongoDB:       mongoDB,
 		auth:          auth,
+		permissions:   permissions,
 		s3:            s3,
 		filesService:  filesService,
+		scanner:       scanner,
+		content:       content,
 		producer:      kProducer,
 		newUUID:       newUUID,
 		newSlug:       newSlug,
 		newResourceID: newResourceID,
 		respond:       respond,
+		progress:      broker,
+		uploads:       limiter.New(cfg.UploadConcurrency, cfg.UploadQueueDepth, cfg.UploadQueueTimeout, cfg.UploadRetryAfter),
+		zipLimits: zip.Limits{
+			MaxEntries:          cfg.ZipMaxEntries,
+			MaxUncompressedSize: cfg.ZipMaxUncompressedSize,
+			MaxCompressionRatio: cfg.ZipMaxCompressionRatio,
+		},
+		contentScanners: []zip.ContentScanner{&zip.ContentPolicy{
+			AllowedScriptOrigins: cfg.ContentPolicyScriptOrigins,
+			ForbiddenFileTypes:   zip.DefaultForbiddenFileTypes,
+		}},
+	}
+
+	if kProducer != nil {
+		api.outbox = event.NewOutboxRelay(mongoDB, kProducer, cfg.OutboxRelayInterval, cfg.OutboxBatchSize, cfg.OutboxMaxAttempts)
 	}
 
 	if r != nil {
 		if cfg.PublishingEnabled {
 			r.HandleFunc("/v1/interactives", auth.Require(InteractivesCreatePermission, api.UploadInteractivesHandler)).Methods(http.MethodPost)
 			r.HandleFunc("/v1/interactives", auth.Require(InteractivesReadPermission, api.ListInteractivesHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/bulk", auth.Require(InteractivesUpdatePermission, api.BulkInteractivesHandler)).Methods(http.MethodPost)
+			r.HandleFunc("/v1/interactives/resource/{resource_id}", auth.Require(InteractivesReadPermission, api.GetInteractiveByResourceIDHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/resolve/{path:.*}", auth.Require(InteractivesReadPermission, api.ResolveInteractiveHandler)).Methods(http.MethodGet)
 			r.HandleFunc("/v1/interactives/{id}", auth.Require(InteractivesReadPermission, api.GetInteractiveHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/files", auth.Require(InteractivesReadPermission, api.GetInteractiveFilesHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/events", auth.Require(InteractivesReadPermission, api.InteractiveEventsHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/content/{path:.*}", auth.Require(InteractivesReadPermission, api.GetInteractiveContentHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/thumbnails/{name}", auth.Require(InteractivesReadPermission, api.GetInteractiveThumbnailHandler)).Methods(http.MethodGet)
 			r.HandleFunc("/v1/interactives/{id}", auth.Require(InteractivesUpdatePermission, api.UpdateInteractiveHandler)).Methods(http.MethodPut)
 			r.HandleFunc("/v1/interactives/{id}", auth.Require(InteractivesUpdatePermission, api.PatchInteractiveHandler)).Methods(http.MethodPatch)
 			r.HandleFunc("/v1/interactives/{id}", auth.Require(InteractivesDeletePermission, api.DeleteInteractivesHandler)).Methods(http.MethodDelete)
 			r.HandleFunc("/v1/collection/{id}", auth.Require(InteractivesUpdatePermission, api.PublishCollectionHandler)).Methods(http.MethodPatch)
+
+			paginator := pagination.NewPaginator(respond, cfg.DefaultLimit, cfg.DefaultOffset, cfg.DefaultMaxLimit)
+			r.HandleFunc("/v1/dead-letters", auth.Require(InteractivesReadPermission, paginator.Paginate(api.ListDeadLettersHandler))).Methods(http.MethodGet)
+			r.HandleFunc("/v1/dead-letters/{id}", auth.Require(InteractivesReadPermission, api.GetDeadLetterHandler)).Methods(http.MethodGet)
+			r.HandleFunc("/v1/dead-letters/{id}/replay", auth.Require(InteractivesUpdatePermission, api.ReplayDeadLetterHandler)).Methods(http.MethodPost)
+			r.HandleFunc("/v1/dead-letters/{id}", auth.Require(InteractivesDeletePermission, api.DiscardDeadLetterHandler)).Methods(http.MethodDelete)
 		} else {
 			r.HandleFunc("/v1/interactives", api.ListInteractivesHandler).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/resource/{resource_id}", api.GetInteractiveByResourceIDHandler).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/resolve/{path:.*}", api.ResolveInteractiveHandler).Methods(http.MethodGet)
 			r.HandleFunc("/v1/interactives/{id}", api.GetInteractiveHandler).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/files", api.GetInteractiveFilesHandler).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/content/{path:.*}", api.GetInteractiveContentHandler).Methods(http.MethodGet)
+			r.HandleFunc("/v1/interactives/{id}/thumbnails/{name}", api.GetInteractiveThumbnailHandler).Methods(http.MethodGet)
 		}
 	} else {
 		log.Error(ctx, "api setup error - no router", nil)
@@ -95,13 +166,48 @@ func Setup(ctx context.Context,
 	return api
 }
 
+// StartOutboxRelay starts relaying pending events from the outbox to kafka (no-op without a producer)
+func (api *API) StartOutboxRelay(ctx context.Context) {
+	if api.outbox != nil {
+		api.outbox.Start(ctx)
+	}
+}
+
 // Close is called during graceful shutdown to give the API an opportunity to perform any required disposal task
-func (*API) Close(ctx context.Context) error {
+func (api *API) Close(ctx context.Context) error {
+	api.outbox.Stop()
 	log.Info(ctx, "graceful shutdown of api complete")
 	return nil
 }
 
-func (api *API) uploadFile(tmpFileName, name string) (string, error) {
+// UploadsChecker reports how many upload slots are in use and how many uploads are queued for one
+func (api *API) UploadsChecker(ctx context.Context, state *healthcheck.CheckState) error {
+	return api.uploads.Checker(ctx, state)
+}
+
+// acquireUpload takes an upload slot, turning the request away with a 429 if none comes free
+func (api *API) acquireUpload(ctx context.Context, w http.ResponseWriter) (*limiter.Slot, bool) {
+	slot, err := api.uploads.Acquire(ctx)
+	if err != nil {
+		api.turnAwayUpload(ctx, w, err)
+		return nil, false
+	}
+	return slot, true
+}
+
+// turnAwayUpload responds to an upload that could not take a slot. Only a full queue, or a wait in it that
+// timed out, is a 429 - a request whose context ended while queued has no one left to tell.
+func (api *API) turnAwayUpload(ctx context.Context, w http.ResponseWriter, err error) {
+	if !errors.Is(err, limiter.ErrFull) && !errors.Is(err, limiter.ErrQueueTimeout) {
+		log.Info(ctx, "upload abandoned waiting for a slot", log.Data{"reason": err.Error()})
+		return
+	}
+	log.Warn(ctx, "upload turned away", log.Data{"reason": err.Error(), "utilisation": api.uploads.Utilisation().String()})
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(api.uploads.RetryAfter().Seconds()))))
+	api.respond.Error(ctx, w, http.StatusTooManyRequests, err)
+}
+
+func (api *API) uploadFile(id, tmpFileName, name string) (string, error) {
 	err := api.s3.ValidateBucket()
 	if err != nil {
 		return "", fmt.Errorf("invalid s3 bucket %w", err)
@@ -112,8 +218,15 @@ func (api *API) uploadFile(tmpFileName, name string) (string, error) {
 		return "", fmt.Errorf("cannot open zipfile %w", err)
 	}
 
+	defer localFile.Close()
+
+	var body io.Reader = localFile
+	if info, err := localFile.Stat(); err == nil {
+		body = api.progress.Reader(id, localFile, info.Size())
+	}
+
 	uniqueS3Key := fmt.Sprintf("%s/%s", api.newUUID(""), name)
-	_, err = api.s3.Upload(&s3manager.UploadInput{Body: localFile, Key: &uniqueS3Key})
+	_, err = api.s3.Upload(&s3manager.UploadInput{Body: body, Key: &uniqueS3Key})
 	if err != nil {
 		return "", fmt.Errorf("s3 upload error %w", err)
 	}
@@ -121,6 +234,24 @@ func (api *API) uploadFile(tmpFileName, name string) (string, error) {
 	return uniqueS3Key, nil
 }
 
+// uploader identifies who made the request, from the user token if there is one
+func (api *API) uploader(r *http.Request) string {
+	return api.identity(r).UserID
+}
+
+// identity is the entity in the user token if there is one, otherwise the user or service the request was made as
+func (api *API) identity(r *http.Request) *permsdk.EntityData {
+	if token := strings.TrimPrefix(r.Header.Get(request.AuthHeaderKey), request.BearerPrefix); token != "" && api.auth != nil {
+		if entity, err := api.auth.Parse(token); err == nil && entity != nil {
+			return entity
+		}
+	}
+	if request.IsUserPresent(r.Context()) {
+		return &permsdk.EntityData{UserID: request.User(r.Context())}
+	}
+	return &permsdk.EntityData{UserID: request.Caller(r.Context())}
+}
+
 func (api *API) blockAccess(i *models.Interactive) bool {
 	if i == nil {
 		return true
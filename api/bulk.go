@@ -0,0 +1,254 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// the operations of a bulk request
+const (
+	BulkLink       = "link"
+	BulkUnlink     = "unlink"
+	BulkDelete     = "delete"
+	BulkRestore    = "restore"
+	BulkRedispatch = "redispatch"
+)
+
+var (
+	errNoBulkOperations    = errors.New("no operations given")
+	errNotRedispatchable   = errors.New("only an interactive whose archive failed to dispatch can be dispatched again")
+	errBulkPermission      = errors.New("permission denied")
+	errUnknownBulkOp       = errors.New("unknown operation")
+	errMissingBulkID       = errors.New("no interactive id given")
+	errMissingCollection   = errors.New("no collection id to link to")
+	errBulkInteractiveGone = errors.New("interactive either deleted or does not exist")
+)
+
+// BulkRequest is a list of operations, each on a single interactive
+type BulkRequest struct {
+	Operations []*BulkOperation `json:"operations"`
+}
+
+type BulkOperation struct {
+	Op           string `json:"op"`
+	ID           string `json:"id"`
+	CollectionID string `json:"collection_id,omitempty"`
+}
+
+// BulkResult is the outcome of an operation, with the status the single interactive endpoint would have responded with
+type BulkResult struct {
+	Op     string `json:"op"`
+	ID     string `json:"id"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Results []*BulkResult `json:"results"`
+}
+
+// bulkCaller is what an operation needs to know about who asked for it, worked out before the operations run
+type bulkCaller struct {
+	canDelete  bool
+	uploadedBy string
+}
+
+// BulkInteractivesHandler runs the operations of a bulk request a few at a time, responding with a result for each in
+// the order they were given. Operations on the same interactive run one after another, in that order.
+func (api *API) BulkInteractivesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("cannot unmarshal request body %w", err))
+		return
+	}
+	if len(req.Operations) == 0 {
+		api.respond.Error(ctx, w, http.StatusBadRequest, errNoBulkOperations)
+		return
+	}
+	if max := api.cfg.BulkMaxOperations; max > 0 && len(req.Operations) > max {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("%d operations given, at most %d are allowed", len(req.Operations), max))
+		return
+	}
+	log.Info(ctx, "bulk interactives", log.Data{"operations": len(req.Operations)})
+
+	caller := bulkCaller{
+		canDelete:  api.hasPermission(r, InteractivesDeletePermission),
+		uploadedBy: api.uploader(r),
+	}
+
+	// group the operations by interactive, keeping the groups in the order each interactive first appears
+	var groups [][]int
+	byID := map[string]int{}
+	for n, op := range req.Operations {
+		if op == nil {
+			op = &BulkOperation{}
+			req.Operations[n] = op
+		}
+		g, ok := byID[op.ID]
+		if !ok || op.ID == "" {
+			g = len(groups)
+			byID[op.ID] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], n)
+	}
+
+	workers := api.cfg.BulkConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+
+	results := make([]*BulkResult, len(req.Operations))
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for n := 0; n < workers; n++ {
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				for _, i := range group {
+					results[i] = api.runBulkOperation(ctx, caller, req.Operations[i])
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+
+	api.respond.JSON(ctx, w, http.StatusOK, BulkResponse{Results: results})
+}
+
+func (api *API) runBulkOperation(ctx context.Context, caller bulkCaller, op *BulkOperation) *BulkResult {
+	status, err := api.bulkOperation(ctx, caller, op)
+	result := &BulkResult{Op: op.Op, ID: op.ID, Status: status}
+	if err != nil {
+		log.Error(ctx, "bulk operation failed", err, log.Data{"op": op.Op, "id": op.ID, "status": status})
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (api *API) bulkOperation(ctx context.Context, caller bulkCaller, op *BulkOperation) (int, error) {
+	if op.ID == "" {
+		return http.StatusBadRequest, errMissingBulkID
+	}
+	switch op.Op {
+	case BulkLink:
+		if op.CollectionID == "" {
+			return http.StatusBadRequest, errMissingCollection
+		}
+		return api.linkToCollection(ctx, op.ID, op.CollectionID)
+	case BulkUnlink:
+		return api.linkToCollection(ctx, op.ID, "")
+	case BulkDelete:
+		if !caller.canDelete {
+			return http.StatusForbidden, fmt.Errorf("%w: %s needs %s", errBulkPermission, op.Op, InteractivesDeletePermission)
+		}
+		return api.deleteInteractive(ctx, op.ID)
+	case BulkRestore:
+		if !caller.canDelete {
+			return http.StatusForbidden, fmt.Errorf("%w: %s needs %s", errBulkPermission, op.Op, InteractivesDeletePermission)
+		}
+		return api.restoreInteractive(ctx, op.ID)
+	case BulkRedispatch:
+		return api.redispatch(ctx, op.ID, caller.uploadedBy)
+	}
+	return http.StatusBadRequest, fmt.Errorf("%w %q", errUnknownBulkOp, op.Op)
+}
+
+// activeInteractive fetches an interactive that has not been deleted
+func (api *API) activeInteractive(ctx context.Context, id string) (*models.Interactive, int, error) {
+	i, err := api.mongoDB.GetInteractive(ctx, id)
+	if err != nil && err != mongo.ErrNoRecordFound {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", id, err)
+	}
+	if api.blockAccess(i) {
+		return nil, http.StatusNotFound, fmt.Errorf("%w %s", errBulkInteractiveGone, id)
+	}
+	return i, http.StatusOK, nil
+}
+
+// linkToCollection links an interactive to a collection, or unlinks it given no collection
+func (api *API) linkToCollection(ctx context.Context, id, collectionID string) (int, error) {
+	i, status, err := api.activeInteractive(ctx, id)
+	if err != nil {
+		return status, err
+	}
+	if i.Metadata == nil {
+		i.Metadata = &models.Metadata{}
+	}
+	i.Metadata.CollectionID = collectionID
+	if err = api.mongoDB.PatchInteractive(ctx, interactives.LinkToCollection, i); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("error patching interactive %s %w", id, err)
+	}
+	return http.StatusOK, nil
+}
+
+// restoreInteractive makes a deleted interactive active again, so long as no other interactive has taken its slugs
+func (api *API) restoreInteractive(ctx context.Context, id string) (int, error) {
+	i, err := api.mongoDB.GetInteractive(ctx, id)
+	if (i == nil && err == nil) || err == mongo.ErrNoRecordFound {
+		return http.StatusNotFound, fmt.Errorf("interactive-id (%s) does not exist", id)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", id, err)
+	}
+	if i.Active != nil && *i.Active {
+		return http.StatusOK, nil
+	}
+
+	if i.Metadata != nil {
+		slugs := []string{i.Metadata.HumanReadableSlug}
+		for _, t := range i.Metadata.Translations {
+			if t != nil {
+				slugs = append(slugs, t.HumanReadableSlug)
+			}
+		}
+		for _, slug := range slugs {
+			if status, err := api.checkSlugFree(ctx, id, slug); err != nil {
+				return status, err
+			}
+		}
+	}
+
+	err = api.mongoDB.UpsertInteractive(ctx, id, &models.Interactive{Active: &enabled})
+	if errors.Is(err, mongo.ErrSlugTaken) {
+		return http.StatusConflict, fmt.Errorf("%w: %s", ErrSlugTaken, i.Metadata.HumanReadableSlug)
+	}
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("unable to set active flag %s %w", id, err)
+	}
+	return http.StatusOK, nil
+}
+
+// redispatch queues the event for the importer again for an archive that reached s3 but was never queued
+func (api *API) redispatch(ctx context.Context, id, uploadedBy string) (int, error) {
+	i, status, err := api.activeInteractive(ctx, id)
+	if err != nil {
+		return status, err
+	}
+	if i.State != models.ArchiveDispatchFailed.String() || i.Archive == nil || i.Archive.Name == "" || i.Metadata == nil {
+		return http.StatusConflict, fmt.Errorf("%w (%s is %s)", errNotRedispatchable, id, i.State)
+	}
+	if err = api.dispatch(ctx, i, i.Archive.Name, uploadedBy); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusAccepted, nil
+}
@@ -0,0 +1,221 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkInteractives(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	stored := func(id string) *models.Interactive {
+		i := &models.Interactive{
+			ID:        id,
+			Active:    &on,
+			Published: &off,
+			State:     models.ImportSuccess.String(),
+			Archive:   &models.Archive{Name: "key/archive.zip"},
+			Metadata:  &models.Metadata{Title: id, Label: id, HumanReadableSlug: id, CollectionID: "collection"},
+		}
+		switch id {
+		case "published":
+			i.Published = &on
+		case "deleted", "deleted-slug-taken":
+			i.Active = &off
+		case "dispatch-failed":
+			i.State = models.ArchiveDispatchFailed.String()
+		}
+		return i
+	}
+	newMongo := func() *apiMock.MongoServerMock {
+		return &apiMock.MongoServerMock{
+			GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+				if id == "missing" {
+					return nil, mongo.ErrNoRecordFound
+				}
+				return stored(id), nil
+			},
+			GetInteractiveBySlugFunc: func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+				if slug == "deleted-slug-taken" {
+					return &models.Interactive{ID: "other-id"}, nil
+				}
+				return nil, mongo.ErrNoRecordFound
+			},
+			PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+				return nil
+			},
+			UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error {
+				return nil
+			},
+		}
+	}
+	send := func(a *api.API, body string) (*httptest.ResponseRecorder, api.BulkResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/interactives/bulk", strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, req)
+		var results api.BulkResponse
+		if resp.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &results))
+		}
+		return resp, results
+	}
+	cfg := &config.Config{PublishingEnabled: true, BulkConcurrency: 4, BulkMaxOperations: 20}
+
+	t.Run("WhenOperationsGiven_ThenResultForEachInOrder", func(t *testing.T) {
+		mongoServer := newMongo()
+		a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+		resp, body := send(a, `{"operations":[
+			{"op":"link","id":"one","collection_id":"new-collection"},
+			{"op":"unlink","id":"two"},
+			{"op":"delete","id":"three"},
+			{"op":"restore","id":"deleted"},
+			{"op":"redispatch","id":"dispatch-failed"},
+			{"op":"link","id":"four"},
+			{"op":"delete"},
+			{"op":"archive","id":"five"},
+			{"op":"unlink","id":"missing"},
+			{"op":"delete","id":"published"},
+			{"op":"redispatch","id":"six"},
+			{"op":"restore","id":"deleted-slug-taken"}
+		]}`)
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		expected := []struct {
+			op, id string
+			status int
+		}{
+			{api.BulkLink, "one", http.StatusOK},
+			{api.BulkUnlink, "two", http.StatusOK},
+			{api.BulkDelete, "three", http.StatusNoContent},
+			{api.BulkRestore, "deleted", http.StatusOK},
+			{api.BulkRedispatch, "dispatch-failed", http.StatusAccepted},
+			{api.BulkLink, "four", http.StatusBadRequest},
+			{api.BulkDelete, "", http.StatusBadRequest},
+			{"archive", "five", http.StatusBadRequest},
+			{api.BulkUnlink, "missing", http.StatusNotFound},
+			{api.BulkDelete, "published", http.StatusForbidden},
+			{api.BulkRedispatch, "six", http.StatusConflict},
+			{api.BulkRestore, "deleted-slug-taken", http.StatusConflict},
+		}
+		require.Len(t, body.Results, len(expected))
+		for n, e := range expected {
+			r := body.Results[n]
+			require.Equal(t, e.op, r.Op)
+			require.Equal(t, e.id, r.ID)
+			require.Equal(t, e.status, r.Status, r.Error)
+			require.Equal(t, e.status >= http.StatusBadRequest, r.Error != "")
+		}
+
+		patched := map[string]string{}
+		for _, c := range mongoServer.PatchInteractiveCalls() {
+			switch c.PatchAttribute {
+			case interactives.LinkToCollection:
+				patched[c.Interactive.ID] = c.Interactive.Metadata.CollectionID
+			case interactives.PatchAttribute(mongo.Dispatch):
+				require.Equal(t, "dispatch-failed", c.Interactive.ID)
+				require.Len(t, c.Interactive.Outbox, 1)
+				require.Equal(t, "key/archive.zip", c.Interactive.Outbox[0].FilePath)
+			}
+		}
+		// a published interactive is unlinked, as zebedee would, though it cannot be deleted
+		require.Equal(t, map[string]string{"one": "new-collection", "two": "", "published": ""}, patched)
+
+		active := map[string]bool{}
+		for _, c := range mongoServer.UpsertInteractiveCalls() {
+			active[c.ID] = *c.Vis.Active
+		}
+		require.Equal(t, map[string]bool{"three": false, "deleted": true}, active)
+	})
+
+	t.Run("WhenCallerCannotDelete_ThenDeleteAndRestoreForbidden", func(t *testing.T) {
+		noDelete := denyPermissions(api.InteractivesDeletePermission)
+		mongoServer := newMongo()
+		a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), noDelete, mongoServer, nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+		resp, body := send(a, `{"operations":[{"op":"delete","id":"one"},{"op":"restore","id":"deleted"},{"op":"link","id":"two","collection_id":"c"}]}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		require.Equal(t, http.StatusForbidden, body.Results[0].Status)
+		require.Equal(t, http.StatusForbidden, body.Results[1].Status)
+		require.Equal(t, http.StatusOK, body.Results[2].Status)
+		require.Empty(t, mongoServer.UpsertInteractiveCalls())
+	})
+
+	t.Run("WhenRequestInvalid_ThenBadRequest", func(t *testing.T) {
+		a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, newMongo(), nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+		tooMany := make([]string, cfg.BulkMaxOperations+1)
+		for n := range tooMany {
+			tooMany[n] = `{"op":"unlink","id":"one"}`
+		}
+		for _, body := range []string{
+			`{"operations":[]}`,
+			`{"operations":[` + strings.Join(tooMany, ",") + `]}`,
+			`{"operations":`,
+		} {
+			resp, _ := send(a, body)
+			require.Equal(t, http.StatusBadRequest, resp.Code, body)
+		}
+	})
+
+	t.Run("WhenManyOperations_ThenRunConcurrentlyWithinBound", func(t *testing.T) {
+		var running, most int32
+		var mu sync.Mutex
+		var order []string
+		mongoServer := newMongo()
+		mongoServer.GetInteractiveFunc = func(ctx context.Context, id string) (*models.Interactive, error) {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&most)
+				if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return stored(id), nil
+		}
+		mongoServer.PatchInteractiveFunc = func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+			if ix.ID == "same" {
+				mu.Lock()
+				order = append(order, ix.Metadata.CollectionID)
+				mu.Unlock()
+			}
+			return nil
+		}
+		a := api.Setup(ctx, &config.Config{PublishingEnabled: true, BulkConcurrency: 3, BulkMaxOperations: 20}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+		ops := []string{`{"op":"link","id":"same","collection_id":"first"}`}
+		for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			ops = append(ops, `{"op":"link","id":"`+id+`","collection_id":"c"}`)
+		}
+		ops = append(ops, `{"op":"link","id":"same","collection_id":"second"}`, `{"op":"unlink","id":"same"}`)
+		resp, body := send(a, `{"operations":[`+strings.Join(ops, ",")+`]}`)
+		require.Equal(t, http.StatusOK, resp.Code)
+		for _, r := range body.Results {
+			require.Equal(t, http.StatusOK, r.Status, r.Error)
+		}
+		require.LessOrEqual(t, atomic.LoadInt32(&most), int32(3))
+		require.Equal(t, []string{"first", "second", ""}, order)
+	})
+}
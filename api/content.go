@@ -0,0 +1,129 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+var (
+	errContentUnavailable = errors.New("interactive content is not available")
+	errInvalidContentPath = errors.New("invalid content path")
+)
+
+// GetInteractiveContentHandler serves a file from an imported interactive, the entry point if no path is given.
+// The interactive is looked up with the same access rules as GetInteractive, so unpublished content can be
+// previewed in publishing mode but is never served in web mode.
+func (api *API) GetInteractiveContentHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.content == nil {
+		api.respond.Error(ctx, w, http.StatusNotFound, errContentUnavailable)
+		return
+	}
+
+	interactive, status, err := api.GetInteractive(ctx, r)
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	if interactive.State != models.ImportSuccess.String() || interactive.Archive == nil || interactive.Archive.UploadRootDirectory == "" {
+		api.respond.Error(ctx, w, http.StatusNotFound, errContentUnavailable)
+		return
+	}
+
+	file, ok := contentPath(mux.Vars(r)["path"], interactive)
+	if !ok {
+		api.respond.Error(ctx, w, http.StatusBadRequest, errInvalidContentPath)
+		return
+	}
+	key := path.Join(strings.Trim(interactive.Archive.UploadRootDirectory, "/"), file)
+
+	object, err := api.content.Get(ctx, key, &content.GetOptions{
+		Range:           r.Header.Get("Range"),
+		IfNoneMatch:     r.Header.Get("If-None-Match"),
+		IfModifiedSince: r.Header.Get("If-Modified-Since"),
+	})
+	switch {
+	case err == content.ErrNotFound:
+		api.respond.Error(ctx, w, http.StatusNotFound, fmt.Errorf("%s not found in interactive %s", file, interactive.ID))
+		return
+	case err == content.ErrNotModified:
+		w.Header().Set("Cache-Control", api.contentCacheControl(interactive))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	case err == content.ErrInvalidRange:
+		api.respond.Error(ctx, w, http.StatusRequestedRangeNotSatisfiable, err)
+		return
+	case err != nil:
+		api.respond.Error(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	defer object.Body.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", contentType(file, object.ContentType))
+	header.Set("Content-Length", strconv.FormatInt(object.ContentLength, 10))
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Cache-Control", api.contentCacheControl(interactive))
+	header.Set("X-Content-Type-Options", "nosniff")
+	if object.ETag != "" {
+		header.Set("ETag", object.ETag)
+	}
+	if !object.LastModified.IsZero() {
+		header.Set("Last-Modified", object.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	status = http.StatusOK
+	if object.ContentRange != "" {
+		header.Set("Content-Range", object.ContentRange)
+		status = http.StatusPartialContent
+	}
+	w.WriteHeader(status)
+	if _, err = io.Copy(w, object.Body); err != nil {
+		log.Error(ctx, "error writing interactive content", err, log.Data{"id": interactive.ID, "file": file})
+	}
+}
+
+// contentPath is the file requested relative to the root of the archive, refusing anything that would escape it
+func contentPath(requested string, i *models.Interactive) (string, bool) {
+	if requested == "" {
+		if i.Metadata != nil && i.Metadata.EntryPoint != "" {
+			return i.Metadata.EntryPoint, true
+		}
+		return models.DefaultEntryPoint, true
+	}
+	cleaned := path.Clean("/" + requested)[1:]
+	if cleaned == "" || cleaned != requested {
+		return "", false
+	}
+	return cleaned, true
+}
+
+// contentType goes by extension first, as files are not always stored with the right type
+func contentType(file, stored string) string {
+	if t := mime.TypeByExtension(path.Ext(file)); t != "" {
+		return t
+	}
+	if stored != "" {
+		return stored
+	}
+	return "application/octet-stream"
+}
+
+// contentCacheControl lets published content be cached, but makes previews revalidate every time
+func (api *API) contentCacheControl(i *models.Interactive) string {
+	if api.cfg.PublishingEnabled || i.Published == nil || !*i.Published {
+		return "private, no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d", int(api.cfg.ContentCacheMaxAge.Seconds()))
+}
@@ -0,0 +1,126 @@
+package api_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInteractiveContentHandler(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	interactive := func(state models.State, published bool) func(ctx context.Context, id string) (*models.Interactive, error) {
+		return func(ctx context.Context, id string) (*models.Interactive, error) {
+			return &models.Interactive{
+				ID:        id,
+				State:     state.String(),
+				Active:    &on,
+				Published: &published,
+				Archive:   &models.Archive{UploadRootDirectory: "/root-dir/"},
+				Metadata:  &models.Metadata{EntryPoint: "charts/index.html"},
+			}, nil
+		}
+	}
+	object := func(body, contentRange string) *content.Object {
+		return &content.Object{
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentType:   "binary/octet-stream",
+			ContentLength: int64(len(body)),
+			ContentRange:  contentRange,
+			ETag:          `"etag"`,
+			LastModified:  modified,
+		}
+	}
+
+	tests := []struct {
+		title                string
+		publishing           bool
+		published            bool
+		state                models.State
+		uri                  string
+		header               string
+		object               *content.Object
+		err                  error
+		expectedCode         int
+		expectedKey          string
+		expectedType         string
+		expectedCacheControl string
+		expectedBody         string
+	}{
+		{"WhenPublished_ThenServedAndCacheable", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/js/app.js", "", object("app", ""), nil, http.StatusOK, "root-dir/js/app.js", "text/javascript; charset=utf-8", "public, max-age=300", "app"},
+		{"WhenNoPath_ThenEntryPointServed", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/", "", object("<html>", ""), nil, http.StatusOK, "root-dir/charts/index.html", "text/html; charset=utf-8", "public, max-age=300", "<html>"},
+		{"WhenUnpublishedInWeb_ThenNotFound", false, false, models.ImportSuccess, "/v1/interactives/an-id/content/index.html", "", nil, nil, http.StatusNotFound, "", "", "", ""},
+		{"WhenUnpublishedInPublishing_ThenPreviewed", true, false, models.ImportSuccess, "/v1/interactives/an-id/content/index.html", "", object("<html>", ""), nil, http.StatusOK, "root-dir/index.html", "text/html; charset=utf-8", "private, no-cache", "<html>"},
+		{"WhenNotImported_ThenNotFound", true, false, models.ArchiveUploaded, "/v1/interactives/an-id/content/index.html", "", nil, nil, http.StatusNotFound, "", "", "", ""},
+		{"WhenRangeRequested_ThenPartialContent", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/data.json", "bytes=0-1", object("[1", "bytes 0-1/10"), nil, http.StatusPartialContent, "root-dir/data.json", "application/json", "public, max-age=300", "[1"},
+		{"WhenUnknownExtension_ThenStoredType", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/data.unknown-ext", "", object("x", ""), nil, http.StatusOK, "root-dir/data.unknown-ext", "binary/octet-stream", "public, max-age=300", "x"},
+		{"WhenFileMissing_ThenNotFound", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/missing.js", "", nil, content.ErrNotFound, http.StatusNotFound, "root-dir/missing.js", "", "", ""},
+		{"WhenUnchanged_ThenNotModified", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/index.html", `"etag"`, nil, content.ErrNotModified, http.StatusNotModified, "root-dir/index.html", "", "public, max-age=300", ""},
+		{"WhenRangeInvalid_ThenNotSatisfiable", false, true, models.ImportSuccess, "/v1/interactives/an-id/content/index.html", "bytes=99-", nil, content.ErrInvalidRange, http.StatusRequestedRangeNotSatisfiable, "root-dir/index.html", "", "", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			store := &apiMock.ContentStoreMock{
+				GetFunc: func(ctx context.Context, key string, opts *content.GetOptions) (*content.Object, error) {
+					return tc.object, tc.err
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{GetInteractiveFunc: interactive(tc.state, tc.published)}
+			cfg := &config.Config{PublishingEnabled: tc.publishing, ContentCacheMaxAge: 5 * time.Minute}
+			a := api.Setup(context.Background(), cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, store, noopGen, noopGen, noopGen, respondr)
+
+			req := httptest.NewRequest(http.MethodGet, tc.uri, nil)
+			if strings.HasPrefix(tc.header, "bytes") {
+				req.Header.Set("Range", tc.header)
+			} else if tc.header != "" {
+				req.Header.Set("If-None-Match", tc.header)
+			}
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code)
+
+			if tc.expectedKey == "" {
+				require.Empty(t, store.GetCalls())
+				return
+			}
+			require.Len(t, store.GetCalls(), 1)
+			call := store.GetCalls()[0]
+			require.Equal(t, tc.expectedKey, call.Key)
+			require.Equal(t, req.Header.Get("Range"), call.Opts.Range)
+			require.Equal(t, req.Header.Get("If-None-Match"), call.Opts.IfNoneMatch)
+			require.Equal(t, tc.expectedCacheControl, resp.Header().Get("Cache-Control"))
+			if tc.object == nil {
+				return
+			}
+			require.Equal(t, tc.expectedType, resp.Header().Get("Content-Type"))
+			require.Equal(t, tc.expectedBody, resp.Body.String())
+			require.Equal(t, `"etag"`, resp.Header().Get("ETag"))
+			require.Equal(t, modified.Format(http.TimeFormat), resp.Header().Get("Last-Modified"))
+			require.Equal(t, "bytes", resp.Header().Get("Accept-Ranges"))
+			require.Equal(t, tc.object.ContentRange, resp.Header().Get("Content-Range"))
+		})
+	}
+
+	t.Run("WhenNoContentStore_ThenNotFound", func(t *testing.T) {
+		mongoServer := &apiMock.MongoServerMock{GetInteractiveFunc: interactive(models.ImportSuccess, true)}
+		a := api.Setup(context.Background(), &config.Config{}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/interactives/an-id/content/index.html", nil))
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// ListDeadLettersHandler returns a page of upload events that could not be delivered to the importer
+func (api *API) ListDeadLettersHandler(r *http.Request, limit int, offset int) (interface{}, int, error) {
+	ctx := r.Context()
+	log.Info(ctx, "list dead letters", log.Data{"limit": limit, "offset": offset})
+
+	deadLetters, total, err := api.mongoDB.ListDeadLetters(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing dead letters %w", err)
+	}
+	return deadLetters, total, nil
+}
+
+func (api *API) GetDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	d, status, err := api.getDeadLetter(ctx, r)
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+
+	api.respond.JSON(ctx, w, http.StatusOK, d)
+}
+
+// ReplayDeadLetterHandler sends the dead lettered event again, exactly as the relay would have
+func (api *API) ReplayDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	d, status, err := api.getDeadLetter(ctx, r)
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	logData := log.Data{"event_id": d.ID, "interactive_id": d.InteractiveID}
+	log.Info(ctx, "replay dead letter", logData)
+
+	if api.producer == nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, errors.New("no kafka producer to replay with"))
+		return
+	}
+
+	if err = api.producer.InteractiveUploaded(ctx, event.NewInteractiveUploaded(d.Event)); err != nil {
+		d.Attempts++
+		d.Error = err.Error()
+		if dbErr := api.mongoDB.UpsertDeadLetter(ctx, d); dbErr != nil {
+			log.Error(ctx, "error recording failed replay", dbErr, logData)
+		}
+		api.respond.Error(ctx, w, http.StatusBadGateway, fmt.Errorf("error replaying event %s %w", d.ID, err))
+		return
+	}
+
+	// the importer dedupes on event id, so a dead letter that lingers after a replay is harmless
+	if err = api.mongoDB.DeleteDeadLetter(ctx, d.ID); err != nil && err != mongo.ErrNoRecordFound {
+		log.Error(ctx, "error removing replayed dead letter", err, logData)
+	}
+
+	// only move the interactive on if nothing newer has happened to it
+	ix, err := api.mongoDB.GetInteractive(ctx, d.InteractiveID)
+	if err != nil && err != mongo.ErrNoRecordFound {
+		log.Error(ctx, "error fetching interactive for replayed dead letter", err, logData)
+	}
+	if ix != nil && ix.State == models.ArchiveDispatchFailed.String() {
+		ix.State = models.ArchiveDispatchedToImporter.String()
+		if err = api.mongoDB.PatchInteractive(ctx, interactives.PatchAttribute(mongo.State), ix); err != nil {
+			log.Error(ctx, fmt.Sprintf("error updating mongo for interactive [%s], State [%s]", ix.ID, ix.State), err, logData)
+		}
+	}
+
+	api.respond.JSON(ctx, w, http.StatusAccepted, nil)
+}
+
+// DiscardDeadLetterHandler drops an event that will never be replayed
+func (api *API) DiscardDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := mux.Vars(r)["id"]
+	log.Info(ctx, "discard dead letter", log.Data{"event_id": id})
+
+	err := api.mongoDB.DeleteDeadLetter(ctx, id)
+	if err == mongo.ErrNoRecordFound {
+		api.respond.Error(ctx, w, http.StatusNotFound, fmt.Errorf("dead letter %s does not exist", id))
+		return
+	}
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("error discarding dead letter %s %w", id, err))
+		return
+	}
+
+	api.respond.JSON(ctx, w, http.StatusNoContent, nil)
+}
+
+func (api *API) getDeadLetter(ctx context.Context, r *http.Request) (*models.DeadLetter, int, error) {
+	id := mux.Vars(r)["id"]
+	d, err := api.mongoDB.GetDeadLetter(ctx, id)
+	if err == mongo.ErrNoRecordFound || (d == nil && err == nil) {
+		return nil, http.StatusNotFound, fmt.Errorf("dead letter %s does not exist", id)
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error fetching dead letter %s %w", id, err)
+	}
+	return d, http.StatusOK, nil
+}
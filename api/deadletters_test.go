@@ -0,0 +1,133 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	kafka "github.com/ONSdigital/dp-kafka/v3"
+	kMock "github.com/ONSdigital/dp-kafka/v3/kafkatest"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func newDeadLetterMongoMock(state models.State) *apiMock.MongoServerMock {
+	return &apiMock.MongoServerMock{
+		ListDeadLettersFunc: func(ctx context.Context, offset, limit int) ([]*models.DeadLetter, int, error) {
+			return []*models.DeadLetter{{ID: "event-id"}}, 1, nil
+		},
+		GetDeadLetterFunc: func(ctx context.Context, id string) (*models.DeadLetter, error) {
+			if id != "event-id" {
+				return nil, mongo.ErrNoRecordFound
+			}
+			return models.NewDeadLetter(&models.OutboxEvent{ID: id, InteractiveID: "an-id", FilePath: "path"}, 10, errors.New("kafka error")), nil
+		},
+		UpsertDeadLetterFunc: func(ctx context.Context, d *models.DeadLetter) error { return nil },
+		DeleteDeadLetterFunc: func(ctx context.Context, id string) error {
+			if id != "event-id" {
+				return mongo.ErrNoRecordFound
+			}
+			return nil
+		},
+		GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+			return &models.Interactive{ID: id, Active: &on, State: state.String()}, nil
+		},
+		PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, i *models.Interactive) error {
+			return nil
+		},
+	}
+}
+
+func TestDeadLetterHandlers(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	cfg := &config.Config{PublishingEnabled: true, DefaultLimit: 20, DefaultMaxLimit: 100, KafkaSendTimeout: 10 * time.Millisecond}
+	setup := func(mongoServer api.MongoServer, output chan []byte) *api.API {
+		kafkaProducer := &kMock.IProducerMock{
+			ChannelsFunc: func() *kafka.ProducerChannels { return &kafka.ProducerChannels{Output: output} },
+		}
+		return api.Setup(context.Background(), cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, kafkaProducer, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+	}
+	serve := func(a *api.API, method, uri string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, httptest.NewRequest(method, uri, nil))
+		return resp
+	}
+
+	t.Run("WhenListed_ThenPageOfDeadLetters", func(t *testing.T) {
+		resp := serve(setup(newDeadLetterMongoMock(models.ArchiveDispatchFailed), nil), http.MethodGet, "/v1/dead-letters?limit=5")
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var page struct {
+			Items      []*models.DeadLetter `json:"items"`
+			TotalCount int                  `json:"total_count"`
+			Limit      int                  `json:"limit"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &page))
+		require.Len(t, page.Items, 1)
+		require.Equal(t, 1, page.TotalCount)
+		require.Equal(t, 5, page.Limit)
+	})
+
+	t.Run("WhenInspected_ThenStatusOKOrNotFound", func(t *testing.T) {
+		a := setup(newDeadLetterMongoMock(models.ArchiveDispatchFailed), nil)
+
+		resp := serve(a, http.MethodGet, "/v1/dead-letters/event-id")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var d models.DeadLetter
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &d))
+		require.Equal(t, "kafka error", d.Error)
+		require.Equal(t, 10, d.Attempts)
+
+		require.Equal(t, http.StatusNotFound, serve(a, http.MethodGet, "/v1/dead-letters/other-id").Code)
+	})
+
+	t.Run("WhenReplayed_ThenSentDeletedAndStateMovedOn", func(t *testing.T) {
+		mongoServer := newDeadLetterMongoMock(models.ArchiveDispatchFailed)
+		output := make(chan []byte, 1)
+
+		resp := serve(setup(mongoServer, output), http.MethodPost, "/v1/dead-letters/event-id/replay")
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		require.Len(t, output, 1)
+		require.Len(t, mongoServer.DeleteDeadLetterCalls(), 1)
+		require.Len(t, mongoServer.PatchInteractiveCalls(), 1)
+		require.Equal(t, models.ArchiveDispatchedToImporter.String(), mongoServer.PatchInteractiveCalls()[0].Interactive.State)
+	})
+
+	t.Run("WhenReplayedAfterANewerUpload_ThenStateIsLeftAlone", func(t *testing.T) {
+		mongoServer := newDeadLetterMongoMock(models.ImportSuccess)
+
+		resp := serve(setup(mongoServer, make(chan []byte, 1)), http.MethodPost, "/v1/dead-letters/event-id/replay")
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		require.Len(t, mongoServer.PatchInteractiveCalls(), 0)
+	})
+
+	t.Run("WhenReplayFails_ThenAttemptIsRecorded", func(t *testing.T) {
+		mongoServer := newDeadLetterMongoMock(models.ArchiveDispatchFailed)
+
+		resp := serve(setup(mongoServer, make(chan []byte)), http.MethodPost, "/v1/dead-letters/event-id/replay")
+		require.Equal(t, http.StatusBadGateway, resp.Code)
+		require.Len(t, mongoServer.UpsertDeadLetterCalls(), 1)
+		require.Equal(t, 11, mongoServer.UpsertDeadLetterCalls()[0].D.Attempts)
+		require.Len(t, mongoServer.DeleteDeadLetterCalls(), 0)
+	})
+
+	t.Run("WhenDiscarded_ThenNoContentOrNotFound", func(t *testing.T) {
+		a := setup(newDeadLetterMongoMock(models.ArchiveDispatchFailed), nil)
+		require.Equal(t, http.StatusNoContent, serve(a, http.MethodDelete, "/v1/dead-letters/event-id").Code)
+		require.Equal(t, http.StatusNotFound, serve(a, http.MethodDelete, "/v1/dead-letters/other-id").Code)
+	})
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/internal/progress"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// eventsKeepAlive is how often a comment is written to an idle event stream so proxies keep it open
+const eventsKeepAlive = 15 * time.Second
+
+// notifyingStore announces state changes to event stream subscribers once they have been written
+type notifyingStore struct {
+	MongoServer
+	progress *progress.Broker
+}
+
+func (s *notifyingStore) PatchInteractive(ctx context.Context, attribute interactives.PatchAttribute, i *models.Interactive) error {
+	if err := s.MongoServer.PatchInteractive(ctx, attribute, i); err != nil {
+		return err
+	}
+	switch attribute {
+	case interactives.PatchArchive, interactives.PatchAttribute(mongo.State), interactives.PatchAttribute(mongo.Dispatch):
+		s.progress.State(i.ID, i.State)
+	}
+	return nil
+}
+
+func (s *notifyingStore) CompleteOutboxEvent(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+	if err := s.MongoServer.CompleteOutboxEvent(ctx, e, state); err != nil {
+		return err
+	}
+	s.progress.State(e.InteractiveID, state.String())
+	return nil
+}
+
+// MongoDB is the store the api writes through. Anything else that moves interactives between states
+// (e.g. the import event handler) should use it so that event stream subscribers hear about it.
+func (api *API) MongoDB() MongoServer {
+	return api.mongoDB
+}
+
+// InteractiveEventsHandler streams state changes and upload progress for an interactive as server-sent events.
+// The current state is sent first and the stream ends once the interactive reaches a final state.
+func (api *API) InteractiveEventsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	// subscribe before reading the current state so nothing is missed in between
+	updates, cancel := api.progress.Subscribe(mux.Vars(r)["id"])
+	defer cancel()
+
+	interactive, status, err := api.GetInteractive(ctx, r)
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	current := &progress.Update{Type: progress.TypeState, InteractiveID: interactive.ID, State: interactive.State}
+	if err = writeEvent(w, current); err != nil || isFinalState(current.State) {
+		flusher.Flush()
+		return
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err = fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case u := <-updates:
+			if err = writeEvent(w, u); err != nil {
+				log.Error(ctx, "error writing interactive event", err, log.Data{"id": interactive.ID})
+				return
+			}
+			if u.Type == progress.TypeState && isFinalState(u.State) {
+				flusher.Flush()
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, u *progress.Update) error {
+	data, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", u.Type, data)
+	return err
+}
+
+// isFinalState is true for states an interactive only leaves through another upload
+func isFinalState(state string) bool {
+	switch state {
+	case models.ImportSuccess.String(), models.ImportFailure.String(), models.ArchiveUploadFailed.String(),
+		models.ArchiveDispatchFailed.String(), models.ArchiveQuarantined.String():
+		return true
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,59 +12,145 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/internal/thumbnail"
+	"github.com/ONSdigital/dp-interactives-api/internal/zip"
 	"github.com/ONSdigital/dp-interactives-api/models"
 )
 
 const (
 	UpdateFieldKey      = "interactive"
 	FileFieldKey        = "file"
+	ForceFieldKey       = "force"
+	ThumbnailFieldKey   = "thumbnail"
 	maxUploadFileSizeMb = 2500
 )
 
 type FormDataValidator func(*http.Request) error
 
+// archiveVerifier decides whether an attachment may be stored once its contents are known. It is only called
+// in streaming mode, before the upload to s3 completes - an error abandons the upload and is kept in Rejected.
+type archiveVerifier func(f *FormDataRequest, contents *zip.Contents) error
+
 var (
 	v                                 = validator.New()
 	conform                           = modifiers.New()
 	WantOnlyOneAttachmentWithMetadata = func(r *http.Request) error {
-		numOfAttachments, update := len(r.MultipartForm.File), r.FormValue(UpdateFieldKey)
+		numOfAttachments, update := archiveParts(r.MultipartForm), r.FormValue(UpdateFieldKey)
 		if numOfAttachments == 1 && update != "" {
 			return nil
 		}
 		return errors.New("expecting one attachment with metadata")
 	}
 	WantAtleastMaxOneAttachmentAndOrMetadata = func(r *http.Request) error {
-		numOfAttachments, update := len(r.MultipartForm.File), r.FormValue(UpdateFieldKey)
-		if numOfAttachments == 1 || update != "" {
+		numOfAttachments, update := archiveParts(r.MultipartForm), r.FormValue(UpdateFieldKey)
+		if numOfAttachments == 1 || update != "" || len(r.MultipartForm.File[ThumbnailFieldKey]) > 0 {
 			return nil
 		}
-		return errors.New("no attachment (max one) or metadata present")
+		return errors.New("no attachment (max one), metadata or thumbnail present")
 	}
 )
 
+func init() {
+	// slugs given by hand must follow the same rules as generated ones
+	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return data.IsValidSlug(fl.Field().String())
+	})
+	// english metadata is not a translation
+	v.RegisterValidation("translation", func(fl validator.FieldLevel) bool {
+		lang := fl.Field().String()
+		return lang != data.English && data.IsLanguage(lang)
+	})
+}
+
 type FormDataRequest struct {
-	req                 *http.Request
-	api                 *API
-	Name                string
-	Interactive         *models.Interactive
+	req         *http.Request
+	api         *API
+	Name        string
+	Interactive *models.Interactive
+	// ClearedMetadata are the metadata fields given as null in the update, which an update clears
+	ClearedMetadata     []string
 	isMetadataMandatory bool
 	TmpFileName         string
+	// Force uploads the archive even if it is identical to one already held
+	Force bool
+	// Thumbnail is the image sent in the thumbnail field, which takes the place of one found in the archive
+	Thumbnail []byte
+	// picker looks for a thumbnail in the archive as it is scanned
+	picker *thumbnail.Picker
+
+	verify archiveVerifier
+	// acquire is called once an archive is found, before it is read - an error abandons the request and is kept
+	// in Busy, so that requests without an archive never wait for an upload slot
+	acquire func() error
+	// Busy is why the archive could not be taken on
+	Busy error
+	// Streamed is set in place of TmpFileName when the attachment was sent straight to s3 (streaming mode)
+	Streamed *StreamedArchive
+	// Rejected is why the verifier abandoned a streamed attachment
+	Rejected error
+	// Failed is set when a streamed attachment could not be stored for reasons other than its content
+	Failed error
 }
 
-func newFormDataRequest(req *http.Request, api *API, attachmentValidator FormDataValidator, metadataMandatory bool) (*FormDataRequest, []error) {
+func newFormDataRequest(req *http.Request, api *API, attachmentValidator FormDataValidator, metadataMandatory bool, verify archiveVerifier, acquire func() error) (*FormDataRequest, []error) {
 	f := &FormDataRequest{
 		req:                 req,
 		api:                 api,
 		isMetadataMandatory: metadataMandatory,
+		verify:              verify,
+		acquire:             acquire,
+		picker:              &thumbnail.Picker{},
+	}
+	if api.cfg.UploadStreaming {
+		return f, f.validateStream(attachmentValidator)
 	}
 	return f, f.validate(attachmentValidator)
 }
 
+// hasAttachment is true if a new archive came with the request
+func (f *FormDataRequest) hasAttachment() bool {
+	return f.TmpFileName != "" || f.Streamed != nil
+}
+
+// thumbnail is the image to make thumbnails from - the one sent with the request, or else one found in the
+// archive (uploaded is false)
+func (f *FormDataRequest) thumbnail() (image []byte, uploaded bool) {
+	if f.Thumbnail != nil {
+		return f.Thumbnail, true
+	}
+	return f.picker.Image(), false
+}
+
+// archiveParts counts the files sent, other than the thumbnail
+func archiveParts(form *multipart.Form) int {
+	n := len(form.File)
+	if _, ok := form.File[ThumbnailFieldKey]; ok {
+		n--
+	}
+	return n
+}
+
+// readThumbnail reads the image sent in the thumbnail field, checking that thumbnails can be made from it
+func readThumbnail(r io.Reader) ([]byte, error) {
+	image, err := io.ReadAll(io.LimitReader(r, thumbnail.MaxSize+1))
+	if err != nil {
+		return nil, validatorError(ThumbnailFieldKey, fmt.Sprintf("cannot read thumbnail %s", err.Error()))
+	}
+	if err = thumbnail.Check(image); err != nil {
+		return nil, validatorError(ThumbnailFieldKey, err.Error())
+	}
+	return image, nil
+}
+
 func (f *FormDataRequest) validate(attachmentValidator FormDataValidator) (errs []error) {
 	var err error
 	var tmpfilename, filename string
+	var thumb []byte
 
 	// maxMemory needs to be manageable for containerised envs like Nomad:
 	// 		ParseMultipartForm parses a request body as multipart/form-data.
@@ -76,15 +163,16 @@ func (f *FormDataRequest) validate(attachmentValidator FormDataValidator) (errs
 	}
 
 	if f.req.MultipartForm != nil {
-		if numOfAttach := len(f.req.MultipartForm.File); numOfAttach > 0 {
-			var fileHeader *multipart.FileHeader
-			var fileKey string
-
-			for k, v := range f.req.MultipartForm.File {
+		var fileHeader *multipart.FileHeader
+		var fileKey string
+		for k, v := range f.req.MultipartForm.File {
+			if k != ThumbnailFieldKey {
 				fileHeader = v[0]
 				fileKey = k
 			}
+		}
 
+		if fileHeader != nil {
 			file, _, vErr := f.req.FormFile(fileKey)
 			if vErr != nil {
 				msg := fmt.Sprintf("error reading form data %s", err.Error())
@@ -92,8 +180,9 @@ func (f *FormDataRequest) validate(attachmentValidator FormDataValidator) (errs
 			}
 			defer file.Close()
 
-			if ext := filepath.Ext(fileHeader.Filename); ext != ".zip" {
-				msg := fmt.Sprintf("file extension (%s) should be zip", ext)
+			format, fErr := zip.DetectFormat(fileHeader.Filename)
+			if fErr != nil {
+				msg := fmt.Sprintf("file extension (%s) not supported - %s", filepath.Ext(fileHeader.Filename), fErr.Error())
 				errs = append(errs, validatorError(FileFieldKey, msg))
 			}
 
@@ -102,22 +191,26 @@ func (f *FormDataRequest) validate(attachmentValidator FormDataValidator) (errs
 				errs = append(errs, validatorError(FileFieldKey, msg))
 			}
 
-			tmpZip, err := os.CreateTemp("", "s3-zip_*.zip")
-			if err != nil {
-				msg := fmt.Sprintf("http body read error %s", err.Error())
-				errs = append(errs, validatorError(FileFieldKey, msg))
-			}
-			if _, err = io.Copy(tmpZip, file); err != nil {
-				msg := fmt.Sprintf("http body read error %s", err.Error())
-				errs = append(errs, validatorError(FileFieldKey, msg))
+			if len(errs) == 0 && f.acquire != nil {
+				if f.Busy = f.acquire(); f.Busy != nil {
+					return nil
+				}
 			}
-			if err = tmpZip.Close(); err != nil {
-				msg := fmt.Sprintf("http body read error %s", err.Error())
-				errs = append(errs, validatorError(FileFieldKey, msg))
+			if len(errs) == 0 {
+				tmpfilename, filename, errs = stageAttachment(file, fileHeader.Filename, format, f.api.zipLimits)
 			}
+		}
 
-			tmpfilename = tmpZip.Name()
-			filename = fileHeader.Filename
+		if headers := f.req.MultipartForm.File[ThumbnailFieldKey]; len(headers) > 0 {
+			if file, tErr := headers[0].Open(); tErr != nil {
+				errs = append(errs, validatorError(ThumbnailFieldKey, fmt.Sprintf("cannot read thumbnail %s", tErr.Error())))
+			} else {
+				thumb, tErr = readThumbnail(file)
+				file.Close()
+				if tErr != nil {
+					errs = append(errs, tErr)
+				}
+			}
 		}
 
 		if err = attachmentValidator(f.req); err != nil {
@@ -125,45 +218,126 @@ func (f *FormDataRequest) validate(attachmentValidator FormDataValidator) (errs
 		}
 	}
 
-	// Unmarshal the update field from JSON
-	updateModelJson := f.req.FormValue(UpdateFieldKey)
-	if updateModelJson == "" && f.isMetadataMandatory {
-		errs = append(errs, validatorError(UpdateFieldKey, "missing mandatory key in form data"))
+	force, interactive, cleared, fieldErrs := f.parseFields()
+	if errs = append(errs, fieldErrs...); len(errs) > 0 {
+		if tmpfilename != "" {
+			os.Remove(tmpfilename)
+		}
+		return
+	}
+
+	f.TmpFileName = tmpfilename
+	f.Name = filename
+	f.Interactive = interactive
+	f.ClearedMetadata = cleared
+	f.Force = force
+	f.Thumbnail = thumb
+
+	return
+}
+
+// stageAttachment copies the attachment to a temporary file, converting it to a zip if it is not one already
+func stageAttachment(file io.Reader, filename string, format zip.Format, limits zip.Limits) (tmpfilename, name string, errs []error) {
+	tmpZip, err := os.CreateTemp("", "s3-zip_*.zip")
+	if err != nil {
+		msg := fmt.Sprintf("http body read error %s", err.Error())
+		return "", "", []error{validatorError(FileFieldKey, msg)}
+	}
+	if _, err = io.Copy(tmpZip, file); err != nil {
+		msg := fmt.Sprintf("http body read error %s", err.Error())
+		errs = append(errs, validatorError(FileFieldKey, msg))
+	}
+	if err = tmpZip.Close(); err != nil {
+		msg := fmt.Sprintf("http body read error %s", err.Error())
+		errs = append(errs, validatorError(FileFieldKey, msg))
 	}
 
-	var interactive *models.Interactive
-	if updateModelJson != "" {
-		if err = json.Unmarshal([]byte(updateModelJson), &interactive); err != nil {
-			errs = append(errs, validatorError(UpdateFieldKey, "cannot unmarshal update json"))
+	tmpfilename, name = tmpZip.Name(), filename
+
+	// everything downstream works with zips - other formats are converted first
+	if len(errs) == 0 && format != zip.FormatZip {
+		normalised, nErr := zip.Normalise(tmpfilename, format, limits)
+		os.Remove(tmpfilename)
+		tmpfilename, name = normalised, zip.NormalisedName(filename)
+		var validationErr *zip.ValidationError
+		if errors.As(nErr, &validationErr) {
+			errs = append(errs, validationErr.Errors()...)
+		} else if nErr != nil {
+			errs = append(errs, validatorError(FileFieldKey, fmt.Sprintf("cannot read %s %s", filename, nErr.Error())))
 		}
+	}
 
-		if interactive.Metadata == nil {
-			interactive.Metadata = &models.Metadata{}
+	if len(errs) > 0 {
+		os.Remove(tmpfilename)
+		return "", "", errs
+	}
+	return tmpfilename, name, nil
+}
+
+// parseFields reads the force flag and the interactive update, which is conformed and validated, along with the
+// metadata fields the update gives as null
+func (f *FormDataRequest) parseFields() (force bool, interactive *models.Interactive, cleared []string, errs []error) {
+	var err error
+
+	// form field or query parameter
+	if forceValue := f.req.FormValue(ForceFieldKey); forceValue != "" {
+		if force, err = strconv.ParseBool(forceValue); err != nil {
+			errs = append(errs, validatorError(ForceFieldKey, "should be true or false"))
 		}
-		interactive.Metadata.Label = strings.TrimSpace(interactive.Metadata.Label)
+	}
 
-		if err = conform.Struct(f.req.Context(), interactive); err != nil {
-			return []error{err}
+	// Unmarshal the update field from JSON
+	updateModelJson := f.req.FormValue(UpdateFieldKey)
+	if updateModelJson == "" {
+		if f.isMetadataMandatory {
+			errs = append(errs, validatorError(UpdateFieldKey, "missing mandatory key in form data"))
 		}
+		return
+	}
 
-		if err = v.Struct(interactive); err != nil {
-			if validationErrs, ok := err.(validator.ValidationErrors); ok {
-				for _, vErr := range validationErrs {
-					errs = append(errs, validatorError(strings.ToLower(vErr.Namespace()), vErr.Tag()))
-				}
-				return
-			} else {
-				errs = append(errs, validatorError(UpdateFieldKey, err.Error()))
+	if err = json.Unmarshal([]byte(updateModelJson), &interactive); err != nil || interactive == nil {
+		errs = append(errs, validatorError(UpdateFieldKey, "cannot unmarshal update json"))
+		return
+	}
+
+	if interactive.Metadata == nil {
+		interactive.Metadata = &models.Metadata{}
+	}
+	errs = append(errs, validateInteractive(f.req.Context(), interactive)...)
+
+	var members struct {
+		Metadata map[string]json.RawMessage `json:"metadata"`
+	}
+	if json.Unmarshal([]byte(updateModelJson), &members) == nil {
+		for name, value := range members.Metadata {
+			if string(value) == "null" {
+				cleared = append(cleared, name)
 			}
 		}
+		sort.Strings(cleared)
+	}
+	return
+}
+
+// validateInteractive conforms the interactive (trimming its fields) then checks it against its validation tags
+func validateInteractive(ctx context.Context, interactive *models.Interactive) (errs []error) {
+	if interactive.Metadata != nil {
+		interactive.Metadata.Label = strings.TrimSpace(interactive.Metadata.Label)
 	}
 
-	if len(errs) == 0 {
-		f.TmpFileName = tmpfilename
-		f.Name = filename
-		f.Interactive = interactive
+	if err := conform.Struct(ctx, interactive); err != nil {
+		return []error{err}
 	}
 
+	if err := v.Struct(interactive); err != nil {
+		if validationErrs, ok := err.(validator.ValidationErrors); ok {
+			for _, vErr := range validationErrs {
+				errs = append(errs, validatorError(strings.ToLower(vErr.Namespace()), vErr.Tag()))
+			}
+		} else {
+			errs = append(errs, validatorError(UpdateFieldKey, err.Error()))
+		}
+	}
 	return
 }
 
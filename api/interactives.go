@@ -8,9 +8,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
-	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/internal/limiter"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
 	"github.com/ONSdigital/dp-interactives-api/internal/zip"
 	"github.com/ONSdigital/dp-interactives-api/models"
 	"github.com/ONSdigital/dp-interactives-api/mongo"
@@ -28,40 +32,112 @@ var (
 	enabled, disabled        = true, false
 	ErrInvalidBody           = errors.New("body has invalid format")
 	ErrCantDeletePublishedIn = errors.New("cannot delete a published interactive")
+
+	// errDuplicateUpload stops an archive identical to one already held from being stored again
+	errDuplicateUpload = errors.New("duplicate upload")
 )
 
 func (api *API) UploadInteractivesHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log.Info(ctx, "upload interactives")
 
+	// the slot is held until the archive is in s3, which for a buffered upload is after the response
+	slot, ok := api.acquireUpload(ctx, w)
+	if !ok {
+		return
+	}
+	defer slot.Release()
+
 	// Validate request
-	formDataRequest, errs := newFormDataRequest(r, api, WantOnlyOneAttachmentWithMetadata, true)
+	var duplicate *models.Interactive
+	var verified *zip.Contents
+	verify := func(f *FormDataRequest, contents *zip.Contents) (err error) {
+		verified = contents
+		duplicate, err = api.verifyUpload(ctx, f, contents)
+		return err
+	}
+	formDataRequest, errs := newFormDataRequest(r, api, WantOnlyOneAttachmentWithMetadata, true, verify, nil)
 	if errs != nil {
 		api.respond.Errors(ctx, w, http.StatusBadRequest, errs)
 		return
 	}
-	archive, htmlFiles, err := zip.Open(formDataRequest.TmpFileName)
-	if err != nil {
-		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("unable to open file %w", err))
+	if formDataRequest.Failed != nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, formDataRequest.Failed)
 		return
 	}
 
+	// a streamed archive was inspected and verified as it was uploaded
+	var contents *zip.Contents
+	var content *models.ContentScan
+	err := formDataRequest.Rejected
+	if streamed := formDataRequest.Streamed; streamed != nil {
+		contents, content = streamed.Contents, streamed.ContentScan
+	} else if err == nil {
+		if contents, content, err = api.inspectArchive(formDataRequest.TmpFileName, formDataRequest.picker); err != nil {
+			api.rejectArchive(ctx, w, formDataRequest.TmpFileName, err)
+			return
+		}
+		err = verify(formDataRequest, contents)
+	}
+	switch {
+	case err == errDuplicateUpload:
+		// The same archive has already been imported - the new interactive shares that import rather than repeating it
+		os.Remove(formDataRequest.TmpFileName)
+		log.Info(ctx, "duplicate upload - reusing import", log.Data{"duplicate_of": duplicate.ID})
+		contents, content = verified, duplicate.Content
+	case errors.Is(err, models.ErrEntryPointNotFound):
+		os.Remove(formDataRequest.TmpFileName)
+		api.respond.Error(ctx, w, http.StatusBadRequest, err)
+		return
+	case err != nil:
+		os.Remove(formDataRequest.TmpFileName)
+		api.respond.Error(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
 	update := formDataRequest.Interactive
+	if status, err := api.checkSlug(ctx, r, nil, update.Metadata); err != nil {
+		os.Remove(formDataRequest.TmpFileName)
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	generated := update.Metadata.HumanReadableSlug == ""
+	update.Metadata.GenerateSlugs(api.newSlug)
+	slug := update.Metadata.HumanReadableSlug
 
-	// Write to DB
 	id := api.newUUID("")
-	interact := &models.Interactive{
-		ID:        id,
-		Active:    &enabled,
-		Published: &disabled,
-		State:     models.ArchiveUploading.String(),
-		Archive:   archive,
-		HTMLFiles: htmlFiles,
+	image, uploaded := formDataRequest.thumbnail()
+	thumbnails, status, err := api.storeThumbnails(ctx, id, image, uploaded)
+	if err != nil {
+		os.Remove(formDataRequest.TmpFileName)
+		api.respond.Error(ctx, w, status, err)
+		return
 	}
-	collisions := 0
+
+	// Write to DB
+	interact := &models.Interactive{
+		ID:         id,
+		Active:     &enabled,
+		Published:  &disabled,
+		State:      models.ArchiveUploading.String(),
+		Archive:    contents.Archive,
+		HTMLFiles:  contents.HTMLFiles,
+		Content:    content,
+		SHA:        contents.Manifest.SHA,
+		Files:      contents.Manifest.Files,
+		Thumbnails: thumbnails,
+	}
+	if duplicate != nil {
+		interact.State, interact.Archive = duplicate.State, duplicate.Archive
+	}
+	collisions, slugs := 0, 0
 	for {
 		update.Metadata.ResourceID = api.newResourceID("")
-		update.Metadata.HumanReadableSlug = api.newSlug(update.Metadata.Label)
+		update.Metadata.PreviousSlugs = nil
+		for _, t := range update.Metadata.Translations {
+			if t != nil {
+				t.PreviousSlugs = nil
+			}
+		}
 		interact.Metadata = update.Metadata
 
 		err = api.mongoDB.UpsertInteractive(ctx, id, interact)
@@ -69,6 +145,16 @@ func (api *API) UploadInteractivesHandler(w http.ResponseWriter, r *http.Request
 			break
 		}
 
+		if errors.Is(err, mongo.ErrSlugTaken) {
+			// a slug made from the label is numbered until it is unique, one given by hand is refused
+			if slugs++; !generated || slugs == MaxCollisions {
+				os.Remove(formDataRequest.TmpFileName)
+				api.respond.Error(ctx, w, http.StatusConflict, fmt.Errorf("%w: %s", ErrSlugTaken, update.Metadata.HumanReadableSlug))
+				return
+			}
+			update.Metadata.HumanReadableSlug = fmt.Sprintf("%s-%d", slug, slugs+1)
+			continue
+		}
 		if mongoDriver.IsDuplicateKeyError(err) {
 			collisions++
 		} else {
@@ -88,25 +174,67 @@ func (api *API) UploadInteractivesHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if duplicate != nil {
+		interactive.Duplicate = true
+		api.respond.JSON(ctx, w, http.StatusOK, interactive)
+		return
+	}
+
+	// a streamed archive is already in s3 so can go straight to the importer
+	if streamed := formDataRequest.Streamed; streamed != nil {
+		api.dispatch(ctx, interactive, streamed.Key, api.uploader(r))
+		api.respond.JSON(ctx, w, http.StatusAccepted, interactive)
+		return
+	}
+
 	api.respond.JSON(ctx, w, http.StatusAccepted, interactive)
 
 	// dont hang on to the old context
 	requestID := request.GetRequestId(ctx)
 	newCtx := request.WithRequestId(context.Background(), requestID)
-	go api.uploadAsync(newCtx, interactive, formDataRequest.TmpFileName, formDataRequest.Name)
+	go api.uploadAsync(newCtx, slot.Transfer(), interactive, formDataRequest.TmpFileName, formDataRequest.Name, api.uploader(r))
 }
 
 func (api *API) GetInteractiveHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	lang, err := language(r)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, err)
+		return
+	}
 	interactive, status, err := api.GetInteractive(ctx, r)
 	if err != nil {
 		api.respond.Error(ctx, w, status, err)
 		return
 	}
+	if lang != "" {
+		interactive.Localise(lang)
+	}
 
 	api.respond.JSON(ctx, w, http.StatusOK, interactive)
 }
 
+// language reads the lang query parameter, which picks the language of the metadata returned
+func language(r *http.Request) (string, error) {
+	lang := r.URL.Query().Get("lang")
+	if lang != "" && !data.IsLanguage(lang) {
+		return "", fmt.Errorf("lang: should be one of %s", strings.Join(data.Languages, ", "))
+	}
+	return lang, nil
+}
+
+// GetInteractiveFilesHandler lists every file in the interactive's archive, with checksums
+func (api *API) GetInteractiveFilesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	interactive, status, err := api.GetInteractive(ctx, r)
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+
+	api.respond.JSON(ctx, w, http.StatusOK, interactive.Manifest())
+}
+
 // update rules
 // if published - allow only file updates
 // if unpublished - allow both file + metadata
@@ -116,12 +244,26 @@ func (api *API) UpdateInteractiveHandler(w http.ResponseWriter, r *http.Request)
 	id := vars["id"]
 	log.Info(ctx, "list interactives", log.Data{"_id": id})
 
+	// only an update carrying an archive takes an upload slot, held until the archive is in s3
+	var slot *limiter.Slot
+	defer func() { slot.Release() }()
+	acquire := func() (err error) {
+		slot, err = api.uploads.Acquire(ctx)
+		return err
+	}
+
 	// Validate request
-	formDataRequest, errs := newFormDataRequest(r, api, WantAtleastMaxOneAttachmentAndOrMetadata, false)
+	formDataRequest, errs := newFormDataRequest(r, api, WantAtleastMaxOneAttachmentAndOrMetadata, false, func(f *FormDataRequest, contents *zip.Contents) error {
+		return api.verifyUpdate(ctx, id, f, contents)
+	}, acquire)
 	if errs != nil {
 		api.respond.Errors(ctx, w, http.StatusBadRequest, errs)
 		return
 	}
+	if formDataRequest.Busy != nil {
+		api.turnAwayUpload(ctx, w, formDataRequest.Busy)
+		return
+	}
 
 	// Check that id exists and is not deleted
 	existing, err := api.mongoDB.GetInteractive(ctx, id)
@@ -133,6 +275,23 @@ func (api *API) UpdateInteractiveHandler(w http.ResponseWriter, r *http.Request)
 		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", id, err))
 		return
 	}
+	if formDataRequest.Failed != nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, formDataRequest.Failed)
+		return
+	}
+
+	// a streamed archive was abandoned if it was not fit to replace the current one
+	duplicate := formDataRequest.Rejected == errDuplicateUpload
+	switch rejected := formDataRequest.Rejected; {
+	case duplicate:
+		log.Info(ctx, "duplicate upload - skipping import", log.Data{"_id": id})
+	case errors.Is(rejected, models.ErrEntryPointNotFound):
+		api.respond.Error(ctx, w, http.StatusBadRequest, rejected)
+		return
+	case rejected != nil:
+		api.respond.Error(ctx, w, http.StatusInternalServerError, rejected)
+		return
+	}
 
 	// prepare updated model
 	updatedModel := &models.Interactive{
@@ -143,28 +302,91 @@ func (api *API) UpdateInteractiveHandler(w http.ResponseWriter, r *http.Request)
 		Metadata:  existing.Metadata,
 	}
 
+	var currentEntryPoint, requestedEntryPoint string
+	if existing.Metadata != nil {
+		currentEntryPoint = existing.Metadata.EntryPoint
+	}
+
 	update := formDataRequest.Interactive
 	if update != nil {
 		if update.Metadata != nil {
+			if status, err := api.checkSlug(ctx, r, existing, update.Metadata); err != nil {
+				os.Remove(formDataRequest.TmpFileName)
+				api.respond.Error(ctx, w, status, err)
+				return
+			}
+			requestedEntryPoint = update.Metadata.EntryPoint
 			updatedModel.Metadata = updatedModel.Metadata.Update(update.Metadata, api.newSlug)
 		}
+		// fields given as null are cleared (required ones have failed validation already)
+		if cleared := formDataRequest.ClearedMetadata; len(cleared) > 0 {
+			updatedModel.Metadata = updatedModel.Metadata.Clear(cleared)
+		}
 	}
 
 	// Finally check if file to be uploaded
-	if formDataRequest.TmpFileName != "" {
-		archive, htmlFiles, err := zip.Open(formDataRequest.TmpFileName)
+	htmlFiles := existing.ArchiveHTMLFiles()
+	if streamed := formDataRequest.Streamed; streamed != nil {
+		updatedModel.Archive = streamed.Contents.Archive
+		updatedModel.HTMLFiles = streamed.Contents.HTMLFiles
+		updatedModel.Content = streamed.ContentScan
+		updatedModel.SHA = streamed.Contents.Manifest.SHA
+		updatedModel.Files = streamed.Contents.Manifest.Files
+		updatedModel.State = models.ArchiveUploading.String()
+		htmlFiles = streamed.Contents.HTMLPaths()
+	} else if formDataRequest.TmpFileName != "" {
+		contents, content, err := api.inspectArchive(formDataRequest.TmpFileName, formDataRequest.picker)
 		if err != nil {
-			api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("unable to open file %w", err))
+			api.rejectArchive(ctx, w, formDataRequest.TmpFileName, err)
 			return
 		}
 
-		updatedModel.Archive = archive
-		updatedModel.HTMLFiles = htmlFiles
-		updatedModel.State = models.ArchiveUploading.String()
+		// an identical archive is not uploaded and imported again (metadata is still updated)
+		if duplicate = !formDataRequest.Force && existing.IsDuplicateUpload(contents.Manifest.SHA); duplicate {
+			log.Info(ctx, "duplicate upload - skipping import", log.Data{"_id": id})
+			os.Remove(formDataRequest.TmpFileName)
+			formDataRequest.TmpFileName = ""
+		} else {
+			updatedModel.Archive = contents.Archive
+			updatedModel.HTMLFiles = contents.HTMLFiles
+			updatedModel.Content = content
+			updatedModel.SHA = contents.Manifest.SHA
+			updatedModel.Files = contents.Manifest.Files
+			updatedModel.State = models.ArchiveUploading.String()
+			htmlFiles = contents.HTMLPaths()
+		}
+	}
+
+	// the entry point must be in the archive - a new one may no longer have the current entry point
+	if requestedEntryPoint != "" || formDataRequest.hasAttachment() {
+		updatedModel.Metadata.EntryPoint, err = models.ResolveEntryPoint(requestedEntryPoint, currentEntryPoint, htmlFiles)
+		if err != nil {
+			os.Remove(formDataRequest.TmpFileName)
+			api.respond.Error(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// a thumbnail found in an archive that was not imported again is already in use
+	if image, uploaded := formDataRequest.thumbnail(); image != nil && (uploaded || !duplicate) {
+		thumbnails, status, err := api.storeThumbnails(ctx, id, image, uploaded)
+		if err != nil {
+			os.Remove(formDataRequest.TmpFileName)
+			api.respond.Error(ctx, w, status, err)
+			return
+		}
+		if thumbnails != nil {
+			updatedModel.Thumbnails = thumbnails
+		}
 	}
 
 	// write to DB
 	err = api.mongoDB.UpsertInteractive(ctx, id, updatedModel)
+	if errors.Is(err, mongo.ErrSlugTaken) {
+		os.Remove(formDataRequest.TmpFileName)
+		api.respond.Error(ctx, w, http.StatusConflict, fmt.Errorf("%w: %s", ErrSlugTaken, updatedModel.Metadata.HumanReadableSlug))
+		return
+	}
 	if err != nil {
 		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("unable to write to DB %w", err))
 		return
@@ -176,6 +398,12 @@ func (api *API) UpdateInteractiveHandler(w http.ResponseWriter, r *http.Request)
 		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", id, err))
 		return
 	}
+	interactive.Duplicate = duplicate
+
+	// a streamed archive is already in s3 so can go straight to the importer
+	if streamed := formDataRequest.Streamed; streamed != nil {
+		api.dispatch(ctx, interactive, streamed.Key, api.uploader(r))
+	}
 
 	api.respond.JSON(ctx, w, http.StatusOK, interactive)
 
@@ -183,7 +411,7 @@ func (api *API) UpdateInteractiveHandler(w http.ResponseWriter, r *http.Request)
 	if formDataRequest.TmpFileName != "" {
 		requestID := request.GetRequestId(ctx)
 		newCtx := request.WithRequestId(context.Background(), requestID)
-		go api.uploadAsync(newCtx, interactive, formDataRequest.TmpFileName, formDataRequest.Name)
+		go api.uploadAsync(newCtx, slot.Transfer(), interactive, formDataRequest.TmpFileName, formDataRequest.Name, api.uploader(r))
 	}
 }
 
@@ -239,6 +467,14 @@ func (api *API) PatchInteractiveHandler(w http.ResponseWriter, r *http.Request)
 		api.respond.Error(ctx, w, status, err)
 		return
 	}
+	switch {
+	case isContentType(r, MergePatchContentType):
+		api.mergeMetadata(w, r, i)
+		return
+	case isContentType(r, JSONPatchContentType):
+		api.jsonPatch(w, r, i)
+		return
+	}
 
 	bytes, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -256,19 +492,16 @@ func (api *API) PatchInteractiveHandler(w http.ResponseWriter, r *http.Request)
 	case interactives.PatchArchive:
 		if patchReq.Interactive.Archive == nil {
 			api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("no archive to patch"))
+			return
 		}
 
-		i.State = models.ImportFailure.String()
-		if patchReq.Interactive.Archive.ImportSuccessful {
-			i.State = models.ImportSuccess.String()
-		}
-
-		i.Archive = &models.Archive{
+		i.SetImportResult(&models.Archive{
 			Name:                patchReq.Interactive.Archive.Name,
 			Size:                patchReq.Interactive.Archive.Size,
 			ImportMessage:       patchReq.Interactive.Archive.ImportMessage,
 			UploadRootDirectory: patchReq.Interactive.Archive.UploadRootDirectory,
-		}
+			ImportSuccessful:    patchReq.Interactive.Archive.ImportSuccessful,
+		})
 
 		err = api.mongoDB.PatchInteractive(ctx, patchReq.Attribute, i)
 		if err != nil {
@@ -297,8 +530,14 @@ func (api *API) ListInteractivesHandler(w http.ResponseWriter, req *http.Request
 	ctx := req.Context()
 	var filter *models.Filter
 
+	lang, err := language(req)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
 	filterJson := req.URL.Query().Get("filter")
-	log.Info(ctx, "list interactives", log.Data{"filter": filterJson})
+	log.Info(ctx, "list interactives", log.Data{"filter": filterJson, "lang": lang})
 	if filterJson != "" {
 		defer req.Body.Close()
 		filter = &models.Filter{}
@@ -318,6 +557,9 @@ func (api *API) ListInteractivesHandler(w http.ResponseWriter, req *http.Request
 	response := make([]*models.Interactive, 0)
 	for _, i := range db {
 		if !api.blockAccess(i) {
+			if lang != "" {
+				i.Localise(lang)
+			}
 			response = append(response, i)
 		}
 	}
@@ -331,15 +573,23 @@ func (api *API) DeleteInteractivesHandler(w http.ResponseWriter, r *http.Request
 	id := vars["id"]
 	log.Info(ctx, fmt.Sprintf("delete interactive [%s]", id))
 
+	if status, err := api.deleteInteractive(ctx, id); err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+
+	api.respond.JSON(ctx, w, http.StatusNoContent, nil)
+}
+
+// deleteInteractive marks an interactive inactive, unless it has been published
+func (api *API) deleteInteractive(ctx context.Context, id string) (int, error) {
 	// error if it doesnt exist
 	vis, err := api.mongoDB.GetInteractive(ctx, id)
 	if (vis == nil && err == nil) || err == mongo.ErrNoRecordFound {
-		api.respond.Error(ctx, w, http.StatusNotFound, fmt.Errorf("interactive-id (%s) does not exist", id))
-		return
+		return http.StatusNotFound, fmt.Errorf("interactive-id (%s) does not exist", id)
 	}
 	if err != nil {
-		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", id, err))
-		return
+		return http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", id, err)
 	}
 
 	// must not delete published interactives
@@ -352,8 +602,7 @@ func (api *API) DeleteInteractivesHandler(w http.ResponseWriter, r *http.Request
 			api.mongoDB.PatchInteractive(ctx, interactives.LinkToCollection, vis)
 		}
 
-		api.respond.Error(ctx, w, http.StatusForbidden, ErrCantDeletePublishedIn)
-		return
+		return http.StatusForbidden, ErrCantDeletePublishedIn
 	}
 
 	// set to inactive
@@ -361,11 +610,9 @@ func (api *API) DeleteInteractivesHandler(w http.ResponseWriter, r *http.Request
 		Active: &disabled,
 	})
 	if err != nil {
-		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("unable to unset active flag %s %w", id, err))
-		return
+		return http.StatusInternalServerError, fmt.Errorf("unable to unset active flag %s %w", id, err)
 	}
-
-	api.respond.JSON(ctx, w, http.StatusNoContent, nil)
+	return http.StatusNoContent, nil
 }
 
 func (api *API) GetInteractive(ctx context.Context, req *http.Request) (*models.Interactive, int, error) {
@@ -386,10 +633,96 @@ func (api *API) GetInteractive(ctx context.Context, req *http.Request) (*models.
 	return i, http.StatusOK, nil
 }
 
-func (api *API) uploadAsync(ctx context.Context, ix *models.Interactive, tmpFileName, name string) {
+// verifyUpload resolves the entry point of a new interactive and, unless forced, looks for an interactive
+// whose import of the same archive succeeded - returned along with errDuplicateUpload, so its import is reused
+func (api *API) verifyUpload(ctx context.Context, f *FormDataRequest, contents *zip.Contents) (*models.Interactive, error) {
+	var err error
+	update := f.Interactive
+	if update.Metadata.EntryPoint, err = models.ResolveEntryPoint(update.Metadata.EntryPoint, "", contents.HTMLPaths()); err != nil {
+		return nil, err
+	}
+	if f.Force {
+		return nil, nil
+	}
+
+	duplicate, err := api.mongoDB.GetInteractiveBySHA(ctx, contents.Manifest.SHA)
+	if err != nil && err != mongo.ErrNoRecordFound {
+		return nil, fmt.Errorf("error checking for duplicate upload %w", err)
+	}
+	if duplicate.IsDuplicateUpload(contents.Manifest.SHA) && duplicate.State == models.ImportSuccess.String() &&
+		duplicate.Archive != nil && duplicate.Archive.UploadRootDirectory != "" {
+		return duplicate, errDuplicateUpload
+	}
+	return nil, nil
+}
+
+// verifyUpdate checks a streamed archive can replace the interactive's current one. An identical archive is
+// abandoned with errDuplicateUpload (the metadata is still updated) unless forced.
+func (api *API) verifyUpdate(ctx context.Context, id string, f *FormDataRequest, contents *zip.Contents) error {
+	existing, err := api.mongoDB.GetInteractive(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error fetching interactive %s %w", id, err)
+	}
+	if existing == nil || existing.Active == nil || !*existing.Active {
+		return fmt.Errorf("interactive-id (%s) is either deleted or does not exist", id)
+	}
+	if !f.Force && existing.IsDuplicateUpload(contents.Manifest.SHA) {
+		return errDuplicateUpload
+	}
+
+	var requested, current string
+	if f.Interactive != nil && f.Interactive.Metadata != nil {
+		requested = f.Interactive.Metadata.EntryPoint
+	}
+	if existing.Metadata != nil {
+		current = existing.Metadata.EntryPoint
+	}
+	_, err = models.ResolveEntryPoint(requested, current, contents.HTMLPaths())
+	return err
+}
+
+// inspectArchive validates the upload and checks its content against the content policy
+func (api *API) inspectArchive(tmpFileName string, extra ...zip.ContentScanner) (*zip.Contents, *models.ContentScan, error) {
+	contents, err := zip.Open(tmpFileName, api.zipLimits)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	findings, err := zip.ScanContent(tmpFileName, api.scanners(extra...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return contents, models.NewContentScan(findings, api.cfg.ContentPolicyStrict), nil
+}
+
+// scanners are the content scanners run over every upload, along with any just for this one
+func (api *API) scanners(extra ...zip.ContentScanner) []zip.ContentScanner {
+	return append(append([]zip.ContentScanner{}, api.contentScanners...), extra...)
+}
+
+// rejectArchive responds with every problem found in the upload and discards it
+func (api *API) rejectArchive(ctx context.Context, w http.ResponseWriter, tmpFileName string, err error) {
 	defer os.Remove(tmpFileName)
+
+	var validationErr *zip.ValidationError
+	if errors.As(err, &validationErr) {
+		api.respond.Errors(ctx, w, http.StatusBadRequest, validationErr.Errors())
+		return
+	}
+	api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("unable to open file %w", err))
+}
+
+func (api *API) uploadAsync(ctx context.Context, slot *limiter.Slot, ix *models.Interactive, tmpFileName, name, uploadedBy string) {
+	defer slot.Release()
+	defer os.Remove(tmpFileName)
+
+	// Infected archives are kept out of s3 and never reach the importer
+	if api.quarantine(ctx, ix, tmpFileName) {
+		return
+	}
+
 	// Upload to S3
-	uri, err := api.uploadFile(tmpFileName, name)
+	uri, err := api.uploadFile(ix.ID, tmpFileName, name)
 	if err != nil {
 		log.Error(ctx, fmt.Sprintf("error uploading [%s] to s3 bucket", tmpFileName), err)
 		ix.State = models.ArchiveUploadFailed.String()
@@ -400,34 +733,75 @@ func (api *API) uploadAsync(ctx context.Context, ix *models.Interactive, tmpFile
 		return
 	}
 
-	// Patch archive + state
+	api.dispatch(ctx, ix, uri, uploadedBy)
+}
+
+// dispatch records that the archive is in s3 and queues the event for the importer. Failures are logged here;
+// the error is for callers that report on the dispatch.
+func (api *API) dispatch(ctx context.Context, ix *models.Interactive, uri, uploadedBy string) error {
+	// Patch archive + state, queueing the event for the importer in the same write
+	// CollectionID will always be there (interactive can only be uploaded inside a collection)
 	ix.Archive.Name = uri
 	ix.State = models.ArchiveUploaded.String()
-	err = api.mongoDB.PatchInteractive(ctx, interactives.PatchArchive, ix)
+	ix.Outbox = []*models.OutboxEvent{{
+		ID:            api.newUUID(""),
+		InteractiveID: ix.ID,
+		FilePath:      uri,
+		Title:         ix.Metadata.Title,
+		CollectionID:  ix.Metadata.CollectionID,
+		Size:          ix.Archive.Size,
+		HTMLFiles:     ix.ArchiveHTMLFiles(),
+		UploadedBy:    uploadedBy,
+		AttemptID:     api.newUUID(""),
+		Created:       time.Now(),
+	}}
+	err := api.mongoDB.PatchInteractive(ctx, interactives.PatchAttribute(mongo.Dispatch), ix)
 	if err != nil {
 		log.Error(ctx, fmt.Sprintf("error updating mongo for interactive [%s], State [%s]", ix.ID, ix.State), err)
-	}
-
-	// Send kafka message to importer
-	// CollectionID will always be there (interactive can only be uploaded inside a collection)
-	err = api.producer.InteractiveUploaded(&event.InteractiveUploaded{
-		ID:           ix.ID,
-		FilePath:     uri,
-		Title:        ix.Metadata.Title,
-		CollectionID: ix.Metadata.CollectionID,
-	})
-	if err != nil {
-		// update state in DB
+		// nothing was queued so the importer will never hear about this upload - keep the archive's key so
+		// that it can be dispatched again
 		ix.State = models.ArchiveDispatchFailed.String()
-		err = api.mongoDB.PatchInteractive(ctx, interactives.PatchAttribute(mongo.State), ix)
-		if err != nil {
-			log.Error(ctx, fmt.Sprintf("error updating mongo for interactive [%s], State [%s]", ix.ID, ix.State), err)
+		if patchErr := api.mongoDB.PatchInteractive(ctx, interactives.PatchArchive, ix); patchErr != nil {
+			log.Error(ctx, fmt.Sprintf("error updating mongo for interactive [%s], State [%s]", ix.ID, ix.State), patchErr)
 		}
-		return
+		return fmt.Errorf("error dispatching interactive %s %w", ix.ID, err)
 	}
-	ix.State = models.ArchiveDispatchedToImporter.String()
-	err = api.mongoDB.PatchInteractive(ctx, interactives.PatchAttribute(mongo.State), ix)
-	if err != nil {
+
+	// Send kafka message to importer now rather than waiting for the next relay tick
+	api.outbox.Notify()
+	return nil
+}
+
+// quarantine scans the archive for malware, returning true (having recorded the outcome) if the upload
+// must go no further. A scan that cannot complete fails the upload rather than letting the archive through.
+func (api *API) quarantine(ctx context.Context, ix *models.Interactive, tmpFileName string) bool {
+	if api.scanner == nil {
+		return false
+	}
+
+	result, err := api.scanFile(ctx, tmpFileName)
+	switch {
+	case err != nil:
+		log.Error(ctx, fmt.Sprintf("error scanning [%s] for malware", tmpFileName), err, log.Data{"id": ix.ID})
+		ix.State = models.ArchiveUploadFailed.String()
+	case result.Infected:
+		log.Warn(ctx, "malware found in uploaded archive", log.Data{"id": ix.ID, "signature": result.Signature})
+		ix.State = models.ArchiveQuarantined.String()
+	default:
+		return false
+	}
+
+	if err = api.mongoDB.PatchInteractive(ctx, interactives.PatchAttribute(mongo.State), ix); err != nil {
 		log.Error(ctx, fmt.Sprintf("error updating mongo for interactive [%s], State [%s]", ix.ID, ix.State), err)
 	}
+	return true
+}
+
+func (api *API) scanFile(ctx context.Context, tmpFileName string) (*scan.Result, error) {
+	f, err := os.Open(tmpFileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return api.scanner.Scan(ctx, f)
 }
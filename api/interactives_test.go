@@ -1,25 +1,40 @@
 package api_test
 
 import (
+	"archive/zip"
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
 	authorisation "github.com/ONSdigital/dp-authorisation/v2/authorisation/mock"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-interactives-api/api"
 	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
 	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/internal/progress"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
 	test_support "github.com/ONSdigital/dp-interactives-api/internal/test-support"
 	"github.com/ONSdigital/dp-interactives-api/models"
+	apiMongo "github.com/ONSdigital/dp-interactives-api/mongo"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
 	kMock "github.com/ONSdigital/dp-kafka/v3/kafkatest"
 	"github.com/ONSdigital/dp-net/v2/responder"
+	permsdk "github.com/ONSdigital/dp-permissions-api/sdk"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/gorilla/mux"
@@ -53,6 +68,9 @@ var (
 		}
 		return nil, nil
 	}
+	noDuplicateFunc = func(ctx context.Context, sha string) (*models.Interactive, error) {
+		return nil, apiMongo.ErrNoRecordFound
+	}
 )
 
 func TestUploadAndUpdateInteractivesHandlers(t *testing.T) {
@@ -88,6 +106,17 @@ func TestUploadAndUpdateInteractivesHandlers(t *testing.T) {
 			title:    "WhenUploadedFileIsNotZip_ThenStatusBadRequest",
 			formFile: "resources/fortest.txt",
 		},
+		{
+			requests: []request{
+				{"/v1/interactives", http.MethodPost, http.StatusBadRequest},
+				{"/v1/interactives/an-id", http.MethodPut, http.StatusBadRequest},
+			},
+			title:    "WhenArchiveIsUnsafe_ThenStatusBadRequest",
+			formFile: "resources/unsafe-interactive.zip",
+			mongoServer: &apiMock.MongoServerMock{
+				GetInteractiveFunc: getInteractiveFunc,
+			},
+		},
 		{
 			requests: []request{
 				{"/v1/interactives", http.MethodPost, http.StatusInternalServerError},
@@ -99,7 +128,8 @@ func TestUploadAndUpdateInteractivesHandlers(t *testing.T) {
 				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error {
 					return errors.New("db upsert error")
 				},
-				GetInteractiveFunc: getInteractiveFunc,
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
 			},
 			s3: &apiMock.S3InterfaceMock{
 				ValidateBucketFunc: func() error { return nil },
@@ -120,7 +150,8 @@ func TestUploadAndUpdateInteractivesHandlers(t *testing.T) {
 				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error {
 					return nil
 				},
-				GetInteractiveFunc: getInteractiveFunc,
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
 				PatchInteractiveFunc: func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
 					return nil
 				},
@@ -141,7 +172,7 @@ func TestUploadAndUpdateInteractivesHandlers(t *testing.T) {
 		t.Run(tc.title, func(t *testing.T) {
 			ctx := context.Background()
 
-			api := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), tc.mongoServer, tc.kafkaProducer, tc.s3, tc.fs, validInteractiveIdGen, noopGen, noopGen, respondr)
+			api := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, tc.mongoServer, tc.kafkaProducer, tc.s3, tc.fs, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
 
 			for _, testReq := range tc.requests {
 				var req *http.Request
@@ -200,7 +231,8 @@ func TestUploadInteractivesHandlers(t *testing.T) {
 				}
 			}
 		},
-		GetInteractiveFunc: getInteractiveFunc,
+		GetInteractiveFunc:      getInteractiveFunc,
+		GetInteractiveBySHAFunc: noDuplicateFunc,
 		PatchInteractiveFunc: func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
 			return nil
 		},
@@ -224,7 +256,7 @@ func TestUploadInteractivesHandlers(t *testing.T) {
 			return strconv.Itoa(callCount)
 		}
 
-		a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), mongoServer, kafkaProducer, s3, fs, validInteractiveIdGen, resourceIdGen, noopGen, respondr)
+		a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, kafkaProducer, s3, fs, nil, nil, validInteractiveIdGen, resourceIdGen, noopGen, respondr)
 
 		req := test_support.NewFileUploadRequest(testReq.method, testReq.uri, "attachment", formFile, &models.Interactive{
 			Metadata: &models.Metadata{
@@ -242,6 +274,324 @@ func TestUploadInteractivesHandlers(t *testing.T) {
 	}
 }
 
+func TestUploadScansArchiveBeforeDispatch(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		title         string
+		result        *scan.Result
+		err           error
+		expectedState models.State
+	}{
+		{"WhenArchiveIsInfected_ThenQuarantined", &scan.Result{Infected: true, Signature: "Eicar-Signature"}, nil, models.ArchiveQuarantined},
+		{"WhenScanFails_ThenUploadFailed", nil, errors.New("clamd unavailable"), models.ArchiveUploadFailed},
+		{"WhenArchiveIsClean_ThenUploaded", &scan.Result{}, nil, models.ArchiveUploaded},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			patched := make(chan string, 1)
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					return &s3manager.UploadOutput{Location: "s3://bucket/file.zip"}, nil
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					patched <- ix.State
+					return nil
+				},
+			}
+			scanner := &apiMock.ScannerMock{
+				ScanFunc: func(ctx context.Context, r io.Reader) (*scan.Result, error) { return tc.result, tc.err },
+			}
+
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, scanner, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			req := test_support.NewFileUploadRequest(http.MethodPost, "/v1/interactives", "attachment", "resources/single-interactive.zip", &models.Interactive{
+				Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"},
+			})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+
+			select {
+			case state := <-patched:
+				require.Equal(t, tc.expectedState.String(), state)
+			case <-time.After(5 * time.Second):
+				t.Fatal("upload was not processed")
+			}
+			require.Len(t, scanner.ScanCalls(), 1)
+			if tc.expectedState != models.ArchiveUploaded {
+				require.Empty(t, s3.UploadCalls())
+			}
+		})
+	}
+}
+
+func TestUploadNormalisesArchives(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		title           string
+		formFile        string
+		expectedKey     string
+		expectedEntries []string
+	}{
+		{"WhenTarGz_ThenUploadedAsZip", "resources/single-interactive.tar.gz", "single-interactive.zip", []string{"css/styles.css", "index.html", "js/chart.js"}},
+		{"WhenSingleHtml_ThenWrappedInZip", "resources/single-interactive.html", "single-interactive.zip", []string{"index.html"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			uploaded := make(chan *s3manager.UploadInput, 1)
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					b, err := io.ReadAll(input.Body)
+					require.NoError(t, err)
+					input.Body = bytes.NewReader(b)
+					uploaded <- input
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			req := test_support.NewFileUploadRequest(http.MethodPost, "/v1/interactives", "attachment", tc.formFile, &models.Interactive{
+				Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"},
+			})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+
+			var input *s3manager.UploadInput
+			select {
+			case input = <-uploaded:
+			case <-time.After(5 * time.Second):
+				t.Fatal("archive was not uploaded")
+			}
+			require.True(t, strings.HasSuffix(*input.Key, "/"+tc.expectedKey))
+
+			b, err := io.ReadAll(input.Body)
+			require.NoError(t, err)
+			zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+			require.NoError(t, err)
+			var entries []string
+			for _, f := range zr.File {
+				entries = append(entries, f.Name)
+			}
+			require.ElementsMatch(t, tc.expectedEntries, entries)
+			upserts := mongoServer.UpsertInteractiveCalls()
+			require.Len(t, upserts[len(upserts)-1].Vis.HTMLFiles, 1)
+			require.Len(t, upserts[len(upserts)-1].Vis.Files, len(tc.expectedEntries))
+			require.Len(t, upserts[len(upserts)-1].Vis.SHA, 64)
+		})
+	}
+}
+
+func TestDuplicateUploads(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+
+	b, err := os.ReadFile("../internal/test-support/resources/single-interactive.zip")
+	require.NoError(t, err)
+	sha := fmt.Sprintf("%x", sha256.Sum256(b))
+	held := func(state models.State) func(ctx context.Context, id string) (*models.Interactive, error) {
+		return func(ctx context.Context, id string) (*models.Interactive, error) {
+			return &models.Interactive{
+				ID:        "an-id",
+				SHA:       sha,
+				State:     state.String(),
+				Active:    &on,
+				Published: &off,
+				Archive:   &models.Archive{Name: "an-id/single-interactive.zip", UploadRootDirectory: "an-id"},
+				Metadata:  &models.Metadata{Title: "title", Label: "label"},
+			}, nil
+		}
+	}
+
+	tests := []struct {
+		title             string
+		method            string
+		uri               string
+		heldState         models.State
+		expectedCode      int
+		expectedDuplicate bool
+	}{
+		{"WhenUploadIsIdentical_ThenImportReused", http.MethodPost, "/v1/interactives", models.ImportSuccess, http.StatusOK, true},
+		{"WhenIdenticalUploadStillImporting_ThenUploaded", http.MethodPost, "/v1/interactives", models.ArchiveUploaded, http.StatusAccepted, false},
+		{"WhenUploadIsIdenticalButForced_ThenUploaded", http.MethodPost, "/v1/interactives?force=true", models.ImportSuccess, http.StatusAccepted, false},
+		{"WhenUpdateIsIdentical_ThenSkipped", http.MethodPut, "/v1/interactives/an-id", models.ImportSuccess, http.StatusOK, true},
+		{"WhenUpdateIsIdenticalButForced_ThenUploaded", http.MethodPut, "/v1/interactives/an-id?force=true", models.ImportSuccess, http.StatusOK, false},
+		{"WhenIdenticalUploadFailedToImport_ThenUploaded", http.MethodPut, "/v1/interactives/an-id", models.ImportFailure, http.StatusOK, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			uploaded := make(chan struct{}, 1)
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					uploaded <- struct{}{}
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:    held(tc.heldState),
+				GetInteractiveBySHAFunc: func(ctx context.Context, sha string) (*models.Interactive, error) {
+					return held(tc.heldState)(ctx, "an-id")
+				},
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			req := test_support.NewFileUploadRequest(tc.method, tc.uri, "attachment", "resources/single-interactive.zip", &models.Interactive{
+				Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"},
+			})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Result().StatusCode)
+
+			var interactive models.Interactive
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &interactive))
+			require.Equal(t, tc.expectedDuplicate, interactive.Duplicate)
+
+			if tc.expectedDuplicate {
+				require.Empty(t, s3.UploadCalls())
+				if tc.method == http.MethodPost {
+					// a new interactive, with the metadata sent, sharing the archive already imported
+					upserts := mongoServer.UpsertInteractiveCalls()
+					require.Len(t, upserts, 1)
+					require.Equal(t, "title1", upserts[0].Vis.Metadata.Title)
+					require.Equal(t, models.ImportSuccess.String(), upserts[0].Vis.State)
+					require.Equal(t, "an-id", upserts[0].Vis.Archive.UploadRootDirectory)
+					require.Equal(t, sha, upserts[0].Vis.SHA)
+					require.Empty(t, mongoServer.PatchInteractiveCalls())
+				}
+				return
+			}
+			select {
+			case <-uploaded:
+			case <-time.After(5 * time.Second):
+				t.Fatal("archive was not uploaded")
+			}
+		})
+	}
+}
+
+func TestUploadEntryPoint(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+
+	tests := []struct {
+		title              string
+		method             string
+		uri                string
+		entryPoint         string
+		expectedCode       int
+		expectedEntryPoint string
+	}{
+		{"WhenUploadWithoutEntryPoint_ThenIndexHtml", http.MethodPost, "/v1/interactives", "", http.StatusAccepted, "index.html"},
+		{"WhenUploadWithEntryPointInArchive_ThenStatusAccepted", http.MethodPost, "/v1/interactives", "/index.html", http.StatusAccepted, "index.html"},
+		{"WhenUploadWithEntryPointNotInArchive_ThenStatusBadRequest", http.MethodPost, "/v1/interactives", "missing.html", http.StatusBadRequest, ""},
+		{"WhenUpdateWithEntryPointNotInArchive_ThenStatusBadRequest", http.MethodPut, "/v1/interactives/an-id", "missing.html", http.StatusBadRequest, ""},
+		{"WhenUpdateWithoutEntryPoint_ThenIndexHtml", http.MethodPut, "/v1/interactives/an-id", "", http.StatusOK, "index.html"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			req := test_support.NewFileUploadRequest(tc.method, tc.uri, "attachment", "resources/single-interactive.zip", &models.Interactive{
+				Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1", EntryPoint: tc.entryPoint},
+			})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Result().StatusCode)
+
+			upserts := mongoServer.UpsertInteractiveCalls()
+			if tc.expectedCode == http.StatusBadRequest {
+				require.Empty(t, upserts)
+				return
+			}
+			require.Equal(t, tc.expectedEntryPoint, upserts[0].Vis.Metadata.EntryPoint)
+		})
+	}
+}
+
+func TestGetInteractiveFilesHandler(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	files := []*models.ArchiveFile{
+		{Path: "index.html", Size: 10, ContentType: "text/html; charset=utf-8", SHA256: "abc"},
+	}
+	tests := []struct {
+		title         string
+		responseCode  int
+		interactive   *models.Interactive
+		expectedFiles int
+	}{
+		{"WhenMissingInDatabase_ThenStatusNotFound", http.StatusNotFound, nil, 0},
+		{"WhenInteractiveIsDeleted_ThenStatusNotFound", http.StatusNotFound, &models.Interactive{Active: &off, Files: files}, 0},
+		{"WhenNoManifest_ThenEmptyList", http.StatusOK, &models.Interactive{Active: &on, Published: &on}, 0},
+		{"WhenAllGood_ThenManifestListed", http.StatusOK, &models.Interactive{Active: &on, Published: &on, SHA: "archive-sha", Files: files}, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			mongoServer := &apiMock.MongoServerMock{
+				GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) { return tc.interactive, nil },
+			}
+			a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/interactives/an-id/files", nil))
+			require.Equal(t, tc.responseCode, resp.Result().StatusCode)
+
+			if tc.responseCode == http.StatusOK {
+				var manifest models.Manifest
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &manifest))
+				require.Equal(t, tc.interactive.SHA, manifest.SHA)
+				require.Len(t, manifest.Files, tc.expectedFiles)
+			}
+		})
+	}
+}
+
 func TestGetInteractiveMetadataHandler(t *testing.T) {
 	t.Parallel()
 	log.SetDestination(io.Discard, io.Discard)
@@ -303,7 +653,7 @@ func TestGetInteractiveMetadataHandler(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.title, func(t *testing.T) {
 			ctx := context.Background()
-			api := api.Setup(ctx, &config.Config{PublishingEnabled: tc.publishingEnabled}, mux.NewRouter(), newAuthMiddlwareMock(), tc.mongoServer, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+			api := api.Setup(ctx, &config.Config{PublishingEnabled: tc.publishingEnabled}, mux.NewRouter(), newAuthMiddlwareMock(), nil, tc.mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
 			resp := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("http://localhost:27050/v1/interactives/%s", interactiveID), nil)
 			api.Router.ServeHTTP(resp, req)
@@ -320,3 +670,606 @@ func newAuthMiddlwareMock() *authorisation.MiddlewareMock {
 		},
 	}
 }
+
+func denyPermissions(denied ...string) *apiMock.PermissionsCheckerMock {
+	return &apiMock.PermissionsCheckerMock{
+		HasPermissionFunc: func(ctx context.Context, entityData permsdk.EntityData, permission string, attributes map[string]string) (bool, error) {
+			for _, d := range denied {
+				if permission == d {
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	}
+}
+
+func TestInteractiveEventsHandler(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	interactiveInState := func(state models.State) func(ctx context.Context, id string) (*models.Interactive, error) {
+		return func(ctx context.Context, id string) (*models.Interactive, error) {
+			return &models.Interactive{
+				ID:        id,
+				State:     state.String(),
+				Active:    &on,
+				Published: &off,
+				Archive:   &models.Archive{Name: "an-id/single-interactive.zip", UploadRootDirectory: "an-id"},
+				Metadata:  &models.Metadata{Title: "title", Label: "label"},
+			}, nil
+		}
+	}
+
+	t.Run("WhenMissingInDatabase_ThenStatusNotFound", func(t *testing.T) {
+		mongoServer := &apiMock.MongoServerMock{
+			GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+				return nil, apiMongo.ErrNoRecordFound
+			},
+		}
+		a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/interactives/an-id/events", nil))
+		require.Equal(t, http.StatusNotFound, resp.Result().StatusCode)
+	})
+
+	t.Run("WhenInFinalState_ThenStreamEndsAfterCurrentState", func(t *testing.T) {
+		mongoServer := &apiMock.MongoServerMock{GetInteractiveFunc: interactiveInState(models.ImportSuccess)}
+		a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/v1/interactives/an-id/events", nil))
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "text/event-stream", resp.Header().Get("Content-Type"))
+		require.Equal(t, "event: state\ndata: {\"interactive_id\":\"an-id\",\"state\":\"ImportSuccess\"}\n\n", resp.Body.String())
+	})
+
+	t.Run("WhenUploadedAndImported_ThenProgressAndStatesStreamed", func(t *testing.T) {
+		s3 := &apiMock.S3InterfaceMock{
+			ValidateBucketFunc: func() error { return nil },
+			UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+				_, err := io.Copy(io.Discard, input.Body)
+				return &s3manager.UploadOutput{Location: "s3://bucket/file.zip"}, err
+			},
+		}
+		mongoServer := &apiMock.MongoServerMock{
+			UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+			GetInteractiveFunc:      interactiveInState(models.ArchiveUploading),
+			GetInteractiveBySHAFunc: noDuplicateFunc,
+			PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+				return nil
+			},
+		}
+		a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+		server := httptest.NewServer(a.Router)
+		defer server.Close()
+
+		stream, err := http.Get(server.URL + "/v1/interactives/an-id/events")
+		require.NoError(t, err)
+		defer stream.Body.Close()
+		events := bufio.NewReader(stream.Body)
+		nextEvent := func() (string, *progress.Update) {
+			name, err := events.ReadString('\n')
+			require.NoError(t, err)
+			data, err := events.ReadString('\n')
+			require.NoError(t, err)
+			_, err = events.ReadString('\n')
+			require.NoError(t, err)
+
+			var u progress.Update
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &u))
+			return strings.TrimSpace(strings.TrimPrefix(name, "event: ")), &u
+		}
+
+		name, u := nextEvent()
+		require.Equal(t, progress.TypeState, name)
+		require.Equal(t, models.ArchiveUploading.String(), u.State)
+
+		req := test_support.NewFileUploadRequest(http.MethodPost, "/v1/interactives", "attachment", "resources/single-interactive.zip", &models.Interactive{
+			Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"},
+		})
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusAccepted, resp.Result().StatusCode)
+
+		var last *progress.Update
+		for name, u = nextEvent(); name == progress.TypeProgress; name, u = nextEvent() {
+			last = u
+		}
+		require.NotNil(t, last)
+		require.Equal(t, last.TotalBytes, last.BytesUploaded)
+		require.Equal(t, models.ArchiveUploaded.String(), u.State)
+
+		patch, _ := json.Marshal(interactives.PatchRequest{
+			Attribute:   interactives.PatchArchive,
+			Interactive: interactives.Interactive{Archive: &interactives.Archive{Name: "file.zip", ImportSuccessful: true}},
+		})
+		resp = httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodPatch, "/v1/interactives/an-id", bytes.NewReader(patch)))
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+
+		name, u = nextEvent()
+		require.Equal(t, progress.TypeState, name)
+		require.Equal(t, models.ImportSuccess.String(), u.State)
+
+		_, err = events.ReadByte()
+		require.Equal(t, io.EOF, err)
+	})
+}
+
+func TestStreamingUploads(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	interactive := &models.Interactive{Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"}}
+
+	archive, err := os.ReadFile("../internal/test-support/resources/single-interactive.zip")
+	require.NoError(t, err)
+	unsafe, err := os.ReadFile("../internal/test-support/resources/unsafe-interactive.zip")
+	require.NoError(t, err)
+	infected := &bytes.Buffer{}
+	w := zip.NewWriter(infected)
+	f, err := w.CreateRaw(&zip.FileHeader{
+		Name:               "index.html",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(scan.Eicar)),
+		CompressedSize64:   uint64(len(scan.Eicar)),
+		UncompressedSize64: uint64(len(scan.Eicar)),
+	})
+	require.NoError(t, err)
+	_, err = f.Write([]byte(scan.Eicar))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	tests := []struct {
+		title          string
+		body           []byte
+		fieldsFirst    bool
+		held           *models.Interactive
+		expectedCode   int
+		expectedError  string
+		expectedStored bool
+	}{
+		{"WhenArchiveIsValid_ThenStoredAndDispatched", archive, true, nil, http.StatusAccepted, "", true},
+		{"WhenArchiveIsUnsafe_ThenRejected", unsafe, true, nil, http.StatusBadRequest, "path traversal is not allowed", false},
+		{"WhenArchiveIsInfected_ThenRejected", infected.Bytes(), true, nil, http.StatusBadRequest, "archive failed malware scan", false},
+		{"WhenArchiveIsADuplicate_ThenNotStored", archive, true, &models.Interactive{ID: "held-id", State: models.ImportSuccess.String(), Active: &on, Archive: &models.Archive{Name: "held-id/archive.zip", UploadRootDirectory: "held-id"}, Metadata: &models.Metadata{}}, http.StatusOK, "", false},
+		{"WhenFieldsComeAfterTheFile_ThenRejected", archive, false, nil, http.StatusBadRequest, "must come before the file", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			var stored []byte
+			var partSize int64
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					u := &s3manager.Uploader{}
+					for _, o := range options {
+						o(u)
+					}
+					partSize = u.PartSize
+					b, err := io.ReadAll(input.Body)
+					if err != nil {
+						return nil, err
+					}
+					stored = b
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			var states []string
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:    getInteractiveFunc,
+				GetInteractiveBySHAFunc: func(ctx context.Context, sha string) (*models.Interactive, error) {
+					if tc.held == nil {
+						return nil, apiMongo.ErrNoRecordFound
+					}
+					tc.held.SHA = sha
+					return tc.held, nil
+				},
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					states = append(states, ix.State)
+					return nil
+				},
+			}
+			cfg := &config.Config{PublishingEnabled: true, UploadStreaming: true, UploadStreamPartSize: 6 << 20}
+
+			a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, &scan.Fake{}, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			req := newStreamingUploadRequest(t, "single-interactive.zip", tc.body, interactive, tc.fieldsFirst)
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Result().StatusCode, resp.Body.String())
+			require.Contains(t, resp.Body.String(), tc.expectedError)
+
+			if !tc.expectedStored {
+				require.Nil(t, stored)
+				require.Empty(t, states)
+				return
+			}
+			// the archive is dispatched before the response rather than in the background
+			require.Equal(t, tc.body, stored)
+			require.Equal(t, int64(6<<20), partSize)
+			require.Equal(t, []string{models.ArchiveUploaded.String()}, states)
+		})
+	}
+}
+
+// newStreamingUploadRequest builds an upload form, by default with the interactive field ahead of the file
+func newStreamingUploadRequest(t *testing.T, filename string, content []byte, i *models.Interactive, fieldsFirst bool) *http.Request {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	writeFields := func() {
+		b, err := json.Marshal(i)
+		require.NoError(t, err)
+		require.NoError(t, w.WriteField(api.UpdateFieldKey, string(b)))
+	}
+
+	if fieldsFirst {
+		writeFields()
+	}
+	part, err := w.CreateFormFile("attachment", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	if !fieldsFirst {
+		writeFields()
+	}
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/interactives", body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestUploadLimit(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	release := make(chan struct{})
+	uploaded := make(chan struct{}, 4)
+	s3 := &apiMock.S3InterfaceMock{
+		ValidateBucketFunc: func() error { return nil },
+		UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+			<-release
+			uploaded <- struct{}{}
+			return &s3manager.UploadOutput{}, nil
+		},
+	}
+	mongoServer := &apiMock.MongoServerMock{
+		UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+		GetInteractiveFunc:      getInteractiveFunc,
+		GetInteractiveBySHAFunc: noDuplicateFunc,
+		GetInteractiveBySlugFunc: func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+			return nil, apiMongo.ErrNoRecordFound
+		},
+		PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+			return nil
+		},
+	}
+	cfg := &config.Config{PublishingEnabled: true, UploadConcurrency: 1, UploadRetryAfter: 30 * time.Second}
+	a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+	send := func(a *api.API, ctx context.Context, method, uri, path string) *http.Response {
+		req := test_support.NewFileUploadRequest(method, uri, "attachment", path, &models.Interactive{
+			Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"},
+		})
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, req.WithContext(ctx))
+		return resp.Result()
+	}
+	upload := func() *http.Response {
+		return send(a, ctx, http.MethodPost, "/v1/interactives", "resources/single-interactive.zip")
+	}
+
+	// the first upload holds the only slot until it is in s3, after the response
+	require.Equal(t, http.StatusAccepted, upload().StatusCode)
+
+	resp := upload()
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, "30", resp.Header.Get("Retry-After"))
+
+	// an update without an archive needs no slot, unlike one with an archive
+	require.Equal(t, http.StatusOK, send(a, ctx, http.MethodPut, "/v1/interactives/an-id", "-").StatusCode)
+	require.Equal(t, http.StatusTooManyRequests, send(a, ctx, http.MethodPut, "/v1/interactives/an-id", "resources/single-interactive.zip").StatusCode)
+
+	state := healthcheck.NewCheckState("uploads")
+	require.NoError(t, a.UploadsChecker(ctx, state))
+	require.Equal(t, healthcheck.StatusWarning, state.Status())
+
+	// a request given up while queued is not turned away as though the uploads were saturated
+	queueing := api.Setup(ctx, &config.Config{PublishingEnabled: true, UploadConcurrency: 1, UploadQueueDepth: 1, UploadRetryAfter: 30 * time.Second}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+	require.Equal(t, http.StatusAccepted, send(queueing, ctx, http.MethodPost, "/v1/interactives", "resources/single-interactive.zip").StatusCode)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	resp = send(queueing, cancelled, http.MethodPost, "/v1/interactives", "resources/single-interactive.zip")
+	require.NotEqual(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Empty(t, resp.Header.Get("Retry-After"))
+
+	close(release)
+	<-uploaded
+	require.Eventually(t, func() bool { return upload().StatusCode == http.StatusAccepted }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLocalisedMetadata(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	translated := func() *models.Interactive {
+		i := &models.Interactive{
+			ID:        "an-id",
+			Active:    &on,
+			Published: &on,
+			Metadata: &models.Metadata{
+				Title:             "Economic growth",
+				Label:             "growth",
+				HumanReadableSlug: "economic-growth",
+				ResourceID:        "abcd1234",
+				Translations: map[string]*models.Translation{
+					"cy": {Title: "Twf economaidd", Label: "twf", HumanReadableSlug: "twf-economaidd"},
+				},
+			},
+		}
+		i.SetJSONAttribs("")
+		return i
+	}
+	mongoServer := &apiMock.MongoServerMock{
+		GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) { return translated(), nil },
+		GetInteractiveByResourceIDFunc: func(ctx context.Context, resourceID string) (*models.Interactive, error) {
+			return translated(), nil
+		},
+		ListInteractivesFunc: func(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error) {
+			return []*models.Interactive{translated()}, nil
+		},
+	}
+
+	tests := []struct {
+		title         string
+		uri           string
+		expectedCode  int
+		expectedTitle string
+		expectedURI   string
+	}{
+		{"WhenGetWithoutLang_ThenEnglish", "/v1/interactives/an-id", http.StatusOK, "Economic growth", "/interactives/economic-growth-abcd1234"},
+		{"WhenGetInWelsh_ThenWelsh", "/v1/interactives/an-id?lang=cy", http.StatusOK, "Twf economaidd", "/interactives/twf-economaidd-abcd1234"},
+		{"WhenGetInEnglish_ThenEnglish", "/v1/interactives/an-id?lang=en", http.StatusOK, "Economic growth", "/interactives/economic-growth-abcd1234"},
+		{"WhenGetInUnknownLang_ThenBadRequest", "/v1/interactives/an-id?lang=fr", http.StatusBadRequest, "", ""},
+		{"WhenGetByResourceIDInWelsh_ThenWelsh", "/v1/interactives/resource/abcd1234?lang=cy", http.StatusOK, "Twf economaidd", "/interactives/twf-economaidd-abcd1234"},
+		{"WhenListInWelsh_ThenWelsh", "/v1/interactives?lang=cy", http.StatusOK, "Twf economaidd", "/interactives/twf-economaidd-abcd1234"},
+		{"WhenListInUnknownLang_ThenBadRequest", "/v1/interactives?lang=fr", http.StatusBadRequest, "", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			a := api.Setup(context.Background(), &config.Config{}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.uri, nil))
+			require.Equal(t, tc.expectedCode, resp.Code)
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+
+			var interactive *models.Interactive
+			if strings.HasPrefix(tc.uri, "/v1/interactives?") {
+				var list []*models.Interactive
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+				require.Len(t, list, 1)
+				interactive = list[0]
+			} else {
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &interactive))
+			}
+			require.Equal(t, tc.expectedTitle, interactive.Metadata.Title)
+			require.Equal(t, tc.expectedURI, interactive.URI)
+		})
+	}
+}
+
+func TestUploadTranslations(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	tests := []struct {
+		title        string
+		translations map[string]*models.Translation
+		expectedCode int
+		expectedSlug string
+	}{
+		{"WhenWelsh_ThenWelshSlug", map[string]*models.Translation{"cy": {Title: " Gŵyl y Gwanwyn ", Label: "Gŵyl"}}, http.StatusAccepted, "gŵyl"},
+		{"WhenWelshSlugGiven_ThenUsed", map[string]*models.Translation{"cy": {Title: "Gŵyl y Gwanwyn", Label: "Gŵyl", HumanReadableSlug: "gwyl-gwanwyn"}}, http.StatusAccepted, "gwyl-gwanwyn"},
+		{"WhenWelshSlugTaken_ThenConflict", map[string]*models.Translation{"cy": {Title: "Gŵyl y Gwanwyn", Label: "Gŵyl", HumanReadableSlug: "gwyl-taken"}}, http.StatusConflict, ""},
+		{"WhenWelshSlugInvalid_ThenBadRequest", map[string]*models.Translation{"cy": {Title: "Gŵyl y Gwanwyn", Label: "Gŵyl", HumanReadableSlug: "Gŵyl y Gwanwyn"}}, http.StatusBadRequest, ""},
+		{"WhenWelshTitleMissing_ThenBadRequest", map[string]*models.Translation{"cy": {Label: "Gŵyl"}}, http.StatusBadRequest, ""},
+		{"WhenEnglishTranslation_ThenBadRequest", map[string]*models.Translation{"en": {Title: "Spring Festival", Label: "festival"}}, http.StatusBadRequest, ""},
+		{"WhenUnknownLanguage_ThenBadRequest", map[string]*models.Translation{"fr": {Title: "Fête du printemps", Label: "fete"}}, http.StatusBadRequest, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
+				GetInteractiveBySlugFunc: func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+					if slug == "gwyl-taken" {
+						return &models.Interactive{ID: "other-id"}, nil
+					}
+					return nil, apiMongo.ErrNoRecordFound
+				},
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+			a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			req := test_support.NewFileUploadRequest(http.MethodPost, "/v1/interactives", "attachment", "resources/single-interactive.zip", &models.Interactive{
+				Metadata: &models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1", Translations: tc.translations},
+			})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code)
+
+			upserts := mongoServer.UpsertInteractiveCalls()
+			if tc.expectedSlug == "" {
+				require.Empty(t, upserts)
+				return
+			}
+			welsh := upserts[0].Vis.Metadata.Translations["cy"]
+			require.Equal(t, tc.expectedSlug, welsh.HumanReadableSlug)
+			require.Equal(t, "Gŵyl y Gwanwyn", welsh.Title)
+		})
+	}
+}
+
+func TestUploadEditorialMetadata(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("keyword%d", i)
+	}
+	valid := func() *models.Metadata {
+		return &models.Metadata{
+			Label:         "label1",
+			InternalID:    "idValue",
+			Title:         "title1",
+			Summary:       " How GDP grew ",
+			Keywords:      []string{" gdp ", "growth"},
+			Topics:        []string{"economy"},
+			RelatedLink:   &models.Link{Title: "GDP", URI: "/datasets/gdp"},
+			Contact:       &models.Contact{Name: "Economic statistics", Email: "gdp@ons.gov.uk", Telephone: "+44 1633 456900"},
+			Accessibility: "A line chart of GDP growth",
+			Thumbnail:     "https://www.ons.gov.uk/thumbnails/gdp.png",
+		}
+	}
+	onlyRequired := func(m *models.Metadata) {
+		*m = models.Metadata{Label: "label1", InternalID: "idValue", Title: "title1"}
+	}
+
+	tests := []struct {
+		title            string
+		change           func(m *models.Metadata)
+		expectedCode     int
+		expectedKeywords []string
+	}{
+		{"WhenValid_ThenAccepted", func(m *models.Metadata) {}, http.StatusAccepted, []string{"gdp", "growth"}},
+		{"WhenOnlyRequiredFields_ThenAccepted", onlyRequired, http.StatusAccepted, nil},
+		{"WhenTooManyKeywords_ThenBadRequest", func(m *models.Metadata) { m.Keywords = tooMany }, http.StatusBadRequest, nil},
+		{"WhenBlankKeyword_ThenBadRequest", func(m *models.Metadata) { m.Keywords = []string{"gdp", " "} }, http.StatusBadRequest, nil},
+		{"WhenSummaryTooLong_ThenBadRequest", func(m *models.Metadata) { m.Summary = strings.Repeat("x", 1001) }, http.StatusBadRequest, nil},
+		{"WhenRelatedLinkNotUri_ThenBadRequest", func(m *models.Metadata) { m.RelatedLink.URI = "not a link" }, http.StatusBadRequest, nil},
+		{"WhenContactEmailInvalid_ThenBadRequest", func(m *models.Metadata) { m.Contact.Email = "not-an-email" }, http.StatusBadRequest, nil},
+		{"WhenContactNameMissing_ThenBadRequest", func(m *models.Metadata) { m.Contact.Name = "" }, http.StatusBadRequest, nil},
+		{"WhenThumbnailNotUri_ThenBadRequest", func(m *models.Metadata) { m.Thumbnail = "thumbnail" }, http.StatusBadRequest, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc:   func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc:      getInteractiveFunc,
+				GetInteractiveBySHAFunc: noDuplicateFunc,
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+			a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+			metadata := valid()
+			tc.change(metadata)
+			req := test_support.NewFileUploadRequest(http.MethodPost, "/v1/interactives", "attachment", "resources/single-interactive.zip", &models.Interactive{Metadata: metadata})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code)
+
+			upserts := mongoServer.UpsertInteractiveCalls()
+			if tc.expectedCode != http.StatusAccepted {
+				require.Empty(t, upserts)
+				return
+			}
+			stored := upserts[0].Vis.Metadata
+			require.Equal(t, strings.TrimSpace(metadata.Summary), stored.Summary)
+			require.Equal(t, tc.expectedKeywords, stored.Keywords)
+			require.Equal(t, metadata.Contact, stored.Contact)
+		})
+	}
+}
+
+func TestUpdateClearsMetadata(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	tests := []struct {
+		title        string
+		update       string
+		expectedCode int
+		check        func(t *testing.T, m *models.Metadata)
+	}{
+		{"WhenFieldsNull_ThenCleared", `{"metadata":{"title":"new title","label":"label","internal_id":"id","summary":null,"keywords":null,"collection_id":null}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, "new title", m.Title)
+			require.Empty(t, m.Summary)
+			require.Nil(t, m.Keywords)
+			require.Empty(t, m.CollectionID)
+			require.Equal(t, "/datasets/gdp", m.RelatedLink.URI)
+		}},
+		{"WhenSlugOrResourceIdNull_ThenKept", `{"metadata":{"title":"title","label":"label","internal_id":"id","slug":null,"resource_id":null}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, "gdp-growth", m.HumanReadableSlug)
+			require.Equal(t, "abcd1234", m.ResourceID)
+		}},
+		{"WhenRequiredFieldNull_ThenBadRequest", `{"metadata":{"title":null,"label":"label","internal_id":"id"}}`, http.StatusBadRequest, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			mongoServer := &apiMock.MongoServerMock{
+				GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+					return &models.Interactive{
+						ID:        id,
+						Active:    &on,
+						Published: &off,
+						State:     models.ImportSuccess.String(),
+						Metadata: &models.Metadata{
+							Title:             "title",
+							Label:             "label",
+							InternalID:        "id",
+							HumanReadableSlug: "gdp-growth",
+							ResourceID:        "abcd1234",
+							CollectionID:      "collection",
+							Summary:           "How GDP grew",
+							Keywords:          []string{"gdp"},
+							RelatedLink:       &models.Link{URI: "/datasets/gdp"},
+						},
+					}, nil
+				},
+				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+			}
+			a := api.Setup(context.Background(), &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+			body := &bytes.Buffer{}
+			w := multipart.NewWriter(body)
+			require.NoError(t, w.WriteField(api.UpdateFieldKey, tc.update))
+			require.NoError(t, w.Close())
+			req := httptest.NewRequest(http.MethodPut, "/v1/interactives/an-id", body)
+			req.Header.Set("Content-Type", w.FormDataContentType())
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+
+			upserts := mongoServer.UpsertInteractiveCalls()
+			if tc.check == nil {
+				require.Empty(t, upserts)
+				return
+			}
+			require.Len(t, upserts, 1)
+			tc.check(t, upserts[0].Vis.Metadata)
+		})
+	}
+}
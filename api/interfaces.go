@@ -2,11 +2,15 @@ package api
 
 import (
 	"context"
+	"io"
 	"net/http"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
 	"github.com/ONSdigital/dp-interactives-api/models"
+	permsdk "github.com/ONSdigital/dp-permissions-api/sdk"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 )
 
@@ -14,14 +18,28 @@ import (
 //go:generate moq -out mock/auth.go -pkg mock . AuthHandler
 //go:generate moq -out mock/filesservice.go -pkg mock . FilesService
 //go:generate moq -out mock/s3.go -pkg mock . S3Interface
+//go:generate moq -out mock/scanner.go -pkg mock . Scanner
+//go:generate moq -out mock/content.go -pkg mock . ContentStore
+//go:generate moq -out mock/permissions.go -pkg mock . PermissionsChecker
 
 type MongoServer interface {
 	Close(ctx context.Context) error
 	Checker(ctx context.Context, state *healthcheck.CheckState) (err error)
 	UpsertInteractive(ctx context.Context, id string, vis *models.Interactive) (err error)
 	GetInteractive(ctx context.Context, id string) (*models.Interactive, error)
+	GetInteractiveBySHA(ctx context.Context, sha string) (*models.Interactive, error)
+	GetInteractiveByResourceID(ctx context.Context, resourceID string) (*models.Interactive, error)
+	GetInteractiveBySlug(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error)
 	ListInteractives(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error)
 	PatchInteractive(context.Context, interactives.PatchAttribute, *models.Interactive) error
+	PatchMetadata(ctx context.Context, id string, current, patched *models.Metadata, published *bool) error
+	ListOutboxEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error)
+	RetryOutboxEvent(ctx context.Context, e *models.OutboxEvent) error
+	CompleteOutboxEvent(ctx context.Context, e *models.OutboxEvent, state models.State) error
+	UpsertDeadLetter(ctx context.Context, d *models.DeadLetter) error
+	ListDeadLetters(ctx context.Context, offset, limit int) ([]*models.DeadLetter, int, error)
+	GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error)
+	DeleteDeadLetter(ctx context.Context, id string) error
 }
 
 // AuthHandler interface for adding auth to endpoints
@@ -29,6 +47,12 @@ type AuthHandler interface {
 	Require(permission string, handler http.HandlerFunc) http.HandlerFunc
 }
 
+// PermissionsChecker tells whether a caller holds a permission, where what a handler does (rather than whether
+// it may be called) depends on it
+type PermissionsChecker interface {
+	HasPermission(ctx context.Context, entityData permsdk.EntityData, permission string, attributes map[string]string) (bool, error)
+}
+
 type FilesService interface {
 	SetCollectionID(ctx context.Context, file, collectionID string) error
 	Checker(ctx context.Context, state *healthcheck.CheckState) error
@@ -36,6 +60,20 @@ type FilesService interface {
 
 type S3Interface interface {
 	Upload(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error)
+	Get(key string) (io.ReadCloser, *int64, error)
 	ValidateBucket() error
 	Checker(ctx context.Context, state *healthcheck.CheckState) error
 }
+
+// Scanner checks an uploaded archive for malware before it is dispatched to the importer
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (*scan.Result, error)
+	Checker(ctx context.Context, state *healthcheck.CheckState) error
+}
+
+// ContentStore reads the files of imported interactives, and writes the thumbnails served alongside them
+type ContentStore interface {
+	Get(ctx context.Context, key string, opts *content.GetOptions) (*content.Object, error)
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	Checker(ctx context.Context, state *healthcheck.CheckState) error
+}
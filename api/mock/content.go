@@ -0,0 +1,202 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"context"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-interactives-api/api"
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"sync"
+)
+
+// Ensure, that ContentStoreMock does implement api.ContentStore.
+// If this is not the case, regenerate this file with moq.
+var _ api.ContentStore = &ContentStoreMock{}
+
+// ContentStoreMock is a mock implementation of api.ContentStore.
+//
+//	func TestSomethingThatUsesContentStore(t *testing.T) {
+//
+//		// make and configure a mocked api.ContentStore
+//		mockedContentStore := &ContentStoreMock{
+//			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error {
+//				panic("mock out the Checker method")
+//			},
+//			GetFunc: func(ctx context.Context, key string, opts *content.GetOptions) (*content.Object, error) {
+//				panic("mock out the Get method")
+//			},
+//			PutFunc: func(ctx context.Context, key string, contentType string, body []byte) error {
+//				panic("mock out the Put method")
+//			},
+//		}
+//
+//		// use mockedContentStore in code that requires api.ContentStore
+//		// and then make assertions.
+//
+//	}
+type ContentStoreMock struct {
+	// CheckerFunc mocks the Checker method.
+	CheckerFunc func(ctx context.Context, state *healthcheck.CheckState) error
+
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, key string, opts *content.GetOptions) (*content.Object, error)
+
+	// PutFunc mocks the Put method.
+	PutFunc func(ctx context.Context, key string, contentType string, body []byte) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Checker holds details about calls to the Checker method.
+		Checker []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// State is the state argument value.
+			State *healthcheck.CheckState
+		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// Opts is the opts argument value.
+			Opts *content.GetOptions
+		}
+		// Put holds details about calls to the Put method.
+		Put []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// ContentType is the contentType argument value.
+			ContentType string
+			// Body is the body argument value.
+			Body []byte
+		}
+	}
+	lockChecker sync.RWMutex
+	lockGet     sync.RWMutex
+	lockPut     sync.RWMutex
+}
+
+// Checker calls CheckerFunc.
+func (mock *ContentStoreMock) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	if mock.CheckerFunc == nil {
+		panic("ContentStoreMock.CheckerFunc: method is nil but ContentStore.Checker was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		State *healthcheck.CheckState
+	}{
+		Ctx:   ctx,
+		State: state,
+	}
+	mock.lockChecker.Lock()
+	mock.calls.Checker = append(mock.calls.Checker, callInfo)
+	mock.lockChecker.Unlock()
+	return mock.CheckerFunc(ctx, state)
+}
+
+// CheckerCalls gets all the calls that were made to Checker.
+// Check the length with:
+//
+//	len(mockedContentStore.CheckerCalls())
+func (mock *ContentStoreMock) CheckerCalls() []struct {
+	Ctx   context.Context
+	State *healthcheck.CheckState
+} {
+	var calls []struct {
+		Ctx   context.Context
+		State *healthcheck.CheckState
+	}
+	mock.lockChecker.RLock()
+	calls = mock.calls.Checker
+	mock.lockChecker.RUnlock()
+	return calls
+}
+
+// Get calls GetFunc.
+func (mock *ContentStoreMock) Get(ctx context.Context, key string, opts *content.GetOptions) (*content.Object, error) {
+	if mock.GetFunc == nil {
+		panic("ContentStoreMock.GetFunc: method is nil but ContentStore.Get was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Key  string
+		Opts *content.GetOptions
+	}{
+		Ctx:  ctx,
+		Key:  key,
+		Opts: opts,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, key, opts)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedContentStore.GetCalls())
+func (mock *ContentStoreMock) GetCalls() []struct {
+	Ctx  context.Context
+	Key  string
+	Opts *content.GetOptions
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Key  string
+		Opts *content.GetOptions
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// Put calls PutFunc.
+func (mock *ContentStoreMock) Put(ctx context.Context, key string, contentType string, body []byte) error {
+	if mock.PutFunc == nil {
+		panic("ContentStoreMock.PutFunc: method is nil but ContentStore.Put was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		Key         string
+		ContentType string
+		Body        []byte
+	}{
+		Ctx:         ctx,
+		Key:         key,
+		ContentType: contentType,
+		Body:        body,
+	}
+	mock.lockPut.Lock()
+	mock.calls.Put = append(mock.calls.Put, callInfo)
+	mock.lockPut.Unlock()
+	return mock.PutFunc(ctx, key, contentType, body)
+}
+
+// PutCalls gets all the calls that were made to Put.
+// Check the length with:
+//
+//	len(mockedContentStore.PutCalls())
+func (mock *ContentStoreMock) PutCalls() []struct {
+	Ctx         context.Context
+	Key         string
+	ContentType string
+	Body        []byte
+} {
+	var calls []struct {
+		Ctx         context.Context
+		Key         string
+		ContentType string
+		Body        []byte
+	}
+	mock.lockPut.RLock()
+	calls = mock.calls.Put
+	mock.lockPut.RUnlock()
+	return calls
+}
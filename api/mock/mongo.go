@@ -18,34 +18,67 @@ var _ api.MongoServer = &MongoServerMock{}
 
 // MongoServerMock is a mock implementation of api.MongoServer.
 //
-// 	func TestSomethingThatUsesMongoServer(t *testing.T) {
+//	func TestSomethingThatUsesMongoServer(t *testing.T) {
 //
-// 		// make and configure a mocked api.MongoServer
-// 		mockedMongoServer := &MongoServerMock{
-// 			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error {
-// 				panic("mock out the Checker method")
-// 			},
-// 			CloseFunc: func(ctx context.Context) error {
-// 				panic("mock out the Close method")
-// 			},
-// 			GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
-// 				panic("mock out the GetInteractive method")
-// 			},
-// 			ListInteractivesFunc: func(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error) {
-// 				panic("mock out the ListInteractives method")
-// 			},
-// 			PatchInteractiveFunc: func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
-// 				panic("mock out the PatchInteractive method")
-// 			},
-// 			UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error {
-// 				panic("mock out the UpsertInteractive method")
-// 			},
-// 		}
+//		// make and configure a mocked api.MongoServer
+//		mockedMongoServer := &MongoServerMock{
+//			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error {
+//				panic("mock out the Checker method")
+//			},
+//			CloseFunc: func(ctx context.Context) error {
+//				panic("mock out the Close method")
+//			},
+//			CompleteOutboxEventFunc: func(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+//				panic("mock out the CompleteOutboxEvent method")
+//			},
+//			DeleteDeadLetterFunc: func(ctx context.Context, id string) error {
+//				panic("mock out the DeleteDeadLetter method")
+//			},
+//			GetDeadLetterFunc: func(ctx context.Context, id string) (*models.DeadLetter, error) {
+//				panic("mock out the GetDeadLetter method")
+//			},
+//			GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+//				panic("mock out the GetInteractive method")
+//			},
+//			GetInteractiveByResourceIDFunc: func(ctx context.Context, resourceID string) (*models.Interactive, error) {
+//				panic("mock out the GetInteractiveByResourceID method")
+//			},
+//			GetInteractiveBySHAFunc: func(ctx context.Context, sha string) (*models.Interactive, error) {
+//				panic("mock out the GetInteractiveBySHA method")
+//			},
+//			GetInteractiveBySlugFunc: func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+//				panic("mock out the GetInteractiveBySlug method")
+//			},
+//			ListDeadLettersFunc: func(ctx context.Context, offset int, limit int) ([]*models.DeadLetter, int, error) {
+//				panic("mock out the ListDeadLetters method")
+//			},
+//			ListInteractivesFunc: func(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error) {
+//				panic("mock out the ListInteractives method")
+//			},
+//			ListOutboxEventsFunc: func(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+//				panic("mock out the ListOutboxEvents method")
+//			},
+//			PatchInteractiveFunc: func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
+//				panic("mock out the PatchInteractive method")
+//			},
+//			PatchMetadataFunc: func(ctx context.Context, id string, current *models.Metadata, patched *models.Metadata, published *bool) error {
+//				panic("mock out the PatchMetadata method")
+//			},
+//			RetryOutboxEventFunc: func(ctx context.Context, e *models.OutboxEvent) error {
+//				panic("mock out the RetryOutboxEvent method")
+//			},
+//			UpsertDeadLetterFunc: func(ctx context.Context, d *models.DeadLetter) error {
+//				panic("mock out the UpsertDeadLetter method")
+//			},
+//			UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error {
+//				panic("mock out the UpsertInteractive method")
+//			},
+//		}
 //
-// 		// use mockedMongoServer in code that requires api.MongoServer
-// 		// and then make assertions.
+//		// use mockedMongoServer in code that requires api.MongoServer
+//		// and then make assertions.
 //
-// 	}
+//	}
 type MongoServerMock struct {
 	// CheckerFunc mocks the Checker method.
 	CheckerFunc func(ctx context.Context, state *healthcheck.CheckState) error
@@ -53,15 +86,48 @@ type MongoServerMock struct {
 	// CloseFunc mocks the Close method.
 	CloseFunc func(ctx context.Context) error
 
+	// CompleteOutboxEventFunc mocks the CompleteOutboxEvent method.
+	CompleteOutboxEventFunc func(ctx context.Context, e *models.OutboxEvent, state models.State) error
+
+	// DeleteDeadLetterFunc mocks the DeleteDeadLetter method.
+	DeleteDeadLetterFunc func(ctx context.Context, id string) error
+
+	// GetDeadLetterFunc mocks the GetDeadLetter method.
+	GetDeadLetterFunc func(ctx context.Context, id string) (*models.DeadLetter, error)
+
 	// GetInteractiveFunc mocks the GetInteractive method.
 	GetInteractiveFunc func(ctx context.Context, id string) (*models.Interactive, error)
 
+	// GetInteractiveByResourceIDFunc mocks the GetInteractiveByResourceID method.
+	GetInteractiveByResourceIDFunc func(ctx context.Context, resourceID string) (*models.Interactive, error)
+
+	// GetInteractiveBySHAFunc mocks the GetInteractiveBySHA method.
+	GetInteractiveBySHAFunc func(ctx context.Context, sha string) (*models.Interactive, error)
+
+	// GetInteractiveBySlugFunc mocks the GetInteractiveBySlug method.
+	GetInteractiveBySlugFunc func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error)
+
+	// ListDeadLettersFunc mocks the ListDeadLetters method.
+	ListDeadLettersFunc func(ctx context.Context, offset int, limit int) ([]*models.DeadLetter, int, error)
+
 	// ListInteractivesFunc mocks the ListInteractives method.
 	ListInteractivesFunc func(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error)
 
+	// ListOutboxEventsFunc mocks the ListOutboxEvents method.
+	ListOutboxEventsFunc func(ctx context.Context, limit int) ([]*models.OutboxEvent, error)
+
 	// PatchInteractiveFunc mocks the PatchInteractive method.
 	PatchInteractiveFunc func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error
 
+	// PatchMetadataFunc mocks the PatchMetadata method.
+	PatchMetadataFunc func(ctx context.Context, id string, current *models.Metadata, patched *models.Metadata, published *bool) error
+
+	// RetryOutboxEventFunc mocks the RetryOutboxEvent method.
+	RetryOutboxEventFunc func(ctx context.Context, e *models.OutboxEvent) error
+
+	// UpsertDeadLetterFunc mocks the UpsertDeadLetter method.
+	UpsertDeadLetterFunc func(ctx context.Context, d *models.DeadLetter) error
+
 	// UpsertInteractiveFunc mocks the UpsertInteractive method.
 	UpsertInteractiveFunc func(ctx context.Context, id string, vis *models.Interactive) error
 
@@ -79,6 +145,29 @@ type MongoServerMock struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// CompleteOutboxEvent holds details about calls to the CompleteOutboxEvent method.
+		CompleteOutboxEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *models.OutboxEvent
+			// State is the state argument value.
+			State models.State
+		}
+		// DeleteDeadLetter holds details about calls to the DeleteDeadLetter method.
+		DeleteDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
+		// GetDeadLetter holds details about calls to the GetDeadLetter method.
+		GetDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
 		// GetInteractive holds details about calls to the GetInteractive method.
 		GetInteractive []struct {
 			// Ctx is the ctx argument value.
@@ -86,6 +175,38 @@ type MongoServerMock struct {
 			// ID is the id argument value.
 			ID string
 		}
+		// GetInteractiveByResourceID holds details about calls to the GetInteractiveByResourceID method.
+		GetInteractiveByResourceID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ResourceID is the resourceID argument value.
+			ResourceID string
+		}
+		// GetInteractiveBySHA holds details about calls to the GetInteractiveBySHA method.
+		GetInteractiveBySHA []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Sha is the sha argument value.
+			Sha string
+		}
+		// GetInteractiveBySlug holds details about calls to the GetInteractiveBySlug method.
+		GetInteractiveBySlug []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Slug is the slug argument value.
+			Slug string
+			// PublishedOnly is the publishedOnly argument value.
+			PublishedOnly bool
+		}
+		// ListDeadLetters holds details about calls to the ListDeadLetters method.
+		ListDeadLetters []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Offset is the offset argument value.
+			Offset int
+			// Limit is the limit argument value.
+			Limit int
+		}
 		// ListInteractives holds details about calls to the ListInteractives method.
 		ListInteractives []struct {
 			// Ctx is the ctx argument value.
@@ -93,6 +214,13 @@ type MongoServerMock struct {
 			// Filter is the filter argument value.
 			Filter *models.Filter
 		}
+		// ListOutboxEvents holds details about calls to the ListOutboxEvents method.
+		ListOutboxEvents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+		}
 		// PatchInteractive holds details about calls to the PatchInteractive method.
 		PatchInteractive []struct {
 			// ContextMoqParam is the contextMoqParam argument value.
@@ -102,6 +230,33 @@ type MongoServerMock struct {
 			// Interactive is the interactive argument value.
 			Interactive *models.Interactive
 		}
+		// PatchMetadata holds details about calls to the PatchMetadata method.
+		PatchMetadata []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+			// Current is the current argument value.
+			Current *models.Metadata
+			// Patched is the patched argument value.
+			Patched *models.Metadata
+			// Published is the published argument value.
+			Published *bool
+		}
+		// RetryOutboxEvent holds details about calls to the RetryOutboxEvent method.
+		RetryOutboxEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *models.OutboxEvent
+		}
+		// UpsertDeadLetter holds details about calls to the UpsertDeadLetter method.
+		UpsertDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// D is the d argument value.
+			D *models.DeadLetter
+		}
 		// UpsertInteractive holds details about calls to the UpsertInteractive method.
 		UpsertInteractive []struct {
 			// Ctx is the ctx argument value.
@@ -112,12 +267,23 @@ type MongoServerMock struct {
 			Vis *models.Interactive
 		}
 	}
-	lockChecker           sync.RWMutex
-	lockClose             sync.RWMutex
-	lockGetInteractive    sync.RWMutex
-	lockListInteractives  sync.RWMutex
-	lockPatchInteractive  sync.RWMutex
-	lockUpsertInteractive sync.RWMutex
+	lockChecker                    sync.RWMutex
+	lockClose                      sync.RWMutex
+	lockCompleteOutboxEvent        sync.RWMutex
+	lockDeleteDeadLetter           sync.RWMutex
+	lockGetDeadLetter              sync.RWMutex
+	lockGetInteractive             sync.RWMutex
+	lockGetInteractiveByResourceID sync.RWMutex
+	lockGetInteractiveBySHA        sync.RWMutex
+	lockGetInteractiveBySlug       sync.RWMutex
+	lockListDeadLetters            sync.RWMutex
+	lockListInteractives           sync.RWMutex
+	lockListOutboxEvents           sync.RWMutex
+	lockPatchInteractive           sync.RWMutex
+	lockPatchMetadata              sync.RWMutex
+	lockRetryOutboxEvent           sync.RWMutex
+	lockUpsertDeadLetter           sync.RWMutex
+	lockUpsertInteractive          sync.RWMutex
 }
 
 // Checker calls CheckerFunc.
@@ -140,7 +306,8 @@ func (mock *MongoServerMock) Checker(ctx context.Context, state *healthcheck.Che
 
 // CheckerCalls gets all the calls that were made to Checker.
 // Check the length with:
-//     len(mockedMongoServer.CheckerCalls())
+//
+//	len(mockedMongoServer.CheckerCalls())
 func (mock *MongoServerMock) CheckerCalls() []struct {
 	Ctx   context.Context
 	State *healthcheck.CheckState
@@ -173,7 +340,8 @@ func (mock *MongoServerMock) Close(ctx context.Context) error {
 
 // CloseCalls gets all the calls that were made to Close.
 // Check the length with:
-//     len(mockedMongoServer.CloseCalls())
+//
+//	len(mockedMongoServer.CloseCalls())
 func (mock *MongoServerMock) CloseCalls() []struct {
 	Ctx context.Context
 } {
@@ -186,6 +354,118 @@ func (mock *MongoServerMock) CloseCalls() []struct {
 	return calls
 }
 
+// CompleteOutboxEvent calls CompleteOutboxEventFunc.
+func (mock *MongoServerMock) CompleteOutboxEvent(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+	if mock.CompleteOutboxEventFunc == nil {
+		panic("MongoServerMock.CompleteOutboxEventFunc: method is nil but MongoServer.CompleteOutboxEvent was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		E     *models.OutboxEvent
+		State models.State
+	}{
+		Ctx:   ctx,
+		E:     e,
+		State: state,
+	}
+	mock.lockCompleteOutboxEvent.Lock()
+	mock.calls.CompleteOutboxEvent = append(mock.calls.CompleteOutboxEvent, callInfo)
+	mock.lockCompleteOutboxEvent.Unlock()
+	return mock.CompleteOutboxEventFunc(ctx, e, state)
+}
+
+// CompleteOutboxEventCalls gets all the calls that were made to CompleteOutboxEvent.
+// Check the length with:
+//
+//	len(mockedMongoServer.CompleteOutboxEventCalls())
+func (mock *MongoServerMock) CompleteOutboxEventCalls() []struct {
+	Ctx   context.Context
+	E     *models.OutboxEvent
+	State models.State
+} {
+	var calls []struct {
+		Ctx   context.Context
+		E     *models.OutboxEvent
+		State models.State
+	}
+	mock.lockCompleteOutboxEvent.RLock()
+	calls = mock.calls.CompleteOutboxEvent
+	mock.lockCompleteOutboxEvent.RUnlock()
+	return calls
+}
+
+// DeleteDeadLetter calls DeleteDeadLetterFunc.
+func (mock *MongoServerMock) DeleteDeadLetter(ctx context.Context, id string) error {
+	if mock.DeleteDeadLetterFunc == nil {
+		panic("MongoServerMock.DeleteDeadLetterFunc: method is nil but MongoServer.DeleteDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteDeadLetter.Lock()
+	mock.calls.DeleteDeadLetter = append(mock.calls.DeleteDeadLetter, callInfo)
+	mock.lockDeleteDeadLetter.Unlock()
+	return mock.DeleteDeadLetterFunc(ctx, id)
+}
+
+// DeleteDeadLetterCalls gets all the calls that were made to DeleteDeadLetter.
+// Check the length with:
+//
+//	len(mockedMongoServer.DeleteDeadLetterCalls())
+func (mock *MongoServerMock) DeleteDeadLetterCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockDeleteDeadLetter.RLock()
+	calls = mock.calls.DeleteDeadLetter
+	mock.lockDeleteDeadLetter.RUnlock()
+	return calls
+}
+
+// GetDeadLetter calls GetDeadLetterFunc.
+func (mock *MongoServerMock) GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	if mock.GetDeadLetterFunc == nil {
+		panic("MongoServerMock.GetDeadLetterFunc: method is nil but MongoServer.GetDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetDeadLetter.Lock()
+	mock.calls.GetDeadLetter = append(mock.calls.GetDeadLetter, callInfo)
+	mock.lockGetDeadLetter.Unlock()
+	return mock.GetDeadLetterFunc(ctx, id)
+}
+
+// GetDeadLetterCalls gets all the calls that were made to GetDeadLetter.
+// Check the length with:
+//
+//	len(mockedMongoServer.GetDeadLetterCalls())
+func (mock *MongoServerMock) GetDeadLetterCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockGetDeadLetter.RLock()
+	calls = mock.calls.GetDeadLetter
+	mock.lockGetDeadLetter.RUnlock()
+	return calls
+}
+
 // GetInteractive calls GetInteractiveFunc.
 func (mock *MongoServerMock) GetInteractive(ctx context.Context, id string) (*models.Interactive, error) {
 	if mock.GetInteractiveFunc == nil {
@@ -206,7 +486,8 @@ func (mock *MongoServerMock) GetInteractive(ctx context.Context, id string) (*mo
 
 // GetInteractiveCalls gets all the calls that were made to GetInteractive.
 // Check the length with:
-//     len(mockedMongoServer.GetInteractiveCalls())
+//
+//	len(mockedMongoServer.GetInteractiveCalls())
 func (mock *MongoServerMock) GetInteractiveCalls() []struct {
 	Ctx context.Context
 	ID  string
@@ -221,6 +502,158 @@ func (mock *MongoServerMock) GetInteractiveCalls() []struct {
 	return calls
 }
 
+// GetInteractiveByResourceID calls GetInteractiveByResourceIDFunc.
+func (mock *MongoServerMock) GetInteractiveByResourceID(ctx context.Context, resourceID string) (*models.Interactive, error) {
+	if mock.GetInteractiveByResourceIDFunc == nil {
+		panic("MongoServerMock.GetInteractiveByResourceIDFunc: method is nil but MongoServer.GetInteractiveByResourceID was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ResourceID string
+	}{
+		Ctx:        ctx,
+		ResourceID: resourceID,
+	}
+	mock.lockGetInteractiveByResourceID.Lock()
+	mock.calls.GetInteractiveByResourceID = append(mock.calls.GetInteractiveByResourceID, callInfo)
+	mock.lockGetInteractiveByResourceID.Unlock()
+	return mock.GetInteractiveByResourceIDFunc(ctx, resourceID)
+}
+
+// GetInteractiveByResourceIDCalls gets all the calls that were made to GetInteractiveByResourceID.
+// Check the length with:
+//
+//	len(mockedMongoServer.GetInteractiveByResourceIDCalls())
+func (mock *MongoServerMock) GetInteractiveByResourceIDCalls() []struct {
+	Ctx        context.Context
+	ResourceID string
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ResourceID string
+	}
+	mock.lockGetInteractiveByResourceID.RLock()
+	calls = mock.calls.GetInteractiveByResourceID
+	mock.lockGetInteractiveByResourceID.RUnlock()
+	return calls
+}
+
+// GetInteractiveBySHA calls GetInteractiveBySHAFunc.
+func (mock *MongoServerMock) GetInteractiveBySHA(ctx context.Context, sha string) (*models.Interactive, error) {
+	if mock.GetInteractiveBySHAFunc == nil {
+		panic("MongoServerMock.GetInteractiveBySHAFunc: method is nil but MongoServer.GetInteractiveBySHA was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Sha string
+	}{
+		Ctx: ctx,
+		Sha: sha,
+	}
+	mock.lockGetInteractiveBySHA.Lock()
+	mock.calls.GetInteractiveBySHA = append(mock.calls.GetInteractiveBySHA, callInfo)
+	mock.lockGetInteractiveBySHA.Unlock()
+	return mock.GetInteractiveBySHAFunc(ctx, sha)
+}
+
+// GetInteractiveBySHACalls gets all the calls that were made to GetInteractiveBySHA.
+// Check the length with:
+//
+//	len(mockedMongoServer.GetInteractiveBySHACalls())
+func (mock *MongoServerMock) GetInteractiveBySHACalls() []struct {
+	Ctx context.Context
+	Sha string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Sha string
+	}
+	mock.lockGetInteractiveBySHA.RLock()
+	calls = mock.calls.GetInteractiveBySHA
+	mock.lockGetInteractiveBySHA.RUnlock()
+	return calls
+}
+
+// GetInteractiveBySlug calls GetInteractiveBySlugFunc.
+func (mock *MongoServerMock) GetInteractiveBySlug(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+	if mock.GetInteractiveBySlugFunc == nil {
+		panic("MongoServerMock.GetInteractiveBySlugFunc: method is nil but MongoServer.GetInteractiveBySlug was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		Slug          string
+		PublishedOnly bool
+	}{
+		Ctx:           ctx,
+		Slug:          slug,
+		PublishedOnly: publishedOnly,
+	}
+	mock.lockGetInteractiveBySlug.Lock()
+	mock.calls.GetInteractiveBySlug = append(mock.calls.GetInteractiveBySlug, callInfo)
+	mock.lockGetInteractiveBySlug.Unlock()
+	return mock.GetInteractiveBySlugFunc(ctx, slug, publishedOnly)
+}
+
+// GetInteractiveBySlugCalls gets all the calls that were made to GetInteractiveBySlug.
+// Check the length with:
+//
+//	len(mockedMongoServer.GetInteractiveBySlugCalls())
+func (mock *MongoServerMock) GetInteractiveBySlugCalls() []struct {
+	Ctx           context.Context
+	Slug          string
+	PublishedOnly bool
+} {
+	var calls []struct {
+		Ctx           context.Context
+		Slug          string
+		PublishedOnly bool
+	}
+	mock.lockGetInteractiveBySlug.RLock()
+	calls = mock.calls.GetInteractiveBySlug
+	mock.lockGetInteractiveBySlug.RUnlock()
+	return calls
+}
+
+// ListDeadLetters calls ListDeadLettersFunc.
+func (mock *MongoServerMock) ListDeadLetters(ctx context.Context, offset int, limit int) ([]*models.DeadLetter, int, error) {
+	if mock.ListDeadLettersFunc == nil {
+		panic("MongoServerMock.ListDeadLettersFunc: method is nil but MongoServer.ListDeadLetters was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Offset int
+		Limit  int
+	}{
+		Ctx:    ctx,
+		Offset: offset,
+		Limit:  limit,
+	}
+	mock.lockListDeadLetters.Lock()
+	mock.calls.ListDeadLetters = append(mock.calls.ListDeadLetters, callInfo)
+	mock.lockListDeadLetters.Unlock()
+	return mock.ListDeadLettersFunc(ctx, offset, limit)
+}
+
+// ListDeadLettersCalls gets all the calls that were made to ListDeadLetters.
+// Check the length with:
+//
+//	len(mockedMongoServer.ListDeadLettersCalls())
+func (mock *MongoServerMock) ListDeadLettersCalls() []struct {
+	Ctx    context.Context
+	Offset int
+	Limit  int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Offset int
+		Limit  int
+	}
+	mock.lockListDeadLetters.RLock()
+	calls = mock.calls.ListDeadLetters
+	mock.lockListDeadLetters.RUnlock()
+	return calls
+}
+
 // ListInteractives calls ListInteractivesFunc.
 func (mock *MongoServerMock) ListInteractives(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error) {
 	if mock.ListInteractivesFunc == nil {
@@ -241,7 +674,8 @@ func (mock *MongoServerMock) ListInteractives(ctx context.Context, filter *model
 
 // ListInteractivesCalls gets all the calls that were made to ListInteractives.
 // Check the length with:
-//     len(mockedMongoServer.ListInteractivesCalls())
+//
+//	len(mockedMongoServer.ListInteractivesCalls())
 func (mock *MongoServerMock) ListInteractivesCalls() []struct {
 	Ctx    context.Context
 	Filter *models.Filter
@@ -256,6 +690,42 @@ func (mock *MongoServerMock) ListInteractivesCalls() []struct {
 	return calls
 }
 
+// ListOutboxEvents calls ListOutboxEventsFunc.
+func (mock *MongoServerMock) ListOutboxEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+	if mock.ListOutboxEventsFunc == nil {
+		panic("MongoServerMock.ListOutboxEventsFunc: method is nil but MongoServer.ListOutboxEvents was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Limit int
+	}{
+		Ctx:   ctx,
+		Limit: limit,
+	}
+	mock.lockListOutboxEvents.Lock()
+	mock.calls.ListOutboxEvents = append(mock.calls.ListOutboxEvents, callInfo)
+	mock.lockListOutboxEvents.Unlock()
+	return mock.ListOutboxEventsFunc(ctx, limit)
+}
+
+// ListOutboxEventsCalls gets all the calls that were made to ListOutboxEvents.
+// Check the length with:
+//
+//	len(mockedMongoServer.ListOutboxEventsCalls())
+func (mock *MongoServerMock) ListOutboxEventsCalls() []struct {
+	Ctx   context.Context
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Limit int
+	}
+	mock.lockListOutboxEvents.RLock()
+	calls = mock.calls.ListOutboxEvents
+	mock.lockListOutboxEvents.RUnlock()
+	return calls
+}
+
 // PatchInteractive calls PatchInteractiveFunc.
 func (mock *MongoServerMock) PatchInteractive(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
 	if mock.PatchInteractiveFunc == nil {
@@ -278,7 +748,8 @@ func (mock *MongoServerMock) PatchInteractive(contextMoqParam context.Context, p
 
 // PatchInteractiveCalls gets all the calls that were made to PatchInteractive.
 // Check the length with:
-//     len(mockedMongoServer.PatchInteractiveCalls())
+//
+//	len(mockedMongoServer.PatchInteractiveCalls())
 func (mock *MongoServerMock) PatchInteractiveCalls() []struct {
 	ContextMoqParam context.Context
 	PatchAttribute  interactives.PatchAttribute
@@ -295,6 +766,126 @@ func (mock *MongoServerMock) PatchInteractiveCalls() []struct {
 	return calls
 }
 
+// PatchMetadata calls PatchMetadataFunc.
+func (mock *MongoServerMock) PatchMetadata(ctx context.Context, id string, current *models.Metadata, patched *models.Metadata, published *bool) error {
+	if mock.PatchMetadataFunc == nil {
+		panic("MongoServerMock.PatchMetadataFunc: method is nil but MongoServer.PatchMetadata was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		ID        string
+		Current   *models.Metadata
+		Patched   *models.Metadata
+		Published *bool
+	}{
+		Ctx:       ctx,
+		ID:        id,
+		Current:   current,
+		Patched:   patched,
+		Published: published,
+	}
+	mock.lockPatchMetadata.Lock()
+	mock.calls.PatchMetadata = append(mock.calls.PatchMetadata, callInfo)
+	mock.lockPatchMetadata.Unlock()
+	return mock.PatchMetadataFunc(ctx, id, current, patched, published)
+}
+
+// PatchMetadataCalls gets all the calls that were made to PatchMetadata.
+// Check the length with:
+//
+//	len(mockedMongoServer.PatchMetadataCalls())
+func (mock *MongoServerMock) PatchMetadataCalls() []struct {
+	Ctx       context.Context
+	ID        string
+	Current   *models.Metadata
+	Patched   *models.Metadata
+	Published *bool
+} {
+	var calls []struct {
+		Ctx       context.Context
+		ID        string
+		Current   *models.Metadata
+		Patched   *models.Metadata
+		Published *bool
+	}
+	mock.lockPatchMetadata.RLock()
+	calls = mock.calls.PatchMetadata
+	mock.lockPatchMetadata.RUnlock()
+	return calls
+}
+
+// RetryOutboxEvent calls RetryOutboxEventFunc.
+func (mock *MongoServerMock) RetryOutboxEvent(ctx context.Context, e *models.OutboxEvent) error {
+	if mock.RetryOutboxEventFunc == nil {
+		panic("MongoServerMock.RetryOutboxEventFunc: method is nil but MongoServer.RetryOutboxEvent was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		E   *models.OutboxEvent
+	}{
+		Ctx: ctx,
+		E:   e,
+	}
+	mock.lockRetryOutboxEvent.Lock()
+	mock.calls.RetryOutboxEvent = append(mock.calls.RetryOutboxEvent, callInfo)
+	mock.lockRetryOutboxEvent.Unlock()
+	return mock.RetryOutboxEventFunc(ctx, e)
+}
+
+// RetryOutboxEventCalls gets all the calls that were made to RetryOutboxEvent.
+// Check the length with:
+//
+//	len(mockedMongoServer.RetryOutboxEventCalls())
+func (mock *MongoServerMock) RetryOutboxEventCalls() []struct {
+	Ctx context.Context
+	E   *models.OutboxEvent
+} {
+	var calls []struct {
+		Ctx context.Context
+		E   *models.OutboxEvent
+	}
+	mock.lockRetryOutboxEvent.RLock()
+	calls = mock.calls.RetryOutboxEvent
+	mock.lockRetryOutboxEvent.RUnlock()
+	return calls
+}
+
+// UpsertDeadLetter calls UpsertDeadLetterFunc.
+func (mock *MongoServerMock) UpsertDeadLetter(ctx context.Context, d *models.DeadLetter) error {
+	if mock.UpsertDeadLetterFunc == nil {
+		panic("MongoServerMock.UpsertDeadLetterFunc: method is nil but MongoServer.UpsertDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		D   *models.DeadLetter
+	}{
+		Ctx: ctx,
+		D:   d,
+	}
+	mock.lockUpsertDeadLetter.Lock()
+	mock.calls.UpsertDeadLetter = append(mock.calls.UpsertDeadLetter, callInfo)
+	mock.lockUpsertDeadLetter.Unlock()
+	return mock.UpsertDeadLetterFunc(ctx, d)
+}
+
+// UpsertDeadLetterCalls gets all the calls that were made to UpsertDeadLetter.
+// Check the length with:
+//
+//	len(mockedMongoServer.UpsertDeadLetterCalls())
+func (mock *MongoServerMock) UpsertDeadLetterCalls() []struct {
+	Ctx context.Context
+	D   *models.DeadLetter
+} {
+	var calls []struct {
+		Ctx context.Context
+		D   *models.DeadLetter
+	}
+	mock.lockUpsertDeadLetter.RLock()
+	calls = mock.calls.UpsertDeadLetter
+	mock.lockUpsertDeadLetter.RUnlock()
+	return calls
+}
+
 // UpsertInteractive calls UpsertInteractiveFunc.
 func (mock *MongoServerMock) UpsertInteractive(ctx context.Context, id string, vis *models.Interactive) error {
 	if mock.UpsertInteractiveFunc == nil {
@@ -317,7 +908,8 @@ func (mock *MongoServerMock) UpsertInteractive(ctx context.Context, id string, v
 
 // UpsertInteractiveCalls gets all the calls that were made to UpsertInteractive.
 // Check the length with:
-//     len(mockedMongoServer.UpsertInteractiveCalls())
+//
+//	len(mockedMongoServer.UpsertInteractiveCalls())
 func (mock *MongoServerMock) UpsertInteractiveCalls() []struct {
 	Ctx context.Context
 	ID  string
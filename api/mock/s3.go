@@ -8,6 +8,7 @@ import (
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-interactives-api/api"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"io"
 	"sync"
 )
 
@@ -17,29 +18,35 @@ var _ api.S3Interface = &S3InterfaceMock{}
 
 // S3InterfaceMock is a mock implementation of api.S3Interface.
 //
-// 	func TestSomethingThatUsesS3Interface(t *testing.T) {
+//	func TestSomethingThatUsesS3Interface(t *testing.T) {
 //
-// 		// make and configure a mocked api.S3Interface
-// 		mockedS3Interface := &S3InterfaceMock{
-// 			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error {
-// 				panic("mock out the Checker method")
-// 			},
-// 			UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
-// 				panic("mock out the Upload method")
-// 			},
-// 			ValidateBucketFunc: func() error {
-// 				panic("mock out the ValidateBucket method")
-// 			},
-// 		}
+//		// make and configure a mocked api.S3Interface
+//		mockedS3Interface := &S3InterfaceMock{
+//			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error {
+//				panic("mock out the Checker method")
+//			},
+//			GetFunc: func(key string) (io.ReadCloser, *int64, error) {
+//				panic("mock out the Get method")
+//			},
+//			UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+//				panic("mock out the Upload method")
+//			},
+//			ValidateBucketFunc: func() error {
+//				panic("mock out the ValidateBucket method")
+//			},
+//		}
 //
-// 		// use mockedS3Interface in code that requires api.S3Interface
-// 		// and then make assertions.
+//		// use mockedS3Interface in code that requires api.S3Interface
+//		// and then make assertions.
 //
-// 	}
+//	}
 type S3InterfaceMock struct {
 	// CheckerFunc mocks the Checker method.
 	CheckerFunc func(ctx context.Context, state *healthcheck.CheckState) error
 
+	// GetFunc mocks the Get method.
+	GetFunc func(key string) (io.ReadCloser, *int64, error)
+
 	// UploadFunc mocks the Upload method.
 	UploadFunc func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error)
 
@@ -55,6 +62,11 @@ type S3InterfaceMock struct {
 			// State is the state argument value.
 			State *healthcheck.CheckState
 		}
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Key is the key argument value.
+			Key string
+		}
 		// Upload holds details about calls to the Upload method.
 		Upload []struct {
 			// Input is the input argument value.
@@ -67,6 +79,7 @@ type S3InterfaceMock struct {
 		}
 	}
 	lockChecker        sync.RWMutex
+	lockGet            sync.RWMutex
 	lockUpload         sync.RWMutex
 	lockValidateBucket sync.RWMutex
 }
@@ -91,7 +104,8 @@ func (mock *S3InterfaceMock) Checker(ctx context.Context, state *healthcheck.Che
 
 // CheckerCalls gets all the calls that were made to Checker.
 // Check the length with:
-//     len(mockedS3Interface.CheckerCalls())
+//
+//	len(mockedS3Interface.CheckerCalls())
 func (mock *S3InterfaceMock) CheckerCalls() []struct {
 	Ctx   context.Context
 	State *healthcheck.CheckState
@@ -106,6 +120,38 @@ func (mock *S3InterfaceMock) CheckerCalls() []struct {
 	return calls
 }
 
+// Get calls GetFunc.
+func (mock *S3InterfaceMock) Get(key string) (io.ReadCloser, *int64, error) {
+	if mock.GetFunc == nil {
+		panic("S3InterfaceMock.GetFunc: method is nil but S3Interface.Get was just called")
+	}
+	callInfo := struct {
+		Key string
+	}{
+		Key: key,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(key)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedS3Interface.GetCalls())
+func (mock *S3InterfaceMock) GetCalls() []struct {
+	Key string
+} {
+	var calls []struct {
+		Key string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
 // Upload calls UploadFunc.
 func (mock *S3InterfaceMock) Upload(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
 	if mock.UploadFunc == nil {
@@ -126,7 +172,8 @@ func (mock *S3InterfaceMock) Upload(input *s3manager.UploadInput, options ...fun
 
 // UploadCalls gets all the calls that were made to Upload.
 // Check the length with:
-//     len(mockedS3Interface.UploadCalls())
+//
+//	len(mockedS3Interface.UploadCalls())
 func (mock *S3InterfaceMock) UploadCalls() []struct {
 	Input   *s3manager.UploadInput
 	Options []func(*s3manager.Uploader)
@@ -156,7 +203,8 @@ func (mock *S3InterfaceMock) ValidateBucket() error {
 
 // ValidateBucketCalls gets all the calls that were made to ValidateBucket.
 // Check the length with:
-//     len(mockedS3Interface.ValidateBucketCalls())
+//
+//	len(mockedS3Interface.ValidateBucketCalls())
 func (mock *S3InterfaceMock) ValidateBucketCalls() []struct {
 } {
 	var calls []struct {
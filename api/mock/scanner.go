@@ -0,0 +1,135 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"context"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-interactives-api/api"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
+	"io"
+	"sync"
+)
+
+// Ensure, that ScannerMock does implement api.Scanner.
+// If this is not the case, regenerate this file with moq.
+var _ api.Scanner = &ScannerMock{}
+
+// ScannerMock is a mock implementation of api.Scanner.
+//
+//	func TestSomethingThatUsesScanner(t *testing.T) {
+//
+//		// make and configure a mocked api.Scanner
+//		mockedScanner := &ScannerMock{
+//			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error {
+//				panic("mock out the Checker method")
+//			},
+//			ScanFunc: func(ctx context.Context, r io.Reader) (*scan.Result, error) {
+//				panic("mock out the Scan method")
+//			},
+//		}
+//
+//		// use mockedScanner in code that requires api.Scanner
+//		// and then make assertions.
+//
+//	}
+type ScannerMock struct {
+	// CheckerFunc mocks the Checker method.
+	CheckerFunc func(ctx context.Context, state *healthcheck.CheckState) error
+
+	// ScanFunc mocks the Scan method.
+	ScanFunc func(ctx context.Context, r io.Reader) (*scan.Result, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Checker holds details about calls to the Checker method.
+		Checker []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// State is the state argument value.
+			State *healthcheck.CheckState
+		}
+		// Scan holds details about calls to the Scan method.
+		Scan []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// R is the r argument value.
+			R io.Reader
+		}
+	}
+	lockChecker sync.RWMutex
+	lockScan    sync.RWMutex
+}
+
+// Checker calls CheckerFunc.
+func (mock *ScannerMock) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	if mock.CheckerFunc == nil {
+		panic("ScannerMock.CheckerFunc: method is nil but Scanner.Checker was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		State *healthcheck.CheckState
+	}{
+		Ctx:   ctx,
+		State: state,
+	}
+	mock.lockChecker.Lock()
+	mock.calls.Checker = append(mock.calls.Checker, callInfo)
+	mock.lockChecker.Unlock()
+	return mock.CheckerFunc(ctx, state)
+}
+
+// CheckerCalls gets all the calls that were made to Checker.
+// Check the length with:
+//
+//	len(mockedScanner.CheckerCalls())
+func (mock *ScannerMock) CheckerCalls() []struct {
+	Ctx   context.Context
+	State *healthcheck.CheckState
+} {
+	var calls []struct {
+		Ctx   context.Context
+		State *healthcheck.CheckState
+	}
+	mock.lockChecker.RLock()
+	calls = mock.calls.Checker
+	mock.lockChecker.RUnlock()
+	return calls
+}
+
+// Scan calls ScanFunc.
+func (mock *ScannerMock) Scan(ctx context.Context, r io.Reader) (*scan.Result, error) {
+	if mock.ScanFunc == nil {
+		panic("ScannerMock.ScanFunc: method is nil but Scanner.Scan was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		R   io.Reader
+	}{
+		Ctx: ctx,
+		R:   r,
+	}
+	mock.lockScan.Lock()
+	mock.calls.Scan = append(mock.calls.Scan, callInfo)
+	mock.lockScan.Unlock()
+	return mock.ScanFunc(ctx, r)
+}
+
+// ScanCalls gets all the calls that were made to Scan.
+// Check the length with:
+//
+//	len(mockedScanner.ScanCalls())
+func (mock *ScannerMock) ScanCalls() []struct {
+	Ctx context.Context
+	R   io.Reader
+} {
+	var calls []struct {
+		Ctx context.Context
+		R   io.Reader
+	}
+	mock.lockScan.RLock()
+	calls = mock.calls.Scan
+	mock.lockScan.RUnlock()
+	return calls
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/patch"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+)
+
+const (
+	// MergePatchContentType marks a PATCH body as an RFC 7396 merge patch of the interactive
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType marks a PATCH body as an RFC 6902 JSON patch of the interactive
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+var (
+	errOnlyMetadataMerged = errors.New("only metadata can be changed with a merge patch")
+	errPathNotPatchable   = errors.New("path cannot be patched")
+	errCannotPublish      = errors.New("interactive not in correct state to publish")
+)
+
+// patchablePaths are the members of an interactive a JSON patch may change, along with everything below them, and
+// the permission needed to change each
+var patchablePaths = map[string]string{
+	"/metadata/title":         InteractivesUpdatePermission,
+	"/metadata/label":         InteractivesUpdatePermission,
+	"/metadata/internal_id":   InteractivesUpdatePermission,
+	"/metadata/collection_id": InteractivesUpdatePermission,
+	"/metadata/slug":          InteractivesUpdatePermission,
+	"/metadata/entry_point":   InteractivesUpdatePermission,
+	"/metadata/translations":  InteractivesUpdatePermission,
+	"/metadata/summary":       InteractivesUpdatePermission,
+	"/metadata/keywords":      InteractivesUpdatePermission,
+	"/metadata/related_link":  InteractivesUpdatePermission,
+	"/metadata/topics":        InteractivesUpdatePermission,
+	"/metadata/contact":       InteractivesUpdatePermission,
+	"/metadata/accessibility": InteractivesUpdatePermission,
+	"/metadata/thumbnail":     InteractivesUpdatePermission,
+	"/published":              InteractivesAdminPermission,
+}
+
+// patchable is the part of an interactive a JSON patch is applied to
+type patchable struct {
+	Metadata  *models.Metadata `json:"metadata"`
+	Published *bool            `json:"published,omitempty"`
+}
+
+// isContentType is true if the request body has the media type, ignoring any parameters
+func isContentType(r *http.Request, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == contentType
+}
+
+// mergeMetadata applies a merge patch to the interactive's metadata, where a null clears a field
+func (api *API) mergeMetadata(w http.ResponseWriter, r *http.Request, existing *models.Interactive) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("cannot read request body %w", err))
+		return
+	}
+	var members map[string]json.RawMessage
+	if err = json.Unmarshal(body, &members); err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("cannot unmarshal request body %w", err))
+		return
+	}
+	changes, ok := members["metadata"]
+	if len(members) != 1 || !ok || string(changes) == "null" {
+		api.respond.Error(ctx, w, http.StatusBadRequest, errOnlyMetadataMerged)
+		return
+	}
+
+	current := existing.Metadata
+	if current == nil {
+		current = &models.Metadata{}
+	}
+	doc, err := json.Marshal(current)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	merged, err := patch.Merge(doc, changes)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	var patched *models.Metadata
+	if err = json.Unmarshal(merged, &patched); err != nil || patched == nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("cannot unmarshal patched metadata %w", err))
+		return
+	}
+	api.savePatched(w, r, existing, patched, nil)
+}
+
+// savePatched stores metadata patched by hand, validated as the metadata of an upload would be and with the slugs
+// changing as they would on any other update. Published is only written when given.
+func (api *API) savePatched(w http.ResponseWriter, r *http.Request, existing *models.Interactive, patched *models.Metadata, published *bool) {
+	ctx := r.Context()
+	if errs := validateInteractive(ctx, &models.Interactive{Metadata: patched}); len(errs) > 0 {
+		api.respond.Errors(ctx, w, http.StatusBadRequest, errs)
+		return
+	}
+
+	current := existing.Metadata
+	if current == nil {
+		current = &models.Metadata{}
+	}
+	slugs := slugUpdate(current, patched)
+	if status, err := api.checkSlug(ctx, r, existing, slugs); err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	metadata := current.Merge(patched).Update(slugs, api.newSlug)
+	if patched.EntryPoint != current.EntryPoint {
+		var err error
+		metadata.EntryPoint, err = models.ResolveEntryPoint(patched.EntryPoint, "", existing.ArchiveHTMLFiles())
+		if err != nil {
+			api.respond.Error(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	// only what the patch changed is written
+	err := api.mongoDB.PatchMetadata(ctx, existing.ID, current, metadata, published)
+	if errors.Is(err, mongo.ErrSlugTaken) {
+		api.respond.Error(ctx, w, http.StatusConflict, fmt.Errorf("%w: %s", ErrSlugTaken, metadata.HumanReadableSlug))
+		return
+	}
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("error patching interactive %s %w", existing.ID, err))
+		return
+	}
+	api.GetInteractiveHandler(w, r)
+}
+
+// jsonPatch applies a JSON patch to the interactive's metadata and published flag. Every path the patch changes must
+// be patchable by the caller; test operations may read any path.
+func (api *API) jsonPatch(w http.ResponseWriter, r *http.Request, existing *models.Interactive) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("cannot read request body %w", err))
+		return
+	}
+	ops, err := patch.Decode(body)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	for _, op := range ops {
+		if status, err := api.checkPatchPaths(r, op); err != nil {
+			api.respond.Error(ctx, w, status, err)
+			return
+		}
+	}
+
+	doc, err := json.Marshal(patchable{Metadata: existing.Metadata, Published: existing.Published})
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+	applied, err := patch.Apply(doc, ops)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, patch.ErrTestFailed) {
+			status = http.StatusConflict
+		}
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	var patched patchable
+	if err = json.Unmarshal(applied, &patched); err != nil || patched.Metadata == nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, fmt.Errorf("cannot unmarshal patched interactive %w", err))
+		return
+	}
+
+	var published *bool
+	if patched.Published != nil && (existing.Published == nil || *patched.Published != *existing.Published) {
+		published = patched.Published
+		if *published {
+			if !existing.CanPublish() {
+				api.respond.Error(ctx, w, http.StatusConflict, errCannotPublish)
+				return
+			}
+			// as when its collection is published
+			patched.Metadata.CollectionID = ""
+		}
+	}
+	api.savePatched(w, r, existing, patched.Metadata, published)
+}
+
+// checkPatchPaths checks the caller may change the paths of an operation - for a move, the path it comes from too
+func (api *API) checkPatchPaths(r *http.Request, op patch.Operation) (int, error) {
+	if op.Op == patch.OpTest {
+		return http.StatusOK, nil
+	}
+	paths := []string{op.Path}
+	if op.Op == patch.OpMove {
+		paths = append(paths, op.From)
+	}
+	for _, path := range paths {
+		permission, ok := patchPermission(path)
+		if !ok {
+			return http.StatusBadRequest, fmt.Errorf("%w: %q", errPathNotPatchable, path)
+		}
+		if !api.hasPermission(r, permission) {
+			return http.StatusForbidden, fmt.Errorf("%w: %q needs %s", errPathNotPatchable, path, permission)
+		}
+	}
+	return http.StatusOK, nil
+}
+
+func patchPermission(path string) (string, bool) {
+	for patchable, permission := range patchablePaths {
+		if path == patchable || strings.HasPrefix(path, patchable+"/") {
+			return permission, true
+		}
+	}
+	return "", false
+}
+
+// slugUpdate is the part of patched metadata that Update applies, so that the slugs are given by hand - when the
+// patch changes them - or else follow the labels
+func slugUpdate(current, patched *models.Metadata) *models.Metadata {
+	update := &models.Metadata{Label: patched.Label}
+	if patched.HumanReadableSlug != current.HumanReadableSlug {
+		update.HumanReadableSlug = patched.HumanReadableSlug
+	}
+	for lang, t := range patched.Translations {
+		if t == nil {
+			continue
+		}
+		if update.Translations == nil {
+			update.Translations = map[string]*models.Translation{}
+		}
+		translation := &models.Translation{Title: t.Title, Label: t.Label}
+		if was := current.Translations[lang]; was == nil || t.HumanReadableSlug != was.HumanReadableSlug {
+			translation.HumanReadableSlug = t.HumanReadableSlug
+		}
+		update.Translations[lang] = translation
+	}
+	return update
+}
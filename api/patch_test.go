@@ -0,0 +1,240 @@
+package api_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatchMetadata(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	nonAdmin := denyPermissions(api.InteractivesAdminPermission)
+	existing := func(published bool) *models.Interactive {
+		return &models.Interactive{
+			ID:        "an-id",
+			Active:    &on,
+			Published: &published,
+			State:     models.ImportSuccess.String(),
+			Archive:   &models.Archive{},
+			HTMLFiles: []*models.HTMLFile{{Name: "index.html", URI: "index.html"}, {Name: "two.html", URI: "charts/two.html"}},
+			Metadata: &models.Metadata{
+				Title:             "title",
+				Label:             "label",
+				InternalID:        "internal",
+				CollectionID:      "collection",
+				HumanReadableSlug: "label",
+				ResourceID:        "resource",
+				EntryPoint:        "index.html",
+				Summary:           "summary",
+				Keywords:          []string{"gdp"},
+				Contact:           &models.Contact{Name: "name", Email: "name@ons.gov.uk", Telephone: "01234"},
+			},
+		}
+	}
+
+	tests := []struct {
+		title        string
+		published    bool
+		contentType  string
+		body         string
+		expectedCode int
+		check        func(t *testing.T, m *models.Metadata)
+	}{
+		{"WhenNull_ThenFieldCleared", false, api.MergePatchContentType, `{"metadata":{"collection_id":null,"keywords":null}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Empty(t, m.CollectionID)
+			require.Nil(t, m.Keywords)
+			require.Equal(t, "summary", m.Summary)
+			require.Equal(t, "resource", m.ResourceID)
+		}},
+		{"WhenNestedNull_ThenOnlyNestedFieldCleared", false, api.MergePatchContentType, `{"metadata":{"contact":{"telephone":null},"summary":"new"}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, &models.Contact{Name: "name", Email: "name@ons.gov.uk"}, m.Contact)
+			require.Equal(t, "new", m.Summary)
+			require.Equal(t, "collection", m.CollectionID)
+		}},
+		{"WhenContentTypeHasParameters_ThenMerged", false, api.MergePatchContentType + "; charset=utf-8", `{"metadata":{"summary":null}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Empty(t, m.Summary)
+		}},
+		{"WhenLabelChanged_ThenSlugFollows", false, api.MergePatchContentType, `{"metadata":{"label":"newlabel"}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, "newlabel", m.Label)
+			require.Equal(t, "newlabel", m.HumanReadableSlug)
+			require.Equal(t, []string{"label"}, m.PreviousSlugs)
+		}},
+		{"WhenPublishedLabelChanged_ThenSlugKept", true, api.MergePatchContentType, `{"metadata":{"label":"newlabel"}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, "newlabel", m.Label)
+			require.Equal(t, "label", m.HumanReadableSlug)
+		}},
+		{"WhenResourceIDPatched_ThenIgnored", false, api.MergePatchContentType, `{"metadata":{"resource_id":"other","previous_slugs":["x"]}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, "resource", m.ResourceID)
+			require.Empty(t, m.PreviousSlugs)
+		}},
+		{"WhenEntryPointChanged_ThenResolved", false, api.MergePatchContentType, `{"metadata":{"entry_point":"/charts/two.html"}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, "charts/two.html", m.EntryPoint)
+		}},
+		{"WhenEntryPointCleared_ThenDefault", false, api.MergePatchContentType, `{"metadata":{"entry_point":null}}`, http.StatusOK, func(t *testing.T, m *models.Metadata) {
+			require.Equal(t, models.DefaultEntryPoint, m.EntryPoint)
+		}},
+		{"WhenEntryPointNotInArchive_ThenBadRequest", false, api.MergePatchContentType, `{"metadata":{"entry_point":"missing.html"}}`, http.StatusBadRequest, nil},
+		{"WhenRequiredFieldCleared_ThenBadRequest", false, api.MergePatchContentType, `{"metadata":{"title":null}}`, http.StatusBadRequest, nil},
+		{"WhenInvalidValue_ThenBadRequest", false, api.MergePatchContentType, `{"metadata":{"contact":{"email":"not-an-email"}}}`, http.StatusBadRequest, nil},
+		{"WhenSlugTaken_ThenConflict", false, api.MergePatchContentType, `{"metadata":{"slug":"taken-slug"}}`, http.StatusConflict, nil},
+		{"WhenOtherMembers_ThenBadRequest", false, api.MergePatchContentType, `{"state":"ImportSuccess"}`, http.StatusBadRequest, nil},
+		{"WhenMetadataNull_ThenBadRequest", false, api.MergePatchContentType, `{"metadata":null}`, http.StatusBadRequest, nil},
+		{"WhenNotJSON_ThenBadRequest", false, api.MergePatchContentType, `{"metadata":`, http.StatusBadRequest, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			mongoServer := &apiMock.MongoServerMock{
+				GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+					return existing(tc.published), nil
+				},
+				GetInteractiveBySlugFunc: func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+					if slug == "taken-slug" {
+						return &models.Interactive{ID: "other-id"}, nil
+					}
+					return nil, mongo.ErrNoRecordFound
+				},
+				PatchMetadataFunc: func(ctx context.Context, id string, current, patched *models.Metadata, published *bool) error {
+					return nil
+				},
+			}
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), nonAdmin, mongoServer, nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, func(label string) string { return label }, respondr)
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/interactives/an-id", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+
+			patches := mongoServer.PatchMetadataCalls()
+			if tc.check == nil {
+				require.Empty(t, patches)
+				return
+			}
+			require.Len(t, patches, 1)
+			require.Equal(t, "an-id", patches[0].ID)
+			require.Equal(t, existing(tc.published).Metadata, patches[0].Current)
+			tc.check(t, patches[0].Patched)
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	nonAdmin := denyPermissions(api.InteractivesAdminPermission)
+	existing := func(state models.State) *models.Interactive {
+		return &models.Interactive{
+			ID:        "an-id",
+			Active:    &on,
+			Published: &off,
+			State:     state.String(),
+			Archive:   &models.Archive{},
+			HTMLFiles: []*models.HTMLFile{{Name: "index.html", URI: "index.html"}},
+			Metadata: &models.Metadata{
+				Title:             "title",
+				Label:             "label",
+				InternalID:        "internal",
+				CollectionID:      "collection",
+				HumanReadableSlug: "label",
+				ResourceID:        "resource",
+				EntryPoint:        "index.html",
+				Keywords:          []string{"gdp", "cpi"},
+			},
+		}
+	}
+
+	tests := []struct {
+		title        string
+		admin        bool
+		state        models.State
+		body         string
+		expectedCode int
+		check        func(t *testing.T, i *models.Interactive)
+	}{
+		{"WhenFieldsChanged_ThenPatched", false, models.ImportSuccess, `[{"op":"replace","path":"/metadata/title","value":"new"},{"op":"add","path":"/metadata/keywords/-","value":"rpi"},{"op":"remove","path":"/metadata/collection_id"}]`, http.StatusOK, func(t *testing.T, i *models.Interactive) {
+			require.Equal(t, "new", i.Metadata.Title)
+			require.Equal(t, []string{"gdp", "cpi", "rpi"}, i.Metadata.Keywords)
+			require.Empty(t, i.Metadata.CollectionID)
+			require.Equal(t, "resource", i.Metadata.ResourceID)
+			require.Nil(t, i.Published)
+		}},
+		{"WhenTestPasses_ThenPatched", false, models.ImportSuccess, `[{"op":"test","path":"/metadata/resource_id","value":"resource"},{"op":"add","path":"/metadata/summary","value":"summary"}]`, http.StatusOK, func(t *testing.T, i *models.Interactive) {
+			require.Equal(t, "summary", i.Metadata.Summary)
+		}},
+		{"WhenLabelChanged_ThenSlugFollows", false, models.ImportSuccess, `[{"op":"replace","path":"/metadata/label","value":"newlabel"}]`, http.StatusOK, func(t *testing.T, i *models.Interactive) {
+			require.Equal(t, "newlabel", i.Metadata.HumanReadableSlug)
+			require.Equal(t, []string{"label"}, i.Metadata.PreviousSlugs)
+		}},
+		{"WhenAdminPublishes_ThenPublishedAndUnlinked", true, models.ImportSuccess, `[{"op":"replace","path":"/published","value":true}]`, http.StatusOK, func(t *testing.T, i *models.Interactive) {
+			require.True(t, *i.Published)
+			require.Empty(t, i.Metadata.CollectionID)
+		}},
+		{"WhenAdminPublishesFailedImport_ThenConflict", true, models.ImportFailure, `[{"op":"replace","path":"/published","value":true}]`, http.StatusConflict, nil},
+		{"WhenNotAdminPublishes_ThenForbidden", false, models.ImportSuccess, `[{"op":"replace","path":"/published","value":true}]`, http.StatusForbidden, nil},
+		{"WhenPathNotAllowed_ThenBadRequest", true, models.ImportSuccess, `[{"op":"replace","path":"/metadata/resource_id","value":"other"}]`, http.StatusBadRequest, nil},
+		{"WhenWholeMetadataReplaced_ThenBadRequest", true, models.ImportSuccess, `[{"op":"replace","path":"/metadata","value":{}}]`, http.StatusBadRequest, nil},
+		{"WhenMovedFromPathNotAllowed_ThenBadRequest", true, models.ImportSuccess, `[{"op":"move","from":"/metadata/resource_id","path":"/metadata/summary"}]`, http.StatusBadRequest, nil},
+		{"WhenTestFails_ThenConflict", false, models.ImportSuccess, `[{"op":"test","path":"/metadata/title","value":"other"},{"op":"replace","path":"/metadata/title","value":"new"}]`, http.StatusConflict, nil},
+		{"WhenRequiredFieldRemoved_ThenBadRequest", false, models.ImportSuccess, `[{"op":"remove","path":"/metadata/title"}]`, http.StatusBadRequest, nil},
+		{"WhenInvalidValue_ThenBadRequest", false, models.ImportSuccess, `[{"op":"add","path":"/metadata/contact","value":{"name":"name","email":"not-an-email"}}]`, http.StatusBadRequest, nil},
+		{"WhenWrongType_ThenBadRequest", false, models.ImportSuccess, `[{"op":"replace","path":"/metadata/title","value":1}]`, http.StatusBadRequest, nil},
+		{"WhenMissingPath_ThenBadRequest", false, models.ImportSuccess, `[{"op":"replace","path":"/metadata/summary","value":"summary"}]`, http.StatusBadRequest, nil},
+		{"WhenSlugTaken_ThenConflict", false, models.ImportSuccess, `[{"op":"replace","path":"/metadata/slug","value":"taken-slug"}]`, http.StatusConflict, nil},
+		{"WhenNotAPatch_ThenBadRequest", false, models.ImportSuccess, `{"metadata":{"title":"new"}}`, http.StatusBadRequest, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			mongoServer := &apiMock.MongoServerMock{
+				GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+					return existing(tc.state), nil
+				},
+				GetInteractiveBySlugFunc: func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+					if slug == "taken-slug" {
+						return &models.Interactive{ID: "other-id"}, nil
+					}
+					return nil, mongo.ErrNoRecordFound
+				},
+				PatchMetadataFunc: func(ctx context.Context, id string, current, patched *models.Metadata, published *bool) error {
+					return nil
+				},
+			}
+			permissions := nonAdmin
+			if tc.admin {
+				permissions = denyPermissions()
+			}
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), permissions, mongoServer, nil, nil, nil, nil, nil, validInteractiveIdGen, noopGen, func(label string) string { return label }, respondr)
+
+			req := httptest.NewRequest(http.MethodPatch, "/v1/interactives/an-id", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", api.JSONPatchContentType)
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code, resp.Body.String())
+
+			patches := mongoServer.PatchMetadataCalls()
+			if tc.check == nil {
+				require.Empty(t, patches)
+				return
+			}
+			require.Len(t, patches, 1)
+			require.Equal(t, "an-id", patches[0].ID)
+			tc.check(t, &models.Interactive{Metadata: patches[0].Patched, Published: patches[0].Published})
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+// GetInteractiveByResourceIDHandler gets an interactive by the resource id in its public uri
+func (api *API) GetInteractiveByResourceIDHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	lang, err := language(r)
+	if err != nil {
+		api.respond.Error(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+	interactive, status, err := api.getInteractiveByResourceID(ctx, mux.Vars(r)["resource_id"])
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	if lang != "" {
+		interactive.Localise(lang)
+	}
+
+	api.respond.JSON(ctx, w, http.StatusOK, interactive)
+}
+
+// ResolveInteractiveHandler finds the interactive a public path ({slug}-{resource_id}) refers to. The resource id
+// identifies it. Without one, the slug is looked up, including slugs interactives have had before. A path that
+// is not canonical (e.g. an old slug) resolves with a hint to redirect to the canonical uri.
+func (api *API) ResolveInteractiveHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	path := models.PublicPathSegment(mux.Vars(r)["path"])
+
+	var interactive *models.Interactive
+	status, err := http.StatusNotFound, fmt.Errorf("%s is not an interactive path", path)
+	slug, resourceID, ok := models.ParsePublicPath(path)
+	if ok {
+		interactive, status, err = api.getInteractiveByResourceID(ctx, resourceID)
+	}
+	if status == http.StatusNotFound && path != "" {
+		// a slug can end in something that looks like a resource id
+		slug, resourceID = path, ""
+		interactive, status, err = api.getInteractiveBySlug(ctx, slug)
+	}
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+
+	resolution := interactive.Resolve(slug, resourceID)
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"canonical\"", resolution.CanonicalURI))
+	api.respond.JSON(ctx, w, http.StatusOK, resolution)
+}
+
+func (api *API) getInteractiveByResourceID(ctx context.Context, resourceID string) (*models.Interactive, int, error) {
+	log.Info(ctx, "GetInteractiveByResourceID", log.Data{"resource_id": resourceID})
+	i, err := api.mongoDB.GetInteractiveByResourceID(ctx, resourceID)
+	if err != nil && err != mongo.ErrNoRecordFound {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", resourceID, err)
+	}
+
+	if api.blockAccess(i) {
+		return nil, http.StatusNotFound, fmt.Errorf("interactive either deleted or does not exist %s", resourceID)
+	}
+
+	return i, http.StatusOK, nil
+}
+
+func (api *API) getInteractiveBySlug(ctx context.Context, slug string) (*models.Interactive, int, error) {
+	log.Info(ctx, "GetInteractiveBySlug", log.Data{"slug": slug})
+	i, err := api.mongoDB.GetInteractiveBySlug(ctx, slug, !api.cfg.PublishingEnabled)
+	if err != nil && err != mongo.ErrNoRecordFound {
+		return nil, http.StatusInternalServerError, fmt.Errorf("error fetching interactive %s %w", slug, err)
+	}
+
+	if api.blockAccess(i) {
+		return nil, http.StatusNotFound, fmt.Errorf("interactive either deleted or does not exist %s", slug)
+	}
+
+	return i, http.StatusOK, nil
+}
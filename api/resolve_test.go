@@ -0,0 +1,123 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveInteractiveHandlers(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	held := func(published bool) *models.Interactive {
+		i := &models.Interactive{
+			ID:        "an-id",
+			Active:    &on,
+			Published: &published,
+			Metadata: &models.Metadata{
+				Title:             "title",
+				Label:             "label",
+				HumanReadableSlug: "gdp-growth",
+				ResourceID:        "abcd1234",
+				PreviousSlugs:     []string{"gdp", "economic-growth"},
+			},
+		}
+		i.SetJSONAttribs("")
+		return i
+	}
+	byResourceID := func(published bool, err error) func(ctx context.Context, resourceID string) (*models.Interactive, error) {
+		return func(ctx context.Context, resourceID string) (*models.Interactive, error) {
+			if err != nil {
+				return nil, err
+			}
+			if resourceID != "abcd1234" {
+				return nil, mongo.ErrNoRecordFound
+			}
+			return held(published), nil
+		}
+	}
+	bySlug := func(published bool) func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+		return func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+			if publishedOnly && !published {
+				return nil, mongo.ErrNoRecordFound
+			}
+			if i := held(published); slug == i.Metadata.HumanReadableSlug || i.Metadata.HadSlug(slug) {
+				return i, nil
+			}
+			return nil, mongo.ErrNoRecordFound
+		}
+	}
+
+	tests := []struct {
+		title            string
+		publishing       bool
+		published        bool
+		err              error
+		uri              string
+		expectedCode     int
+		expectedRedirect bool
+	}{
+		{"WhenGetByResourceID_ThenFound", false, true, nil, "/v1/interactives/resource/abcd1234", http.StatusOK, false},
+		{"WhenGetByResourceIDUnpublishedInWeb_ThenNotFound", false, false, nil, "/v1/interactives/resource/abcd1234", http.StatusNotFound, false},
+		{"WhenGetByResourceIDUnpublishedInPublishing_ThenFound", true, false, nil, "/v1/interactives/resource/abcd1234", http.StatusOK, false},
+		{"WhenGetByUnknownResourceID_ThenNotFound", false, true, mongo.ErrNoRecordFound, "/v1/interactives/resource/abcd1234", http.StatusNotFound, false},
+		{"WhenGetByResourceIDFails_ThenInternalError", false, true, errors.New("mongo down"), "/v1/interactives/resource/abcd1234", http.StatusInternalServerError, false},
+		{"WhenResolvingCanonicalPath_ThenNoRedirect", false, true, nil, "/v1/interactives/resolve/gdp-growth-abcd1234", http.StatusOK, false},
+		{"WhenResolvingFullPublicPath_ThenNoRedirect", false, true, nil, "/v1/interactives/resolve/interactives/gdp-growth-abcd1234/", http.StatusOK, false},
+		{"WhenResolvingOutdatedSlug_ThenRedirect", false, true, nil, "/v1/interactives/resolve/gdp-abcd1234", http.StatusOK, true},
+		{"WhenResolvingResourceIDAlone_ThenRedirect", false, true, nil, "/v1/interactives/resolve/abcd1234", http.StatusOK, true},
+		{"WhenResolvingCurrentSlugAlone_ThenRedirect", false, true, nil, "/v1/interactives/resolve/gdp-growth", http.StatusOK, true},
+		{"WhenResolvingPreviousSlugAlone_ThenRedirect", false, true, nil, "/v1/interactives/resolve/economic-growth", http.StatusOK, true},
+		{"WhenResolvingUnknownResourceID_ThenNotFound", false, true, nil, "/v1/interactives/resolve/gdp-growth-zzzz9999", http.StatusNotFound, false},
+		{"WhenResolvingUnknownSlug_ThenNotFound", false, true, nil, "/v1/interactives/resolve/unknown-slug", http.StatusNotFound, false},
+		{"WhenResolvingUnpublishedInWeb_ThenNotFound", false, false, nil, "/v1/interactives/resolve/gdp-growth-abcd1234", http.StatusNotFound, false},
+		{"WhenResolvingUnpublishedSlugInWeb_ThenNotFound", false, false, nil, "/v1/interactives/resolve/gdp-growth", http.StatusNotFound, false},
+		{"WhenResolvingUnpublishedSlugInPublishing_ThenRedirect", true, false, nil, "/v1/interactives/resolve/gdp-growth", http.StatusOK, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			mongoServer := &apiMock.MongoServerMock{
+				GetInteractiveByResourceIDFunc: byResourceID(tc.published, tc.err),
+				GetInteractiveBySlugFunc:       bySlug(tc.published),
+			}
+			a := api.Setup(context.Background(), &config.Config{PublishingEnabled: tc.publishing}, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, nil, nil, nil, nil, noopGen, noopGen, noopGen, respondr)
+
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.uri, nil))
+			require.Equal(t, tc.expectedCode, resp.Code)
+			// web mode only looks for the slug among published interactives
+			for _, c := range mongoServer.GetInteractiveBySlugCalls() {
+				require.Equal(t, !tc.publishing, c.PublishedOnly)
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			var interactive *models.Interactive
+			var resolution models.Resolution
+			if resp.Header().Get("Link") == "" {
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &interactive))
+			} else {
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &resolution))
+				require.Equal(t, "</interactives/gdp-growth-abcd1234>; rel=\"canonical\"", resp.Header().Get("Link"))
+				require.Equal(t, "/interactives/gdp-growth-abcd1234", resolution.CanonicalURI)
+				require.Equal(t, tc.expectedRedirect, resolution.Redirect)
+				interactive = resolution.Interactive
+			}
+			require.Equal(t, "an-id", interactive.ID)
+		})
+	}
+}
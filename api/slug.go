@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ONSdigital/dp-authorisation/v2/authorisation"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+var (
+	ErrSlugTaken  = mongo.ErrSlugTaken
+	ErrSlugLocked = errors.New("slug cannot be changed once published")
+)
+
+// checkSlug makes sure a metadata update may set the slug it asks for. Once the interactive is published its
+// slug is locked - it is kept when the label changes and cannot be given by hand - unless the caller is an admin.
+// A slug given by hand, in english or a translation, must not be one another interactive has, or had, as it would
+// resolve to that interactive.
+func (api *API) checkSlug(ctx context.Context, r *http.Request, existing *models.Interactive, update *models.Metadata) (int, error) {
+	if update == nil || (update.HumanReadableSlug == "" && update.Label == "" && len(update.Translations) == 0) {
+		return http.StatusOK, nil
+	}
+
+	var id, current string
+	if existing != nil {
+		id = existing.ID
+		if existing.Metadata != nil {
+			current = existing.Metadata.HumanReadableSlug
+		}
+		if existing.Published != nil && *existing.Published && !api.hasPermission(r, InteractivesAdminPermission) {
+			if update.HumanReadableSlug != "" && update.HumanReadableSlug != current {
+				return http.StatusForbidden, ErrSlugLocked
+			}
+			update.HumanReadableSlug = current
+			if err := lockTranslatedSlugs(existing.Metadata, update); err != nil {
+				return http.StatusForbidden, err
+			}
+		}
+	}
+
+	if update.HumanReadableSlug != current {
+		if status, err := api.checkSlugFree(ctx, id, update.HumanReadableSlug); err != nil {
+			return status, err
+		}
+	}
+	for lang, t := range update.Translations {
+		if t == nil || t.HumanReadableSlug == "" {
+			continue
+		}
+		if existing != nil && existing.Metadata != nil {
+			if was := existing.Metadata.Translations[lang]; was != nil && was.HumanReadableSlug == t.HumanReadableSlug {
+				continue
+			}
+		}
+		if status, err := api.checkSlugFree(ctx, id, t.HumanReadableSlug); err != nil {
+			return status, err
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// checkSlugFree makes sure no interactive other than the one with the given id has, or had, the slug
+func (api *API) checkSlugFree(ctx context.Context, id, slug string) (int, error) {
+	if slug == "" {
+		return http.StatusOK, nil
+	}
+	taken, err := api.mongoDB.GetInteractiveBySlug(ctx, slug, false)
+	switch {
+	case err == mongo.ErrNoRecordFound:
+		return http.StatusOK, nil
+	case err != nil:
+		return http.StatusInternalServerError, fmt.Errorf("error checking slug %s %w", slug, err)
+	case taken != nil && taken.ID != id:
+		return http.StatusConflict, fmt.Errorf("%w: %s", ErrSlugTaken, slug)
+	}
+	return http.StatusOK, nil
+}
+
+// lockTranslatedSlugs keeps the slugs of the languages a published interactive has been translated into
+func lockTranslatedSlugs(existing, update *models.Metadata) error {
+	if existing == nil {
+		return nil
+	}
+	for lang, t := range update.Translations {
+		current := existing.Translations[lang]
+		if current == nil || t == nil || current.HumanReadableSlug == "" {
+			continue
+		}
+		if t.HumanReadableSlug != "" && t.HumanReadableSlug != current.HumanReadableSlug {
+			return fmt.Errorf("%w (%s)", ErrSlugLocked, lang)
+		}
+		t.HumanReadableSlug = current.HumanReadableSlug
+	}
+	return nil
+}
+
+// hasPermission is true if the caller holds the permission, checked for the collection the request is made in as
+// the authorisation middleware would. Without a permissions checker (authorisation is turned off) every caller does.
+func (api *API) hasPermission(r *http.Request, permission string) bool {
+	if api.auth == nil {
+		return false
+	}
+	if api.permissions == nil {
+		return true
+	}
+	ctx := r.Context()
+	attributes, err := authorisation.GetCollectionIDAttribute(r)
+	if err == nil {
+		var granted bool
+		if granted, err = api.permissions.HasPermission(ctx, *api.identity(r), permission, attributes); err == nil {
+			return granted
+		}
+	}
+	log.Error(ctx, "error checking permission", err, log.Data{"permission": permission})
+	return false
+}
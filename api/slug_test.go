@@ -0,0 +1,133 @@
+package api_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	test_support "github.com/ONSdigital/dp-interactives-api/internal/test-support"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManualSlug(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	existing := func(published bool) func(ctx context.Context, id string) (*models.Interactive, error) {
+		return func(ctx context.Context, id string) (*models.Interactive, error) {
+			return &models.Interactive{
+				ID:        id,
+				SHA:       "sha",
+				State:     models.ImportSuccess.String(),
+				Active:    &on,
+				Published: &published,
+				Archive:   &models.Archive{},
+				Metadata: &models.Metadata{
+					Label:             "label",
+					HumanReadableSlug: "current-slug",
+					PreviousSlugs:     []string{"old-slug"},
+				},
+			}, nil
+		}
+	}
+	bySlug := func(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+		switch slug {
+		case "taken-slug":
+			return &models.Interactive{ID: "other-id"}, nil
+		case "old-slug":
+			return &models.Interactive{ID: "an-id"}, nil
+		}
+		return nil, mongo.ErrNoRecordFound
+	}
+	permissions := func(admin bool) *apiMock.PermissionsCheckerMock {
+		if admin {
+			return denyPermissions()
+		}
+		return denyPermissions(api.InteractivesAdminPermission)
+	}
+
+	tests := []struct {
+		title        string
+		method       string
+		uri          string
+		published    bool
+		admin        bool
+		metadata     *models.Metadata
+		expectedCode int
+		expectedSlug string
+	}{
+		{"WhenUploadWithSlug_ThenSlugUsed", http.MethodPost, "/v1/interactives", false, false, &models.Metadata{HumanReadableSlug: "by-hand"}, http.StatusAccepted, "by-hand"},
+		{"WhenUploadWithInvalidSlug_ThenBadRequest", http.MethodPost, "/v1/interactives", false, false, &models.Metadata{HumanReadableSlug: "Not A Slug"}, http.StatusBadRequest, ""},
+		{"WhenUploadWithTakenSlug_ThenConflict", http.MethodPost, "/v1/interactives", false, false, &models.Metadata{HumanReadableSlug: "taken-slug"}, http.StatusConflict, ""},
+		{"WhenUpdateWithSlug_ThenSlugUsed", http.MethodPut, "/v1/interactives/an-id", false, false, &models.Metadata{HumanReadableSlug: "by-hand"}, http.StatusOK, "by-hand"},
+		{"WhenUpdateWithOwnPreviousSlug_ThenSlugUsed", http.MethodPut, "/v1/interactives/an-id", false, false, &models.Metadata{HumanReadableSlug: "old-slug"}, http.StatusOK, "old-slug"},
+		{"WhenUpdateWithTakenSlug_ThenConflict", http.MethodPut, "/v1/interactives/an-id", false, false, &models.Metadata{HumanReadableSlug: "taken-slug"}, http.StatusConflict, ""},
+		{"WhenUpdatePublishedWithSlug_ThenForbidden", http.MethodPut, "/v1/interactives/an-id", true, false, &models.Metadata{HumanReadableSlug: "by-hand"}, http.StatusForbidden, ""},
+		{"WhenUpdatePublishedWithSameSlug_ThenOK", http.MethodPut, "/v1/interactives/an-id", true, false, &models.Metadata{HumanReadableSlug: "current-slug"}, http.StatusOK, "current-slug"},
+		{"WhenUpdatePublishedLabel_ThenSlugKept", http.MethodPut, "/v1/interactives/an-id", true, false, &models.Metadata{Label: "newlabel"}, http.StatusOK, "current-slug"},
+		{"WhenAdminUpdatesPublishedWithSlug_ThenSlugUsed", http.MethodPut, "/v1/interactives/an-id", true, true, &models.Metadata{HumanReadableSlug: "by-hand"}, http.StatusOK, "by-hand"},
+		{"WhenUploadGeneratesTakenSlug_ThenNumbered", http.MethodPost, "/v1/interactives", false, false, &models.Metadata{Label: "taken"}, http.StatusAccepted, "taken-slug-3"},
+		{"WhenUploadWithSlugTakenMeanwhile_ThenConflict", http.MethodPost, "/v1/interactives", false, false, &models.Metadata{HumanReadableSlug: "raced-slug"}, http.StatusConflict, ""},
+		{"WhenUpdateWithSlugTakenMeanwhile_ThenConflict", http.MethodPut, "/v1/interactives/an-id", false, false, &models.Metadata{HumanReadableSlug: "raced-slug"}, http.StatusConflict, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			// the slug index refuses slugs another interactive holds, including one taken since it was checked
+			var stored []string
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error {
+					switch vis.Metadata.HumanReadableSlug {
+					case "taken-slug", "taken-slug-2", "raced-slug":
+						return mongo.ErrSlugTaken
+					}
+					stored = append(stored, vis.Metadata.HumanReadableSlug)
+					return nil
+				},
+				GetInteractiveFunc:       existing(tc.published),
+				GetInteractiveBySHAFunc:  noDuplicateFunc,
+				GetInteractiveBySlugFunc: bySlug,
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+			a := api.Setup(ctx, &config.Config{PublishingEnabled: true}, mux.NewRouter(), newAuthMiddlwareMock(), permissions(tc.admin), mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, nil, validInteractiveIdGen, noopGen, func(label string) string { return label + "-slug" }, respondr)
+
+			file := "-"
+			if tc.method == http.MethodPost {
+				file = "resources/single-interactive.zip"
+			}
+			if tc.metadata.Label == "" {
+				tc.metadata.Label = "label"
+			}
+			tc.metadata.InternalID, tc.metadata.Title = "idValue", "title1"
+			req := test_support.NewFileUploadRequest(tc.method, tc.uri, "attachment", file, &models.Interactive{Metadata: tc.metadata})
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Result().StatusCode)
+
+			if tc.expectedSlug == "" {
+				require.Empty(t, stored)
+				return
+			}
+			require.Equal(t, []string{tc.expectedSlug}, stored)
+		})
+	}
+}
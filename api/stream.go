@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
+	"github.com/ONSdigital/dp-interactives-api/internal/zip"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// maxFieldSize is the most read from a single (non-file) form field in streaming mode
+const maxFieldSize = 1 << 20
+
+var errScanAbandoned = errors.New("upload abandoned")
+
+// StreamedArchive is an attachment that was validated and stored in s3 as it was received
+type StreamedArchive struct {
+	Key         string
+	Contents    *zip.Contents
+	ContentScan *models.ContentScan
+}
+
+// validateStream reads the form a part at a time so that a zip attachment can be sent to s3 as it is validated,
+// without being written to disk. The attachment is verified as soon as it has been read, so the interactive
+// and force fields must come before it. Other formats are still staged on disk to be converted to zips.
+func (f *FormDataRequest) validateStream(attachmentValidator FormDataValidator) (errs []error) {
+	reader, err := f.req.MultipartReader()
+	if err != nil {
+		msg := fmt.Sprintf("error parsing form data %s", err.Error())
+		return []error{validatorError(FileFieldKey, msg)}
+	}
+
+	// fields read so far are made available through FormValue, as they are after ParseMultipartForm
+	form := &multipart.Form{Value: map[string][]string{}, File: map[string][]*multipart.FileHeader{}}
+	f.req.PostForm = form.Value
+	f.req.Form = url.Values{}
+	for k, v := range f.req.URL.Query() {
+		f.req.Form[k] = v
+	}
+
+	var tmpfilename, filename string
+	var thumb []byte
+	var streamed *StreamedArchive
+	var fieldsParsed, force bool
+	var interactive *models.Interactive
+	var cleared []string
+	for len(errs) == 0 && f.Rejected == nil && f.Failed == nil && f.Busy == nil {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			msg := fmt.Sprintf("error parsing form data %s", err.Error())
+			errs = append(errs, validatorError(FileFieldKey, msg))
+			break
+		}
+
+		if part.FileName() == "" {
+			if fieldsParsed && (part.FormName() == UpdateFieldKey || part.FormName() == ForceFieldKey) {
+				errs = append(errs, validatorError(part.FormName(), "must come before the file"))
+				break
+			}
+			value, err := io.ReadAll(io.LimitReader(part, maxFieldSize+1))
+			if err != nil || len(value) > maxFieldSize {
+				errs = append(errs, validatorError(part.FormName(), "cannot read form field"))
+				break
+			}
+			form.Value[part.FormName()] = append(form.Value[part.FormName()], string(value))
+			f.req.Form.Add(part.FormName(), string(value))
+			continue
+		}
+
+		form.File[part.FormName()] = append(form.File[part.FormName()], &multipart.FileHeader{Filename: part.FileName(), Header: part.Header})
+		if part.FormName() == ThumbnailFieldKey {
+			// small enough to hold in memory, and may come before or after the archive
+			if len(form.File[ThumbnailFieldKey]) > 1 {
+				errs = append(errs, validatorError(ThumbnailFieldKey, "expecting one thumbnail"))
+				break
+			}
+			if thumb, err = readThumbnail(part); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if archiveParts(form) > 1 || len(form.File[part.FormName()]) > 1 {
+			// left for the attachment validator to report
+			break
+		}
+
+		format, fErr := zip.DetectFormat(part.FileName())
+		if fErr != nil {
+			msg := fmt.Sprintf("file extension (%s) not supported - %s", filepath.Ext(part.FileName()), fErr.Error())
+			errs = append(errs, validatorError(FileFieldKey, msg))
+			break
+		}
+
+		// the attachment is verified against the fields, so they must be valid before it is read
+		fieldsParsed = true
+		if f.isMetadataMandatory && f.req.FormValue(UpdateFieldKey) == "" {
+			errs = append(errs, validatorError(UpdateFieldKey, "must come before the file"))
+			break
+		}
+		if force, interactive, cleared, errs = f.parseFields(); len(errs) > 0 {
+			break
+		}
+		f.Interactive, f.ClearedMetadata, f.Force = interactive, cleared, force
+		if f.acquire != nil {
+			if f.Busy = f.acquire(); f.Busy != nil {
+				break
+			}
+		}
+
+		file := http.MaxBytesReader(nil, part, maxUploadFileSizeMb<<20)
+		if format == zip.FormatZip {
+			streamed, errs = f.streamArchive(file, part.FileName())
+			filename = part.FileName()
+		} else {
+			tmpfilename, filename, errs = stageAttachment(file, part.FileName(), format, f.api.zipLimits)
+		}
+	}
+	f.req.MultipartForm = form
+
+	if f.Busy != nil {
+		return nil
+	}
+	if f.Rejected != nil || f.Failed != nil {
+		// a duplicate archive is not stored but its interactive is still created, with any thumbnail sent before it
+		f.Thumbnail = thumb
+		return nil
+	}
+	if len(errs) == 0 {
+		if err = attachmentValidator(f.req); err != nil {
+			errs = append(errs, validatorError(FileFieldKey, err.Error()))
+		}
+	}
+	if len(errs) == 0 && !fieldsParsed {
+		force, interactive, cleared, errs = f.parseFields()
+	}
+	if len(errs) > 0 {
+		if tmpfilename != "" {
+			os.Remove(tmpfilename)
+		}
+		return errs
+	}
+
+	f.TmpFileName = tmpfilename
+	f.Streamed = streamed
+	f.Name = filename
+	f.Interactive = interactive
+	f.ClearedMetadata = cleared
+	f.Force = force
+	f.Thumbnail = thumb
+	return nil
+}
+
+// streamArchive sends a zip attachment to s3 as it is validated and scanned. The archive is verified before the
+// upload completes, so one that fails is never stored. Memory use is bounded by the s3 part size and concurrency.
+func (f *FormDataRequest) streamArchive(file io.Reader, filename string) (*StreamedArchive, []error) {
+	ctx := f.req.Context()
+	if err := f.api.s3.ValidateBucket(); err != nil {
+		f.Failed = fmt.Errorf("invalid s3 bucket %w", err)
+		return nil, nil
+	}
+
+	var malware *malwareScan
+	if f.api.scanner != nil {
+		malware = f.api.startScan(ctx)
+		defer malware.abandon()
+		file = io.TeeReader(file, malware)
+	}
+
+	var contentScan *models.ContentScan
+	stream := zip.NewStream(file, f.api.zipLimits, f.api.scanners(f.picker), func(contents *zip.Contents, findings []*models.ContentFinding) error {
+		if malware != nil {
+			if err := malware.verdict(); err != nil {
+				return err
+			}
+		}
+		contentScan = models.NewContentScan(findings, f.api.cfg.ContentPolicyStrict)
+		if f.verify != nil {
+			f.Rejected = f.verify(f, contents)
+		}
+		return f.Rejected
+	})
+	defer stream.Close()
+
+	key := fmt.Sprintf("%s/%s", f.api.newUUID(""), filename)
+	_, err := f.api.s3.Upload(&s3manager.UploadInput{Body: stream, Key: &key}, f.api.streamUploadOptions)
+
+	var validationErr *zip.ValidationError
+	var tooLarge *http.MaxBytesError
+	var infected *infectedError
+	switch streamErr := stream.Err(); {
+	case f.Rejected != nil:
+		return nil, nil
+	case errors.As(streamErr, &validationErr):
+		return nil, validationErr.Errors()
+	case errors.As(streamErr, &tooLarge):
+		msg := fmt.Sprintf("size of content exceeded allowed limit (%d MB)", maxUploadFileSizeMb)
+		return nil, []error{validatorError(FileFieldKey, msg)}
+	case errors.As(streamErr, &infected):
+		log.Warn(ctx, "malware found in streamed upload", log.Data{"file": filename, "signature": infected.Signature})
+		return nil, []error{validatorError(FileFieldKey, infected.Error())}
+	case streamErr == zip.ErrNoIndexHtml:
+		return nil, []error{validatorError(FileFieldKey, fmt.Sprintf("unable to open file %s", streamErr.Error()))}
+	case streamErr != nil:
+		f.Failed = fmt.Errorf("error reading upload %w", streamErr)
+		return nil, nil
+	case err != nil:
+		f.Failed = fmt.Errorf("s3 upload error %w", err)
+		return nil, nil
+	case stream.Contents() == nil:
+		f.Failed = errors.New("s3 upload completed before the archive was read")
+		return nil, nil
+	}
+
+	return &StreamedArchive{Key: key, Contents: stream.Contents(), ContentScan: contentScan}, nil
+}
+
+// streamUploadOptions bounds the memory a streamed upload can use - s3manager buffers a part per concurrent upload
+func (api *API) streamUploadOptions(u *s3manager.Uploader) {
+	if api.cfg.UploadStreamPartSize > 0 {
+		u.PartSize = api.cfg.UploadStreamPartSize
+	}
+	if api.cfg.UploadStreamConcurrency > 0 {
+		u.Concurrency = api.cfg.UploadStreamConcurrency
+	}
+}
+
+// infectedError is a streamed upload that the malware scanner found a signature in
+type infectedError struct {
+	Signature string
+}
+
+func (e *infectedError) Error() string {
+	return "archive failed malware scan"
+}
+
+// malwareScan runs the scanner over an upload as it is written
+type malwareScan struct {
+	pw     *io.PipeWriter
+	done   chan struct{}
+	result *scan.Result
+	err    error
+}
+
+func (api *API) startScan(ctx context.Context) *malwareScan {
+	pr, pw := io.Pipe()
+	m := &malwareScan{pw: pw, done: make(chan struct{})}
+	go func() {
+		defer close(m.done)
+		m.result, m.err = api.scanner.Scan(ctx, pr)
+		// a scanner that stops reading must not hold up the upload
+		pr.CloseWithError(m.err)
+	}()
+	return m
+}
+
+func (m *malwareScan) Write(p []byte) (int, error) {
+	if _, err := m.pw.Write(p); err != nil {
+		// the scanner has stopped reading - that is only a problem if it failed
+		if <-m.done; m.err != nil {
+			return 0, fmt.Errorf("malware scan failed %w", m.err)
+		}
+	}
+	return len(p), nil
+}
+
+// verdict waits for the scanner to finish with the upload, which must have been written in full
+func (m *malwareScan) verdict() error {
+	m.pw.Close()
+	<-m.done
+	switch {
+	case m.err != nil:
+		return fmt.Errorf("malware scan failed %w", m.err)
+	case m.result == nil:
+		return errors.New("malware scan failed: no result")
+	case m.result.Infected:
+		return &infectedError{Signature: m.result.Signature}
+	}
+	return nil
+}
+
+// abandon stops the scanner if the upload did not complete
+func (m *malwareScan) abandon() {
+	m.pw.CloseWithError(errScanAbandoned)
+	<-m.done
+}
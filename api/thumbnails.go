@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"github.com/ONSdigital/dp-interactives-api/internal/thumbnail"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/gorilla/mux"
+)
+
+var errThumbnailUnavailable = errors.New("interactive thumbnails are not available")
+
+// GetInteractiveThumbnailHandler serves one of the resized thumbnails of an interactive, under the same access
+// rules as GetInteractive. Thumbnails are kept in the content bucket alongside the imported files, so are served
+// in both modes - those stored before a content bucket was configured are read from the upload bucket.
+func (api *API) GetInteractiveThumbnailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if api.content == nil && api.s3 == nil {
+		api.respond.Error(ctx, w, http.StatusNotFound, errThumbnailUnavailable)
+		return
+	}
+
+	interactive, status, err := api.GetInteractive(ctx, r)
+	if err != nil {
+		api.respond.Error(ctx, w, status, err)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	thumb := interactive.Thumbnail(name)
+	if thumb == nil {
+		api.respond.Error(ctx, w, http.StatusNotFound, fmt.Errorf("thumbnail %s not found for interactive %s", name, interactive.ID))
+		return
+	}
+
+	body, size, err := api.readThumbnail(ctx, thumb.Key)
+	switch {
+	case err == content.ErrNotFound:
+		api.respond.Error(ctx, w, http.StatusNotFound, fmt.Errorf("thumbnail %s not found for interactive %s", name, interactive.ID))
+		return
+	case err != nil:
+		api.respond.Error(ctx, w, http.StatusInternalServerError, fmt.Errorf("error reading thumbnail %s %w", thumb.Key, err))
+		return
+	}
+	defer body.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", thumb.ContentType)
+	if size != nil {
+		header.Set("Content-Length", strconv.FormatInt(*size, 10))
+	}
+	header.Set("Cache-Control", api.contentCacheControl(interactive))
+	header.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, body); err != nil {
+		log.Error(ctx, "error writing interactive thumbnail", err, log.Data{"id": interactive.ID, "key": thumb.Key})
+	}
+}
+
+// readThumbnail reads a thumbnail from the content bucket, falling back to the upload bucket when there is one
+func (api *API) readThumbnail(ctx context.Context, key string) (io.ReadCloser, *int64, error) {
+	if api.content != nil {
+		object, err := api.content.Get(ctx, key, nil)
+		if err == nil {
+			return object.Body, &object.ContentLength, nil
+		}
+		if err != content.ErrNotFound || api.s3 == nil {
+			return nil, nil, err
+		}
+	}
+	return api.s3.Get(key)
+}
+
+// storeThumbnails resizes the thumbnail image to the configured widths and puts the results in the content bucket,
+// or the upload bucket if there is no content bucket.
+// An image sent with the request that cannot be resized is a bad request, whereas one picked from the archive
+// is skipped. Each set is stored under a new key so that one replaced is never served from a cache.
+func (api *API) storeThumbnails(ctx context.Context, id string, image []byte, uploaded bool) ([]*models.Thumbnail, int, error) {
+	if image == nil {
+		return nil, http.StatusOK, nil
+	}
+	variants, err := thumbnail.Generate(image, api.cfg.ThumbnailWidths)
+	if err != nil {
+		if uploaded {
+			return nil, http.StatusBadRequest, validatorError(ThumbnailFieldKey, err.Error())
+		}
+		log.Warn(ctx, "skipping thumbnail found in archive", log.Data{"id": id, "reason": err.Error()})
+		return nil, http.StatusOK, nil
+	}
+
+	if api.content == nil {
+		if err = api.s3.ValidateBucket(); err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("invalid s3 bucket %w", err)
+		}
+	}
+	version := api.newUUID("")
+	var thumbnails []*models.Thumbnail
+	for _, v := range variants {
+		key := fmt.Sprintf("thumbnails/%s/%s/%d%s", id, version, v.Width, v.Extension)
+		if err = api.putThumbnail(ctx, key, v.ContentType, v.Data); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		thumbnails = append(thumbnails, &models.Thumbnail{
+			Width:       v.Width,
+			Height:      v.Height,
+			ContentType: v.ContentType,
+			Key:         key,
+		})
+	}
+	return thumbnails, http.StatusOK, nil
+}
+
+// putThumbnail writes a thumbnail to the content bucket, which both modes serve from, or else the upload bucket
+func (api *API) putThumbnail(ctx context.Context, key, contentType string, data []byte) error {
+	if api.content != nil {
+		return api.content.Put(ctx, key, contentType, data)
+	}
+	if _, err := api.s3.Upload(&s3manager.UploadInput{Body: bytes.NewReader(data), Key: &key, ContentType: &contentType}); err != nil {
+		return fmt.Errorf("s3 upload error %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package api_test
+
+import (
+	zipper "archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/api"
+	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+// testArchive is a zip of the named files
+func testArchive(t *testing.T, files map[string][]byte) []byte {
+	var buf bytes.Buffer
+	zw := zipper.NewWriter(&buf)
+	for name, content := range files {
+		f, err := zw.Create(name)
+		require.NoError(t, err)
+		f.Write(content)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// newThumbnailUploadRequest sends the metadata first, then the archive and thumbnail if given
+func newThumbnailUploadRequest(method, uri string, archive, thumb []byte, i *models.Interactive) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	if i != nil {
+		jsonB, _ := json.Marshal(i)
+		writer.WriteField(api.UpdateFieldKey, string(jsonB))
+	}
+	if archive != nil {
+		part, _ := writer.CreateFormFile(api.FileFieldKey, "interactive.zip")
+		part.Write(archive)
+	}
+	if thumb != nil {
+		part, _ := writer.CreateFormFile(api.ThumbnailFieldKey, "thumbnail.png")
+		part.Write(thumb)
+	}
+	writer.Close()
+
+	req, _ := http.NewRequest(method, uri, body)
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadThumbnails(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	wide := testPNG(t, 800, 400)
+	narrow := testPNG(t, 200, 100)
+	archive, err := os.ReadFile("../internal/test-support/resources/single-interactive.zip")
+	require.NoError(t, err)
+	withThumbnail := testArchive(t, map[string][]byte{"index.html": []byte("<html></html>"), "thumbnail.png": narrow})
+	metadata := func() *models.Interactive {
+		return &models.Interactive{Metadata: &models.Metadata{Title: "title", Label: "label", InternalID: "idValue"}}
+	}
+
+	tests := []struct {
+		title          string
+		method         string
+		uri            string
+		streaming      bool
+		contentBucket  bool
+		archive        []byte
+		thumbnail      []byte
+		expectedCode   int
+		expectedWidths []int
+	}{
+		{"WhenUploadWithThumbnail_ThenResized", http.MethodPost, "/v1/interactives", false, false, archive, wide, http.StatusAccepted, []int{640, 320, 160}},
+		{"WhenStreamedWithThumbnail_ThenResized", http.MethodPost, "/v1/interactives", true, false, archive, wide, http.StatusAccepted, []int{640, 320, 160}},
+		{"WhenArchiveHasThumbnail_ThenResized", http.MethodPost, "/v1/interactives", false, false, withThumbnail, nil, http.StatusAccepted, []int{200, 160}},
+		{"WhenStreamedArchiveHasThumbnail_ThenResized", http.MethodPost, "/v1/interactives", true, false, withThumbnail, nil, http.StatusAccepted, []int{200, 160}},
+		{"WhenThumbnailAndArchiveHasThumbnail_ThenUploadedWins", http.MethodPost, "/v1/interactives", false, false, withThumbnail, wide, http.StatusAccepted, []int{640, 320, 160}},
+		{"WhenContentBucket_ThenStoredThere", http.MethodPost, "/v1/interactives", false, true, archive, wide, http.StatusAccepted, []int{640, 320, 160}},
+		{"WhenNoThumbnail_ThenNone", http.MethodPost, "/v1/interactives", false, false, archive, nil, http.StatusAccepted, nil},
+		{"WhenThumbnailNotAnImage_ThenBadRequest", http.MethodPost, "/v1/interactives", false, false, archive, []byte("<svg></svg>"), http.StatusBadRequest, nil},
+		{"WhenUpdateWithOnlyThumbnail_ThenResized", http.MethodPut, "/v1/interactives/an-id", false, false, nil, narrow, http.StatusOK, []int{200, 160}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			var mu sync.Mutex
+			stored := map[string]string{}
+			s3 := &apiMock.S3InterfaceMock{
+				ValidateBucketFunc: func() error { return nil },
+				UploadFunc: func(input *s3manager.UploadInput, options ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+					io.Copy(io.Discard, input.Body)
+					if strings.HasPrefix(*input.Key, "thumbnails/") {
+						mu.Lock()
+						stored[*input.Key] = *input.ContentType
+						mu.Unlock()
+					}
+					return &s3manager.UploadOutput{}, nil
+				},
+			}
+			var contentStore api.ContentStore
+			if tc.contentBucket {
+				contentStore = &apiMock.ContentStoreMock{
+					PutFunc: func(ctx context.Context, key, contentType string, body []byte) error {
+						mu.Lock()
+						stored["content:"+key] = contentType
+						mu.Unlock()
+						return nil
+					},
+				}
+			}
+			mongoServer := &apiMock.MongoServerMock{
+				UpsertInteractiveFunc: func(ctx context.Context, id string, vis *models.Interactive) error { return nil },
+				GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+					return &models.Interactive{
+						ID:        id,
+						Active:    &on,
+						Published: &off,
+						State:     models.ImportSuccess.String(),
+						Archive:   &models.Archive{},
+						Metadata:  &models.Metadata{Title: "title", Label: "label", InternalID: "idValue"},
+					}, nil
+				},
+				GetInteractiveBySHAFunc: noDuplicateFunc,
+				PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, ix *models.Interactive) error {
+					return nil
+				},
+			}
+			cfg := &config.Config{PublishingEnabled: true, UploadStreaming: tc.streaming, ThumbnailWidths: []int{640, 320, 160}}
+			a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3, &apiMock.FilesServiceMock{}, nil, contentStore, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+			var update *models.Interactive
+			if tc.method == http.MethodPost {
+				update = metadata()
+			}
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, newThumbnailUploadRequest(tc.method, tc.uri, tc.archive, tc.thumbnail, update))
+			require.Equal(t, tc.expectedCode, resp.Result().StatusCode, resp.Body.String())
+
+			upserts := mongoServer.UpsertInteractiveCalls()
+			if tc.expectedCode != http.StatusAccepted && tc.expectedCode != http.StatusOK {
+				require.Empty(t, upserts)
+				return
+			}
+			thumbnails := upserts[0].Vis.Thumbnails
+			require.Len(t, thumbnails, len(tc.expectedWidths))
+			for i, width := range tc.expectedWidths {
+				require.Equal(t, width, thumbnails[i].Width)
+				require.Equal(t, width/2, thumbnails[i].Height)
+				require.Equal(t, "image/png", thumbnails[i].ContentType)
+				key := thumbnails[i].Key
+				if tc.contentBucket {
+					key = "content:" + key
+				}
+				mu.Lock()
+				require.Equal(t, "image/png", stored[key])
+				mu.Unlock()
+			}
+		})
+	}
+}
+
+func TestGetInteractiveThumbnailHandler(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	mongoServer := &apiMock.MongoServerMock{
+		GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+			i := &models.Interactive{
+				ID:        id,
+				Active:    &on,
+				Published: &on,
+				Metadata:  &models.Metadata{},
+				Thumbnails: []*models.Thumbnail{
+					{Width: 640, Height: 320, ContentType: "image/png", Key: "thumbnails/an-id/v1/640.png"},
+					{Width: 320, Height: 160, ContentType: "image/png", Key: "thumbnails/an-id/v1/320.png"},
+				},
+			}
+			i.SetJSONAttribs("")
+			return i, nil
+		},
+	}
+
+	tests := []struct {
+		title          string
+		publishing     bool
+		withS3         bool
+		withContent    bool
+		contentMissing bool
+		uri            string
+		expectedCode   int
+		expectedS3Key  string
+	}{
+		{"WhenVariantExists_ThenServed", true, true, false, false, "/v1/interactives/an-id/thumbnails/320.png", http.StatusOK, "thumbnails/an-id/v1/320.png"},
+		{"WhenVariantMissing_ThenNotFound", true, true, false, false, "/v1/interactives/an-id/thumbnails/1280.png", http.StatusNotFound, ""},
+		{"WhenNoBucket_ThenNotFound", true, false, false, false, "/v1/interactives/an-id/thumbnails/320.png", http.StatusNotFound, ""},
+		{"WhenContentBucket_ThenServedFromIt", true, true, true, false, "/v1/interactives/an-id/thumbnails/320.png", http.StatusOK, ""},
+		{"WhenNotInContentBucket_ThenServedFromUploadBucket", true, true, true, true, "/v1/interactives/an-id/thumbnails/320.png", http.StatusOK, "thumbnails/an-id/v1/320.png"},
+		{"WhenWebMode_ThenServedFromContentBucket", false, false, true, false, "/v1/interactives/an-id/thumbnails/320.png", http.StatusOK, ""},
+		{"WhenWebModeAndNotInContentBucket_ThenNotFound", false, false, true, true, "/v1/interactives/an-id/thumbnails/320.png", http.StatusNotFound, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			s3 := &apiMock.S3InterfaceMock{
+				GetFunc: func(key string) (io.ReadCloser, *int64, error) {
+					size := int64(3)
+					return io.NopCloser(strings.NewReader("png")), &size, nil
+				},
+			}
+			store := &apiMock.ContentStoreMock{
+				GetFunc: func(ctx context.Context, key string, opts *content.GetOptions) (*content.Object, error) {
+					if tc.contentMissing {
+						return nil, content.ErrNotFound
+					}
+					return &content.Object{Body: io.NopCloser(strings.NewReader("png")), ContentLength: 3}, nil
+				},
+			}
+			var s3Interface api.S3Interface
+			if tc.withS3 {
+				s3Interface = s3
+			}
+			var contentStore api.ContentStore
+			if tc.withContent {
+				contentStore = store
+			}
+			cfg := &config.Config{PublishingEnabled: tc.publishing}
+			a := api.Setup(ctx, cfg, mux.NewRouter(), newAuthMiddlwareMock(), nil, mongoServer, nil, s3Interface, nil, nil, contentStore, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+			req := httptest.NewRequest(http.MethodGet, tc.uri, nil)
+			resp := httptest.NewRecorder()
+			a.Router.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code)
+			if tc.expectedS3Key == "" {
+				require.Empty(t, s3.GetCalls())
+			} else {
+				require.Equal(t, tc.expectedS3Key, s3.GetCalls()[0].Key)
+			}
+			if tc.expectedCode != http.StatusOK {
+				return
+			}
+			if tc.withContent {
+				require.Equal(t, "thumbnails/an-id/v1/320.png", store.GetCalls()[0].Key)
+			}
+			require.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+			require.Equal(t, "3", resp.Header().Get("Content-Length"))
+			require.Equal(t, "png", resp.Body.String())
+		})
+	}
+}
+
+func TestThumbnailsInWebMode(t *testing.T) {
+	t.Parallel()
+	log.SetDestination(io.Discard, io.Discard)
+
+	ctx := context.Background()
+	withThumbnail := func() *models.Interactive {
+		i := &models.Interactive{
+			ID:         "an-id",
+			Active:     &on,
+			Published:  &on,
+			Metadata:   &models.Metadata{},
+			Thumbnails: []*models.Thumbnail{{Width: 640, Height: 320, ContentType: "image/png", Key: "thumbnails/an-id/v1/640.png"}},
+		}
+		i.SetJSONAttribs("")
+		return i
+	}
+	mongoServer := &apiMock.MongoServerMock{
+		GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+			return withThumbnail(), nil
+		},
+		ListInteractivesFunc: func(ctx context.Context, filter *models.Filter) ([]*models.Interactive, error) {
+			return []*models.Interactive{withThumbnail()}, nil
+		},
+	}
+	a := api.Setup(ctx, &config.Config{PublishingEnabled: false}, mux.NewRouter(), nil, nil, mongoServer, nil, nil, nil, nil, &apiMock.ContentStoreMock{}, validInteractiveIdGen, noopGen, noopGen, respondr)
+
+	for _, uri := range []string{"/v1/interactives/an-id", "/v1/interactives"} {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		resp := httptest.NewRecorder()
+		a.Router.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code, uri)
+		require.Contains(t, resp.Body.String(), "/v1/interactives/an-id/thumbnails/640.png", uri)
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 const (
-	MetadataCollection = "MetadataCollection"
+	MetadataCollection   = "MetadataCollection"
+	DeadLetterCollection = "DeadLetterCollection"
 )
 
 // Config represents service configuration for dp-interactives-api
@@ -29,8 +30,36 @@ type Config struct {
 	KafkaSecClientCert         string        `envconfig:"KAFKA_SEC_CLIENT_CERT"`
 	KafkaSecClientKey          string        `envconfig:"KAFKA_SEC_CLIENT_KEY"    json:"-"`
 	KafkaSecSkipVerify         bool          `envconfig:"KAFKA_SEC_SKIP_VERIFY"`
+	KafkaSendTimeout           time.Duration `envconfig:"KAFKA_SEND_TIMEOUT"`
+	KafkaDeliveryTimeout       time.Duration `envconfig:"KAFKA_DELIVERY_TIMEOUT"`
 	InteractivesWriteTopic     string        `envconfig:"INTERACTIVES_WRITE_TOPIC"`
+	InteractivesReadTopic      string        `envconfig:"INTERACTIVES_READ_TOPIC"`
+	InteractivesGroup          string        `envconfig:"INTERACTIVES_GROUP"`
 	KafkaConsumerWorkers       int           `envconfig:"KAFKA_CONSUMER_WORKERS"`
+	OutboxRelayInterval        time.Duration `envconfig:"OUTBOX_RELAY_INTERVAL"`
+	OutboxBatchSize            int           `envconfig:"OUTBOX_BATCH_SIZE"`
+	OutboxMaxAttempts          int           `envconfig:"OUTBOX_MAX_ATTEMPTS"`
+	SchemaRegistryURL          string        `envconfig:"SCHEMA_REGISTRY_URL"`
+	UploadedSchemaVersion      int           `envconfig:"INTERACTIVE_UPLOADED_SCHEMA_VERSION"`
+	ZipMaxEntries              int           `envconfig:"ZIP_MAX_ENTRIES"`
+	ZipMaxUncompressedSize     int64         `envconfig:"ZIP_MAX_UNCOMPRESSED_SIZE"`
+	ZipMaxCompressionRatio     float64       `envconfig:"ZIP_MAX_COMPRESSION_RATIO"`
+	ContentPolicyStrict        bool          `envconfig:"CONTENT_POLICY_STRICT"`
+	ContentPolicyScriptOrigins []string      `envconfig:"CONTENT_POLICY_SCRIPT_ORIGINS"`
+	ClamAVAddr                 string        `envconfig:"CLAMAV_ADDR"`
+	ClamAVTimeout              time.Duration `envconfig:"CLAMAV_TIMEOUT"`
+	UploadStreaming            bool          `envconfig:"UPLOAD_STREAMING"`
+	UploadStreamPartSize       int64         `envconfig:"UPLOAD_STREAM_PART_SIZE"`
+	UploadStreamConcurrency    int           `envconfig:"UPLOAD_STREAM_CONCURRENCY"`
+	UploadConcurrency          int           `envconfig:"UPLOAD_CONCURRENCY"`
+	UploadQueueDepth           int           `envconfig:"UPLOAD_QUEUE_DEPTH"`
+	UploadQueueTimeout         time.Duration `envconfig:"UPLOAD_QUEUE_TIMEOUT"`
+	UploadRetryAfter           time.Duration `envconfig:"UPLOAD_RETRY_AFTER"`
+	ContentBucketName          string        `envconfig:"CONTENT_BUCKET_NAME"`
+	ContentCacheMaxAge         time.Duration `envconfig:"CONTENT_CACHE_MAX_AGE"`
+	ThumbnailWidths            []int         `envconfig:"THUMBNAIL_WIDTHS"`
+	BulkConcurrency            int           `envconfig:"BULK_CONCURRENCY"`
+	BulkMaxOperations          int           `envconfig:"BULK_MAX_OPERATIONS"`
 	GracefulShutdownTimeout    time.Duration `envconfig:"GRACEFUL_SHUTDOWN_TIMEOUT"`
 	HealthCheckInterval        time.Duration `envconfig:"HEALTHCHECK_INTERVAL"`
 	HealthCheckCriticalTimeout time.Duration `envconfig:"HEALTHCHECK_CRITICAL_TIMEOUT"`
@@ -70,8 +99,34 @@ func Get() (*Config, error) {
 		MinBrokers:                 1,
 		KafkaVersion:               "1.0.2",
 		KafkaMaxBytes:              2000000,
+		KafkaSendTimeout:           5 * time.Second,
+		KafkaDeliveryTimeout:       time.Second,
 		InteractivesWriteTopic:     "interactives-import",
+		InteractivesReadTopic:      "interactive-imported",
+		InteractivesGroup:          "dp-interactives-api",
 		KafkaConsumerWorkers:       1,
+		OutboxRelayInterval:        5 * time.Second,
+		OutboxBatchSize:            50,
+		OutboxMaxAttempts:          10,
+		UploadedSchemaVersion:      2,
+		ZipMaxEntries:              10000,
+		ZipMaxUncompressedSize:     10 << 30,
+		ZipMaxCompressionRatio:     100,
+		ContentPolicyStrict:        false,
+		ContentPolicyScriptOrigins: []string{"www.ons.gov.uk", "cdn.ons.gov.uk"},
+		ClamAVTimeout:              60 * time.Second,
+		UploadStreaming:            false,
+		UploadStreamPartSize:       5 << 20,
+		UploadStreamConcurrency:    2,
+		UploadConcurrency:          4,
+		UploadQueueDepth:           8,
+		UploadQueueTimeout:         30 * time.Second,
+		UploadRetryAfter:           30 * time.Second,
+		ContentBucketName:          "",
+		ContentCacheMaxAge:         5 * time.Minute,
+		ThumbnailWidths:            []int{640, 320, 160},
+		BulkConcurrency:            4,
+		BulkMaxOperations:          100,
 		GracefulShutdownTimeout:    5 * time.Second,
 		HealthCheckInterval:        30 * time.Second,
 		HealthCheckCriticalTimeout: 90 * time.Second,
@@ -86,7 +141,7 @@ func Get() (*Config, error) {
 				Username:                      "",
 				Password:                      "",
 				Database:                      "interactives",
-				Collections:                   map[string]string{MetadataCollection: "metadata"},
+				Collections:                   map[string]string{MetadataCollection: "metadata", DeadLetterCollection: "dead_letters"},
 				ReplicaSet:                    "",
 				IsStrongReadConcernEnabled:    false,
 				IsWriteConcernMajorityEnabled: true,
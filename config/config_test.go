@@ -24,7 +24,36 @@ func TestConfig(t *testing.T) {
 				So(cfg.KafkaVersion, ShouldEqual, "1.0.2")
 				So(cfg.KafkaSecProtocol, ShouldEqual, "")
 				So(cfg.KafkaMaxBytes, ShouldEqual, 2000000)
+				So(cfg.KafkaSendTimeout, ShouldEqual, 5*time.Second)
+				So(cfg.KafkaDeliveryTimeout, ShouldEqual, time.Second)
 				So(cfg.InteractivesWriteTopic, ShouldEqual, "interactives-import")
+				So(cfg.InteractivesReadTopic, ShouldEqual, "interactive-imported")
+				So(cfg.InteractivesGroup, ShouldEqual, "dp-interactives-api")
+				So(cfg.KafkaConsumerWorkers, ShouldEqual, 1)
+				So(cfg.OutboxRelayInterval, ShouldEqual, 5*time.Second)
+				So(cfg.OutboxBatchSize, ShouldEqual, 50)
+				So(cfg.OutboxMaxAttempts, ShouldEqual, 10)
+				So(cfg.SchemaRegistryURL, ShouldEqual, "")
+				So(cfg.UploadedSchemaVersion, ShouldEqual, 2)
+				So(cfg.ZipMaxEntries, ShouldEqual, 10000)
+				So(cfg.ZipMaxUncompressedSize, ShouldEqual, 10<<30)
+				So(cfg.ZipMaxCompressionRatio, ShouldEqual, 100)
+				So(cfg.ContentPolicyStrict, ShouldBeFalse)
+				So(cfg.ContentPolicyScriptOrigins, ShouldResemble, []string{"www.ons.gov.uk", "cdn.ons.gov.uk"})
+				So(cfg.ClamAVAddr, ShouldEqual, "")
+				So(cfg.ClamAVTimeout, ShouldEqual, 60*time.Second)
+				So(cfg.UploadStreaming, ShouldBeFalse)
+				So(cfg.UploadStreamPartSize, ShouldEqual, 5<<20)
+				So(cfg.UploadStreamConcurrency, ShouldEqual, 2)
+				So(cfg.UploadConcurrency, ShouldEqual, 4)
+				So(cfg.UploadQueueDepth, ShouldEqual, 8)
+				So(cfg.BulkConcurrency, ShouldEqual, 4)
+				So(cfg.BulkMaxOperations, ShouldEqual, 100)
+				So(cfg.UploadQueueTimeout, ShouldEqual, 30*time.Second)
+				So(cfg.UploadRetryAfter, ShouldEqual, 30*time.Second)
+				So(cfg.ContentBucketName, ShouldEqual, "")
+				So(cfg.ContentCacheMaxAge, ShouldEqual, 5*time.Minute)
+				So(cfg.ThumbnailWidths, ShouldResemble, []int{640, 320, 160})
 				So(cfg.GracefulShutdownTimeout, ShouldEqual, 5*time.Second)
 				So(cfg.HealthCheckInterval, ShouldEqual, 30*time.Second)
 				So(cfg.HealthCheckCriticalTimeout, ShouldEqual, 90*time.Second)
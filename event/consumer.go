@@ -0,0 +1,135 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	kafka "github.com/ONSdigital/dp-kafka/v3"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+//go:generate moq -out mock/importstore.go -pkg mock . ImportStore
+
+const (
+	// storeRetryBackoff is the first wait before a failed mongo operation is retried, doubling after each failure
+	storeRetryBackoff = 100 * time.Millisecond
+	// maxStoreAttempts bounds the retries (1.5s of waiting in total) so a mongo outage cannot hold a worker, and
+	// with it StopAndWait, for longer than the graceful shutdown allows
+	maxStoreAttempts = 5
+)
+
+// ImportStore is the persistence needed to record the result of an import
+type ImportStore interface {
+	GetInteractive(ctx context.Context, id string) (*models.Interactive, error)
+	PatchInteractive(context.Context, interactives.PatchAttribute, *models.Interactive) error
+}
+
+// Unmarshaller unmarshals messages into events.
+type Unmarshaller interface {
+	Unmarshal(message []byte, s interface{}) error
+}
+
+// InteractiveImportedHandler applies import results consumed from kafka - the equivalent of
+// patching the archive attribute over http
+type InteractiveImportedHandler struct {
+	store        ImportStore
+	unmarshaller Unmarshaller
+}
+
+// NewInteractiveImportedHandler returns a new instance of InteractiveImportedHandler.
+func NewInteractiveImportedHandler(store ImportStore, unmarshaller Unmarshaller) *InteractiveImportedHandler {
+	return &InteractiveImportedHandler{
+		store:        store,
+		unmarshaller: unmarshaller,
+	}
+}
+
+// storeError is returned when the import result could not be read or written - the message is left uncommitted
+type storeError struct {
+	err *kafka.Error
+}
+
+func (e storeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the kafka error, so its log data is still logged
+func (e storeError) Unwrap() error {
+	return e.err
+}
+
+// Commit implements kafka.Commiter
+func (e storeError) Commit() bool {
+	return false
+}
+
+// Handle is a kafka.Handler. Messages that can never be applied (bad payload, unknown interactive) are
+// committed so they dont block the partition. A failed mongo operation is retried a few times, after which the
+// message is left uncommitted.
+func (h *InteractiveImportedHandler) Handle(ctx context.Context, workerID int, msg kafka.Message) error {
+	e := &InteractiveImported{}
+	if err := h.unmarshaller.Unmarshal(msg.GetData(), e); err != nil {
+		return fmt.Errorf("cannot unmarshal interactive imported event %w", err)
+	}
+	logData := log.Data{"interactive_id": e.ID, "import_successful": e.ImportSuccessful, "worker_id": workerID}
+
+	var i *models.Interactive
+	err := retry(ctx, logData, func() (err error) {
+		i, err = h.store.GetInteractive(ctx, e.ID)
+		if errors.Is(err, mongo.ErrNoRecordFound) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return storeError{kafka.NewError(fmt.Errorf("error fetching interactive %s %w", e.ID, err), logData)}
+	}
+	if i == nil || i.Active == nil || !*i.Active {
+		return kafka.NewError(fmt.Errorf("interactive either deleted or does not exist %s", e.ID), logData)
+	}
+
+	i.SetImportResult(&models.Archive{
+		Name:                e.FilePath,
+		Size:                e.Size,
+		ImportMessage:       e.ImportMessage,
+		UploadRootDirectory: e.UploadRootDirectory,
+		ImportSuccessful:    e.ImportSuccessful,
+	})
+
+	err = retry(ctx, logData, func() error {
+		return h.store.PatchInteractive(ctx, interactives.PatchArchive, i)
+	})
+	if err != nil {
+		return storeError{kafka.NewError(fmt.Errorf("error patching interactive %s %w", e.ID, err), logData)}
+	}
+
+	log.Info(ctx, "import result applied", logData)
+	return nil
+}
+
+// retry runs a mongo operation until it succeeds, waiting longer after each failure, until maxStoreAttempts
+// have failed or ctx is done
+func retry(ctx context.Context, logData log.Data, operation func() error) error {
+	wait := storeRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
+		if attempt == maxStoreAttempts {
+			return fmt.Errorf("%w (gave up after %d attempts)", err, attempt)
+		}
+		log.Error(ctx, fmt.Sprintf("error storing import result, retrying in %s", wait), err, logData)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (stopped retrying: %s)", err, ctx.Err())
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
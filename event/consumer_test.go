@@ -0,0 +1,125 @@
+package event_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/event/mock"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/dp-interactives-api/schema"
+	kafka "github.com/ONSdigital/dp-kafka/v3"
+	"github.com/ONSdigital/dp-kafka/v3/kafkatest"
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInteractiveImportedHandler(t *testing.T) {
+	ctx := context.Background()
+	active := true
+
+	newMessage := func(e *event.InteractiveImported) kafka.Message {
+		b, err := schema.InteractiveImportedEvent.Marshal(e)
+		So(err, ShouldBeNil)
+		msg, err := kafkatest.NewMessage(b, 0)
+		So(err, ShouldBeNil)
+		return msg
+	}
+
+	newStore := func(patchErr error) *mock.ImportStoreMock {
+		return &mock.ImportStoreMock{
+			GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+				if id == "missing" {
+					return nil, mongo.ErrNoRecordFound
+				}
+				return &models.Interactive{ID: id, Active: &active, State: models.ArchiveDispatchedToImporter.String()}, nil
+			},
+			PatchInteractiveFunc: func(ctx context.Context, attribute interactives.PatchAttribute, i *models.Interactive) error {
+				return patchErr
+			},
+		}
+	}
+
+	commit := func(err error) bool {
+		if c, ok := err.(kafka.Commiter); ok {
+			return c.Commit()
+		}
+		return true
+	}
+
+	Convey("Given a successful import result", t, func() {
+		store := newStore(nil)
+		handler := event.NewInteractiveImportedHandler(store, schema.InteractiveImportedEvent)
+		msg := newMessage(&event.InteractiveImported{ID: "an-id", FilePath: "path", Size: 42, ImportSuccessful: true})
+
+		Convey("When it is handled", func() {
+			err := handler.Handle(ctx, 1, msg)
+
+			Convey("Then the archive is patched with the import success state", func() {
+				So(err, ShouldBeNil)
+				So(store.PatchInteractiveCalls(), ShouldHaveLength, 1)
+				call := store.PatchInteractiveCalls()[0]
+				So(call.PatchAttribute, ShouldEqual, interactives.PatchArchive)
+				So(call.Interactive.State, ShouldEqual, models.ImportSuccess.String())
+				So(call.Interactive.Archive.Name, ShouldEqual, "path")
+				So(call.Interactive.Archive.Size, ShouldEqual, 42)
+			})
+		})
+	})
+
+	Convey("Given the mongo write fails for a while", t, func() {
+		store := newStore(nil)
+		store.PatchInteractiveFunc = func(ctx context.Context, attribute interactives.PatchAttribute, i *models.Interactive) error {
+			if len(store.PatchInteractiveCalls()) < 3 {
+				return errors.New("db error")
+			}
+			return nil
+		}
+		handler := event.NewInteractiveImportedHandler(store, schema.InteractiveImportedEvent)
+		msg := newMessage(&event.InteractiveImported{ID: "an-id"})
+
+		Convey("Then it is retried until the result is stored", func() {
+			err := handler.Handle(ctx, 1, msg)
+			So(err, ShouldBeNil)
+			So(store.PatchInteractiveCalls(), ShouldHaveLength, 3)
+		})
+	})
+
+	Convey("Given the mongo write keeps failing", t, func() {
+		store := newStore(errors.New("db error"))
+		handler := event.NewInteractiveImportedHandler(store, schema.InteractiveImportedEvent)
+		msg := newMessage(&event.InteractiveImported{ID: "an-id"})
+
+		Convey("Then it is retried a bounded number of times and the message is not committed", func() {
+			err := handler.Handle(ctx, 1, msg)
+			So(err, ShouldNotBeNil)
+			So(commit(err), ShouldBeFalse)
+			So(store.PatchInteractiveCalls(), ShouldHaveLength, 5)
+		})
+
+		Convey("Then it stops retrying when the consumer stops", func() {
+			stopping, cancel := context.WithTimeout(ctx, 250*time.Millisecond)
+			defer cancel()
+			err := handler.Handle(stopping, 1, msg)
+			So(err, ShouldNotBeNil)
+			So(commit(err), ShouldBeFalse)
+			So(len(store.PatchInteractiveCalls()), ShouldBeBetween, 1, 5)
+		})
+	})
+
+	Convey("Given the interactive does not exist", t, func() {
+		store := newStore(nil)
+		handler := event.NewInteractiveImportedHandler(store, schema.InteractiveImportedEvent)
+		msg := newMessage(&event.InteractiveImported{ID: "missing"})
+
+		Convey("Then an error is returned but the message is committed", func() {
+			err := handler.Handle(ctx, 1, msg)
+			So(err, ShouldNotBeNil)
+			So(commit(err), ShouldBeTrue)
+			So(store.PatchInteractiveCalls(), ShouldHaveLength, 0)
+		})
+	})
+}
@@ -1,8 +1,47 @@
 package event
 
+import "github.com/ONSdigital/dp-interactives-api/models"
+
 type InteractiveUploaded struct {
+	FilePath     string   `avro:"path"`
+	ID           string   `avro:"id"`
+	Title        string   `avro:"title"`
+	CollectionID string   `avro:"collection_id"`
+	EventID      string   `avro:"event_id"`
+	Size         int64    `avro:"size_in_bytes"`
+	HTMLFiles    []string `avro:"html_files"`
+	UploadedBy   string   `avro:"uploaded_by"`
+	AttemptID    string   `avro:"attempt_id"`
+}
+
+// NewInteractiveUploaded returns the message for an outbox event - the same event always gives the same message
+func NewInteractiveUploaded(e *models.OutboxEvent) *InteractiveUploaded {
+	return &InteractiveUploaded{
+		ID:           e.InteractiveID,
+		FilePath:     e.FilePath,
+		Title:        e.Title,
+		CollectionID: e.CollectionID,
+		EventID:      e.ID,
+		Size:         e.Size,
+		HTMLFiles:    e.HTMLFiles,
+		UploadedBy:   e.UploadedBy,
+		AttemptID:    e.AttemptID,
+	}
+}
+
+// interactiveUploadedV1 is InteractiveUploaded as written with version 1 of the schema
+type interactiveUploadedV1 struct {
 	FilePath     string `avro:"path"`
 	ID           string `avro:"id"`
 	Title        string `avro:"title"`
 	CollectionID string `avro:"collection_id"`
 }
+
+type InteractiveImported struct {
+	ID                  string `avro:"id"`
+	FilePath            string `avro:"path"`
+	Size                int64  `avro:"size_in_bytes"`
+	UploadRootDirectory string `avro:"upload_root_directory"`
+	ImportMessage       string `avro:"import_message"`
+	ImportSuccessful    bool   `avro:"import_successful"`
+}
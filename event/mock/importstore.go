@@ -0,0 +1,140 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"context"
+	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"sync"
+)
+
+// Ensure, that ImportStoreMock does implement event.ImportStore.
+// If this is not the case, regenerate this file with moq.
+var _ event.ImportStore = &ImportStoreMock{}
+
+// ImportStoreMock is a mock implementation of event.ImportStore.
+//
+//	func TestSomethingThatUsesImportStore(t *testing.T) {
+//
+//		// make and configure a mocked event.ImportStore
+//		mockedImportStore := &ImportStoreMock{
+//			GetInteractiveFunc: func(ctx context.Context, id string) (*models.Interactive, error) {
+//				panic("mock out the GetInteractive method")
+//			},
+//			PatchInteractiveFunc: func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
+//				panic("mock out the PatchInteractive method")
+//			},
+//		}
+//
+//		// use mockedImportStore in code that requires event.ImportStore
+//		// and then make assertions.
+//
+//	}
+type ImportStoreMock struct {
+	// GetInteractiveFunc mocks the GetInteractive method.
+	GetInteractiveFunc func(ctx context.Context, id string) (*models.Interactive, error)
+
+	// PatchInteractiveFunc mocks the PatchInteractive method.
+	PatchInteractiveFunc func(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetInteractive holds details about calls to the GetInteractive method.
+		GetInteractive []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
+		// PatchInteractive holds details about calls to the PatchInteractive method.
+		PatchInteractive []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// PatchAttribute is the patchAttribute argument value.
+			PatchAttribute interactives.PatchAttribute
+			// Interactive is the interactive argument value.
+			Interactive *models.Interactive
+		}
+	}
+	lockGetInteractive   sync.RWMutex
+	lockPatchInteractive sync.RWMutex
+}
+
+// GetInteractive calls GetInteractiveFunc.
+func (mock *ImportStoreMock) GetInteractive(ctx context.Context, id string) (*models.Interactive, error) {
+	if mock.GetInteractiveFunc == nil {
+		panic("ImportStoreMock.GetInteractiveFunc: method is nil but ImportStore.GetInteractive was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetInteractive.Lock()
+	mock.calls.GetInteractive = append(mock.calls.GetInteractive, callInfo)
+	mock.lockGetInteractive.Unlock()
+	return mock.GetInteractiveFunc(ctx, id)
+}
+
+// GetInteractiveCalls gets all the calls that were made to GetInteractive.
+// Check the length with:
+//
+//	len(mockedImportStore.GetInteractiveCalls())
+func (mock *ImportStoreMock) GetInteractiveCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockGetInteractive.RLock()
+	calls = mock.calls.GetInteractive
+	mock.lockGetInteractive.RUnlock()
+	return calls
+}
+
+// PatchInteractive calls PatchInteractiveFunc.
+func (mock *ImportStoreMock) PatchInteractive(contextMoqParam context.Context, patchAttribute interactives.PatchAttribute, interactive *models.Interactive) error {
+	if mock.PatchInteractiveFunc == nil {
+		panic("ImportStoreMock.PatchInteractiveFunc: method is nil but ImportStore.PatchInteractive was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		PatchAttribute  interactives.PatchAttribute
+		Interactive     *models.Interactive
+	}{
+		ContextMoqParam: contextMoqParam,
+		PatchAttribute:  patchAttribute,
+		Interactive:     interactive,
+	}
+	mock.lockPatchInteractive.Lock()
+	mock.calls.PatchInteractive = append(mock.calls.PatchInteractive, callInfo)
+	mock.lockPatchInteractive.Unlock()
+	return mock.PatchInteractiveFunc(contextMoqParam, patchAttribute, interactive)
+}
+
+// PatchInteractiveCalls gets all the calls that were made to PatchInteractive.
+// Check the length with:
+//
+//	len(mockedImportStore.PatchInteractiveCalls())
+func (mock *ImportStoreMock) PatchInteractiveCalls() []struct {
+	ContextMoqParam context.Context
+	PatchAttribute  interactives.PatchAttribute
+	Interactive     *models.Interactive
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		PatchAttribute  interactives.PatchAttribute
+		Interactive     *models.Interactive
+	}
+	mock.lockPatchInteractive.RLock()
+	calls = mock.calls.PatchInteractive
+	mock.lockPatchInteractive.RUnlock()
+	return calls
+}
@@ -0,0 +1,239 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package mock
+
+import (
+	"context"
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"sync"
+)
+
+// Ensure, that OutboxStoreMock does implement event.OutboxStore.
+// If this is not the case, regenerate this file with moq.
+var _ event.OutboxStore = &OutboxStoreMock{}
+
+// OutboxStoreMock is a mock implementation of event.OutboxStore.
+//
+//	func TestSomethingThatUsesOutboxStore(t *testing.T) {
+//
+//		// make and configure a mocked event.OutboxStore
+//		mockedOutboxStore := &OutboxStoreMock{
+//			CompleteOutboxEventFunc: func(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+//				panic("mock out the CompleteOutboxEvent method")
+//			},
+//			ListOutboxEventsFunc: func(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+//				panic("mock out the ListOutboxEvents method")
+//			},
+//			RetryOutboxEventFunc: func(ctx context.Context, e *models.OutboxEvent) error {
+//				panic("mock out the RetryOutboxEvent method")
+//			},
+//			UpsertDeadLetterFunc: func(ctx context.Context, d *models.DeadLetter) error {
+//				panic("mock out the UpsertDeadLetter method")
+//			},
+//		}
+//
+//		// use mockedOutboxStore in code that requires event.OutboxStore
+//		// and then make assertions.
+//
+//	}
+type OutboxStoreMock struct {
+	// CompleteOutboxEventFunc mocks the CompleteOutboxEvent method.
+	CompleteOutboxEventFunc func(ctx context.Context, e *models.OutboxEvent, state models.State) error
+
+	// ListOutboxEventsFunc mocks the ListOutboxEvents method.
+	ListOutboxEventsFunc func(ctx context.Context, limit int) ([]*models.OutboxEvent, error)
+
+	// RetryOutboxEventFunc mocks the RetryOutboxEvent method.
+	RetryOutboxEventFunc func(ctx context.Context, e *models.OutboxEvent) error
+
+	// UpsertDeadLetterFunc mocks the UpsertDeadLetter method.
+	UpsertDeadLetterFunc func(ctx context.Context, d *models.DeadLetter) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// CompleteOutboxEvent holds details about calls to the CompleteOutboxEvent method.
+		CompleteOutboxEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *models.OutboxEvent
+			// State is the state argument value.
+			State models.State
+		}
+		// ListOutboxEvents holds details about calls to the ListOutboxEvents method.
+		ListOutboxEvents []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// RetryOutboxEvent holds details about calls to the RetryOutboxEvent method.
+		RetryOutboxEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *models.OutboxEvent
+		}
+		// UpsertDeadLetter holds details about calls to the UpsertDeadLetter method.
+		UpsertDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// D is the d argument value.
+			D *models.DeadLetter
+		}
+	}
+	lockCompleteOutboxEvent sync.RWMutex
+	lockListOutboxEvents    sync.RWMutex
+	lockRetryOutboxEvent    sync.RWMutex
+	lockUpsertDeadLetter    sync.RWMutex
+}
+
+// CompleteOutboxEvent calls CompleteOutboxEventFunc.
+func (mock *OutboxStoreMock) CompleteOutboxEvent(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+	if mock.CompleteOutboxEventFunc == nil {
+		panic("OutboxStoreMock.CompleteOutboxEventFunc: method is nil but OutboxStore.CompleteOutboxEvent was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		E     *models.OutboxEvent
+		State models.State
+	}{
+		Ctx:   ctx,
+		E:     e,
+		State: state,
+	}
+	mock.lockCompleteOutboxEvent.Lock()
+	mock.calls.CompleteOutboxEvent = append(mock.calls.CompleteOutboxEvent, callInfo)
+	mock.lockCompleteOutboxEvent.Unlock()
+	return mock.CompleteOutboxEventFunc(ctx, e, state)
+}
+
+// CompleteOutboxEventCalls gets all the calls that were made to CompleteOutboxEvent.
+// Check the length with:
+//
+//	len(mockedOutboxStore.CompleteOutboxEventCalls())
+func (mock *OutboxStoreMock) CompleteOutboxEventCalls() []struct {
+	Ctx   context.Context
+	E     *models.OutboxEvent
+	State models.State
+} {
+	var calls []struct {
+		Ctx   context.Context
+		E     *models.OutboxEvent
+		State models.State
+	}
+	mock.lockCompleteOutboxEvent.RLock()
+	calls = mock.calls.CompleteOutboxEvent
+	mock.lockCompleteOutboxEvent.RUnlock()
+	return calls
+}
+
+// ListOutboxEvents calls ListOutboxEventsFunc.
+func (mock *OutboxStoreMock) ListOutboxEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+	if mock.ListOutboxEventsFunc == nil {
+		panic("OutboxStoreMock.ListOutboxEventsFunc: method is nil but OutboxStore.ListOutboxEvents was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Limit int
+	}{
+		Ctx:   ctx,
+		Limit: limit,
+	}
+	mock.lockListOutboxEvents.Lock()
+	mock.calls.ListOutboxEvents = append(mock.calls.ListOutboxEvents, callInfo)
+	mock.lockListOutboxEvents.Unlock()
+	return mock.ListOutboxEventsFunc(ctx, limit)
+}
+
+// ListOutboxEventsCalls gets all the calls that were made to ListOutboxEvents.
+// Check the length with:
+//
+//	len(mockedOutboxStore.ListOutboxEventsCalls())
+func (mock *OutboxStoreMock) ListOutboxEventsCalls() []struct {
+	Ctx   context.Context
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Limit int
+	}
+	mock.lockListOutboxEvents.RLock()
+	calls = mock.calls.ListOutboxEvents
+	mock.lockListOutboxEvents.RUnlock()
+	return calls
+}
+
+// RetryOutboxEvent calls RetryOutboxEventFunc.
+func (mock *OutboxStoreMock) RetryOutboxEvent(ctx context.Context, e *models.OutboxEvent) error {
+	if mock.RetryOutboxEventFunc == nil {
+		panic("OutboxStoreMock.RetryOutboxEventFunc: method is nil but OutboxStore.RetryOutboxEvent was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		E   *models.OutboxEvent
+	}{
+		Ctx: ctx,
+		E:   e,
+	}
+	mock.lockRetryOutboxEvent.Lock()
+	mock.calls.RetryOutboxEvent = append(mock.calls.RetryOutboxEvent, callInfo)
+	mock.lockRetryOutboxEvent.Unlock()
+	return mock.RetryOutboxEventFunc(ctx, e)
+}
+
+// RetryOutboxEventCalls gets all the calls that were made to RetryOutboxEvent.
+// Check the length with:
+//
+//	len(mockedOutboxStore.RetryOutboxEventCalls())
+func (mock *OutboxStoreMock) RetryOutboxEventCalls() []struct {
+	Ctx context.Context
+	E   *models.OutboxEvent
+} {
+	var calls []struct {
+		Ctx context.Context
+		E   *models.OutboxEvent
+	}
+	mock.lockRetryOutboxEvent.RLock()
+	calls = mock.calls.RetryOutboxEvent
+	mock.lockRetryOutboxEvent.RUnlock()
+	return calls
+}
+
+// UpsertDeadLetter calls UpsertDeadLetterFunc.
+func (mock *OutboxStoreMock) UpsertDeadLetter(ctx context.Context, d *models.DeadLetter) error {
+	if mock.UpsertDeadLetterFunc == nil {
+		panic("OutboxStoreMock.UpsertDeadLetterFunc: method is nil but OutboxStore.UpsertDeadLetter was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		D   *models.DeadLetter
+	}{
+		Ctx: ctx,
+		D:   d,
+	}
+	mock.lockUpsertDeadLetter.Lock()
+	mock.calls.UpsertDeadLetter = append(mock.calls.UpsertDeadLetter, callInfo)
+	mock.lockUpsertDeadLetter.Unlock()
+	return mock.UpsertDeadLetterFunc(ctx, d)
+}
+
+// UpsertDeadLetterCalls gets all the calls that were made to UpsertDeadLetter.
+// Check the length with:
+//
+//	len(mockedOutboxStore.UpsertDeadLetterCalls())
+func (mock *OutboxStoreMock) UpsertDeadLetterCalls() []struct {
+	Ctx context.Context
+	D   *models.DeadLetter
+} {
+	var calls []struct {
+		Ctx context.Context
+		D   *models.DeadLetter
+	}
+	mock.lockUpsertDeadLetter.RLock()
+	calls = mock.calls.UpsertDeadLetter
+	mock.lockUpsertDeadLetter.RUnlock()
+	return calls
+}
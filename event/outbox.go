@@ -0,0 +1,175 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+//go:generate moq -out mock/outbox.go -pkg mock . OutboxStore
+
+// OutboxStore is the persistence needed by the relay to drain pending events
+type OutboxStore interface {
+	ListOutboxEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error)
+	RetryOutboxEvent(ctx context.Context, e *models.OutboxEvent) error
+	CompleteOutboxEvent(ctx context.Context, e *models.OutboxEvent, state models.State) error
+	UpsertDeadLetter(ctx context.Context, d *models.DeadLetter) error
+}
+
+const (
+	// DefaultOutboxInterval is how often the outbox is drained when no interval is configured
+	DefaultOutboxInterval = 5 * time.Second
+	// maxRetryBackoff caps the wait before an event that failed is sent again
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// OutboxRelay drains events written to the outbox into kafka. Delivery is at-least-once - an event
+// is only removed from the outbox after it was sent, so consumers should dedupe on EventID.
+type OutboxRelay struct {
+	store       OutboxStore
+	producer    *AvroProducer
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	notify      chan struct{}
+	quit        chan struct{}
+	wg          sync.WaitGroup
+	stopOnce    sync.Once
+}
+
+// NewOutboxRelay returns a new instance of OutboxRelay.
+func NewOutboxRelay(store OutboxStore, producer *AvroProducer, interval time.Duration, batchSize, maxAttempts int) *OutboxRelay {
+	if interval <= 0 {
+		interval = DefaultOutboxInterval
+	}
+	return &OutboxRelay{
+		store:       store,
+		producer:    producer,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		notify:      make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+}
+
+// Start drains the outbox immediately (to pick up anything left by a previous run) and then
+// every interval, or sooner when notified, until Stop is called
+func (r *OutboxRelay) Start(ctx context.Context) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := r.Drain(ctx); err != nil {
+				log.Error(ctx, "error draining outbox", err)
+			}
+			select {
+			case <-r.quit:
+				return
+			case <-ticker.C:
+			case <-r.notify:
+			}
+		}
+	}()
+}
+
+// Notify wakes the relay up without waiting for the next tick - it never blocks
+func (r *OutboxRelay) Notify() {
+	if r == nil {
+		return
+	}
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Stop signals the relay to finish and waits for any in-flight drain to complete
+func (r *OutboxRelay) Stop() {
+	if r == nil {
+		return
+	}
+	r.stopOnce.Do(func() { close(r.quit) })
+	r.wg.Wait()
+}
+
+// Drain sends a single batch of pending events, skipping those waiting to be retried
+func (r *OutboxRelay) Drain(ctx context.Context) error {
+	events, err := r.store.ListOutboxEvents(ctx, r.batchSize)
+	if err != nil {
+		return fmt.Errorf("cannot list outbox events %w", err)
+	}
+
+	// every event is handed to kafka before waiting to hear whether any failed, so the batch shares one
+	// delivery timeout
+	now := time.Now()
+	var due []*models.OutboxEvent
+	var deliveries []*delivery
+	var errs []error
+	for _, e := range events {
+		if e.NextAttempt.After(now) {
+			continue
+		}
+		d, err := r.producer.send(ctx, NewInteractiveUploaded(e))
+		due, deliveries, errs = append(due, e), append(deliveries, d), append(errs, err)
+	}
+
+	for n, e := range due {
+		logData := log.Data{"event_id": e.ID, "interactive_id": e.InteractiveID, "attempts": e.Attempts}
+
+		err = errs[n]
+		if err == nil {
+			err = r.producer.wait(ctx, deliveries[n])
+		}
+		if err == nil {
+			if err = r.store.CompleteOutboxEvent(ctx, e, models.ArchiveDispatchedToImporter); err != nil {
+				// the event stays in the outbox and will be sent again - consumers dedupe on event id
+				log.Error(ctx, "error completing outbox event", err, logData)
+			}
+			continue
+		}
+
+		log.Error(ctx, "error relaying outbox event", err, logData)
+		if e.Attempts+1 >= r.maxAttempts {
+			// keep the event for a replay - if that cannot be saved leave it in the outbox instead
+			if err = r.store.UpsertDeadLetter(ctx, models.NewDeadLetter(e, e.Attempts+1, err)); err != nil {
+				log.Error(ctx, "error dead lettering outbox event", err, logData)
+				e.NextAttempt = now.Add(r.backoff(e.Attempts + 1))
+				if err = r.store.RetryOutboxEvent(ctx, e); err != nil {
+					log.Error(ctx, "error retrying outbox event", err, logData)
+				}
+				continue
+			}
+			if err = r.store.CompleteOutboxEvent(ctx, e, models.ArchiveDispatchFailed); err != nil {
+				log.Error(ctx, "error failing outbox event", err, logData)
+			}
+			continue
+		}
+		e.NextAttempt = now.Add(r.backoff(e.Attempts + 1))
+		if err = r.store.RetryOutboxEvent(ctx, e); err != nil {
+			log.Error(ctx, "error retrying outbox event", err, logData)
+		}
+	}
+
+	return nil
+}
+
+// backoff is the wait before an event is sent again after the given number of failed attempts - the interval,
+// doubling with each attempt after the first
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	wait := r.interval
+	for n := 1; n < attempts && wait < maxRetryBackoff; n++ {
+		wait *= 2
+	}
+	if wait > maxRetryBackoff {
+		wait = maxRetryBackoff
+	}
+	return wait
+}
@@ -0,0 +1,206 @@
+package event_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/event/mock"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestOutboxRelay(t *testing.T) {
+	ctx := context.Background()
+
+	newStore := func(events ...*models.OutboxEvent) *mock.OutboxStoreMock {
+		return &mock.OutboxStoreMock{
+			ListOutboxEventsFunc: func(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+				return events, nil
+			},
+			RetryOutboxEventFunc: func(ctx context.Context, e *models.OutboxEvent) error {
+				return nil
+			},
+			CompleteOutboxEventFunc: func(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+				return nil
+			},
+			UpsertDeadLetterFunc: func(ctx context.Context, d *models.DeadLetter) error {
+				return nil
+			},
+		}
+	}
+
+	Convey("Given an outbox with a pending event", t, func() {
+		outputChannel := make(chan []byte, 1)
+		store := newStore(&models.OutboxEvent{ID: "event-id", InteractiveID: "interactive-id", FilePath: "path"})
+		var sent *event.InteractiveUploaded
+		marshallerMock := &mock.MarshallerMock{
+			MarshalFunc: func(s interface{}) ([]byte, error) {
+				sent = s.(*event.InteractiveUploaded)
+				return []byte("bytes"), nil
+			},
+		}
+		relay := event.NewOutboxRelay(store, event.NewAvroProducer(outputChannel, marshallerMock), 0, 10, 3)
+
+		Convey("When the outbox is drained", func() {
+			err := relay.Drain(ctx)
+
+			Convey("Then the event is sent with its dedupe id and completed as dispatched", func() {
+				So(err, ShouldBeNil)
+				So(<-outputChannel, ShouldResemble, []byte("bytes"))
+				So(sent.ID, ShouldEqual, "interactive-id")
+				So(sent.EventID, ShouldEqual, "event-id")
+				So(store.CompleteOutboxEventCalls(), ShouldHaveLength, 1)
+				So(store.CompleteOutboxEventCalls()[0].State, ShouldEqual, models.ArchiveDispatchedToImporter)
+				So(store.RetryOutboxEventCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given a producer that cannot send", t, func() {
+		marshallerMock := &mock.MarshallerMock{
+			MarshalFunc: func(s interface{}) ([]byte, error) {
+				return nil, errMarshal
+			},
+		}
+		producer := event.NewAvroProducer(nil, marshallerMock)
+
+		Convey("When an event has attempts left", func() {
+			store := newStore(&models.OutboxEvent{ID: "event-id", Attempts: 1})
+			err := event.NewOutboxRelay(store, producer, 0, 10, 3).Drain(ctx)
+
+			Convey("Then it is left in the outbox for a retry after a backoff", func() {
+				So(err, ShouldBeNil)
+				So(store.RetryOutboxEventCalls(), ShouldHaveLength, 1)
+				So(store.CompleteOutboxEventCalls(), ShouldHaveLength, 0)
+				// the second failed attempt waits twice the default interval
+				next := store.RetryOutboxEventCalls()[0].E.NextAttempt
+				So(next, ShouldHappenBetween, time.Now().Add(2*event.DefaultOutboxInterval-time.Second), time.Now().Add(2*event.DefaultOutboxInterval))
+			})
+		})
+
+		Convey("When an event has failed many times", func() {
+			store := newStore(&models.OutboxEvent{ID: "event-id", Attempts: 20})
+			err := event.NewOutboxRelay(store, producer, time.Second, 10, 30).Drain(ctx)
+
+			Convey("Then the backoff is capped", func() {
+				So(err, ShouldBeNil)
+				So(store.RetryOutboxEventCalls()[0].E.NextAttempt, ShouldHappenBefore, time.Now().Add(5*time.Minute+time.Second))
+			})
+		})
+
+		Convey("When an event is waiting to be retried", func() {
+			store := newStore(&models.OutboxEvent{ID: "event-id", Attempts: 1, NextAttempt: time.Now().Add(time.Minute)})
+			err := event.NewOutboxRelay(store, producer, 0, 10, 3).Drain(ctx)
+
+			Convey("Then it is not sent", func() {
+				So(err, ShouldBeNil)
+				So(marshallerMock.MarshalCalls(), ShouldHaveLength, 0)
+				So(store.RetryOutboxEventCalls(), ShouldHaveLength, 0)
+			})
+		})
+
+		Convey("When an event has no attempts left", func() {
+			store := newStore(&models.OutboxEvent{ID: "event-id", Attempts: 2})
+			err := event.NewOutboxRelay(store, producer, 0, 10, 3).Drain(ctx)
+
+			Convey("Then it is dead lettered and completed as failed", func() {
+				So(err, ShouldBeNil)
+				So(store.RetryOutboxEventCalls(), ShouldHaveLength, 0)
+				So(store.UpsertDeadLetterCalls(), ShouldHaveLength, 1)
+				So(store.UpsertDeadLetterCalls()[0].D.ID, ShouldEqual, "event-id")
+				So(store.UpsertDeadLetterCalls()[0].D.Attempts, ShouldEqual, 3)
+				So(store.UpsertDeadLetterCalls()[0].D.Error, ShouldEqual, errMarshal.Error())
+				So(store.CompleteOutboxEventCalls(), ShouldHaveLength, 1)
+				So(store.CompleteOutboxEventCalls()[0].State, ShouldEqual, models.ArchiveDispatchFailed)
+			})
+		})
+
+		Convey("When an event has no attempts left and cannot be dead lettered", func() {
+			store := newStore(&models.OutboxEvent{ID: "event-id", Attempts: 2})
+			store.UpsertDeadLetterFunc = func(ctx context.Context, d *models.DeadLetter) error {
+				return errors.New("db error")
+			}
+			err := event.NewOutboxRelay(store, producer, 0, 10, 3).Drain(ctx)
+
+			Convey("Then it is left in the outbox", func() {
+				So(err, ShouldBeNil)
+				So(store.RetryOutboxEventCalls(), ShouldHaveLength, 1)
+				So(store.CompleteOutboxEventCalls(), ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	Convey("Given a batch of events and kafka confirming delivery by timeout", t, func() {
+		outputChannel := make(chan []byte, 10)
+		marshallerMock := &mock.MarshallerMock{
+			MarshalFunc: func(s interface{}) ([]byte, error) { return []byte(s.(*event.InteractiveUploaded).EventID), nil },
+		}
+		producer := event.NewAvroProducerWithDelivery(ctx, outputChannel, make(chan error), marshallerMock, time.Second, 200*time.Millisecond)
+		var events []*models.OutboxEvent
+		for _, id := range []string{"1", "2", "3", "4", "5"} {
+			events = append(events, &models.OutboxEvent{ID: id})
+		}
+		store := newStore(events...)
+
+		Convey("When the outbox is drained", func() {
+			started := time.Now()
+			err := event.NewOutboxRelay(store, producer, 0, 10, 3).Drain(ctx)
+
+			Convey("Then the events share one delivery timeout rather than waiting one after another", func() {
+				So(err, ShouldBeNil)
+				So(time.Since(started), ShouldBeLessThan, 600*time.Millisecond)
+				So(store.CompleteOutboxEventCalls(), ShouldHaveLength, 5)
+				So(len(outputChannel), ShouldEqual, 5)
+				So(string(<-outputChannel), ShouldEqual, "1")
+			})
+		})
+	})
+
+	Convey("Given kafka reports the delivery of an event with no attempts left failed", t, func() {
+		outputChannel := make(chan []byte, 1)
+		errorsChannel := make(chan error, 1)
+		go func() {
+			<-outputChannel
+			errorsChannel <- errors.New("producer is not initialised")
+		}()
+		marshallerMock := &mock.MarshallerMock{
+			MarshalFunc: func(s interface{}) ([]byte, error) { return []byte("bytes"), nil },
+		}
+		producer := event.NewAvroProducerWithDelivery(ctx, outputChannel, errorsChannel, marshallerMock, time.Second, time.Second)
+		store := newStore(&models.OutboxEvent{ID: "event-id", Attempts: 2})
+
+		Convey("When the outbox is drained", func() {
+			err := event.NewOutboxRelay(store, producer, 0, 10, 3).Drain(ctx)
+
+			Convey("Then the event is completed as failed rather than dispatched", func() {
+				So(err, ShouldBeNil)
+				So(store.CompleteOutboxEventCalls(), ShouldHaveLength, 1)
+				So(store.CompleteOutboxEventCalls()[0].State, ShouldEqual, models.ArchiveDispatchFailed)
+			})
+		})
+	})
+
+	Convey("Given a relay with no interval configured", t, func() {
+		relay := event.NewOutboxRelay(newStore(), event.NewAvroProducer(nil, nil), 0, 10, 3)
+
+		Convey("Then it starts and stops without panicking", func() {
+			So(func() { relay.Start(ctx); relay.Stop() }, ShouldNotPanic)
+		})
+	})
+
+	Convey("Given the outbox cannot be read", t, func() {
+		store := &mock.OutboxStoreMock{
+			ListOutboxEventsFunc: func(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+				return nil, errors.New("db error")
+			},
+		}
+
+		Convey("Then drain returns an error", func() {
+			err := event.NewOutboxRelay(store, event.NewAvroProducer(nil, nil), 0, 10, 3).Drain(ctx)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -1,12 +1,35 @@
 package event
 
-import "errors"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/Shopify/sarama"
+)
 
 //go:generate moq -out mock/marshaller.go -pkg mock . Marshaller
 
 type AvroProducer struct {
-	out        chan []byte
-	marshaller Marshaller
+	out             chan []byte
+	marshaller      Marshaller
+	sendTimeout     time.Duration
+	deliveryTimeout time.Duration
+	confirm         bool
+
+	mu      sync.Mutex
+	pending []*delivery
+}
+
+// delivery is a message handed to kafka whose sender is still waiting to hear of a failure, until the deadline
+type delivery struct {
+	msg      []byte
+	err      chan error
+	deadline time.Time
 }
 
 // Marshaller marshals events into messages.
@@ -22,23 +45,157 @@ func NewAvroProducer(outputChannel chan []byte, marshaller Marshaller) *AvroProd
 	}
 }
 
+// NewAvroProducerWithDelivery returns an AvroProducer that gives up on a send after sendTimeout and, once
+// a message is handed over, waits up to deliveryTimeout for kafka to report it failed. Errors from the
+// kafka producer are read until the errors channel is closed or ctx is done - those not belonging to a
+// waiting send are logged.
+func NewAvroProducerWithDelivery(ctx context.Context, outputChannel chan []byte, errorsChannel chan error, marshaller Marshaller, sendTimeout, deliveryTimeout time.Duration) *AvroProducer {
+	producer := NewAvroProducer(outputChannel, marshaller)
+	producer.sendTimeout = sendTimeout
+	producer.deliveryTimeout = deliveryTimeout
+	if errorsChannel != nil {
+		producer.confirm = deliveryTimeout > 0
+		go producer.watchErrors(ctx, errorsChannel)
+	}
+	return producer
+}
+
 // InteractiveUploaded produces a new InteractiveUploaded event.
-func (producer *AvroProducer) InteractiveUploaded(event *InteractiveUploaded) error {
+func (producer *AvroProducer) InteractiveUploaded(ctx context.Context, event *InteractiveUploaded) error {
 	if event == nil {
 		return errors.New("event required but was nil")
 	}
-	return producer.marshalAndSendEvent(event)
+	return producer.marshalAndSendEvent(ctx, event)
 }
 
 //marshalAndSendEvent is a generic function that marshals avro events and sends them to the output channel of the producer
-func (producer *AvroProducer) marshalAndSendEvent(event interface{}) error {
-	bytes, err := producer.marshaller.Marshal(event)
+func (producer *AvroProducer) marshalAndSendEvent(ctx context.Context, event interface{}) error {
+	d, err := producer.send(ctx, event)
 	if err != nil {
 		return err
 	}
+	return producer.wait(ctx, d)
+}
+
+// send hands the event to kafka, returning the delivery to wait for - nil when delivery is not confirmed
+func (producer *AvroProducer) send(ctx context.Context, event interface{}) (*delivery, error) {
+	bytes, err := producer.marshaller.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
 	// highly unlikely but worth checking
-	if producer.out != nil {
-		producer.out <- bytes
+	if producer.out == nil {
+		return nil, nil
+	}
+
+	// registered before sending, an uninitialised kafka producer reports the error straight away
+	var d *delivery
+	if producer.confirm {
+		d = producer.expect(bytes)
+	}
+
+	sendCtx := ctx
+	if producer.sendTimeout > 0 {
+		var cancel context.CancelFunc
+		sendCtx, cancel = context.WithTimeout(ctx, producer.sendTimeout)
+		defer cancel()
+	}
+	select {
+	case producer.out <- bytes:
+	case <-sendCtx.Done():
+		producer.forget(d)
+		return nil, fmt.Errorf("kafka producer did not accept the message %w", sendCtx.Err())
+	}
+	if d != nil {
+		d.deadline = time.Now().Add(producer.deliveryTimeout)
+	}
+	return d, nil
+}
+
+// wait treats the delivery as a success if kafka has not reported it failed by its deadline. dp-kafka does not
+// acknowledge successful deliveries, so a sender should hand over all it has before waiting on any of them.
+func (producer *AvroProducer) wait(ctx context.Context, d *delivery) error {
+	if d == nil {
+		return nil
+	}
+	defer producer.forget(d)
+	timer := time.NewTimer(time.Until(d.deadline))
+	defer timer.Stop()
+	select {
+	case err := <-d.err:
+		return fmt.Errorf("kafka delivery error %w", err)
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("delivery not confirmed %w", ctx.Err())
+	}
+}
+
+func (producer *AvroProducer) expect(msg []byte) *delivery {
+	d := &delivery{msg: msg, err: make(chan error, 1)}
+	producer.mu.Lock()
+	producer.pending = append(producer.pending, d)
+	producer.mu.Unlock()
+	return d
+}
+
+func (producer *AvroProducer) forget(d *delivery) {
+	if d == nil {
+		return
+	}
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	for i, p := range producer.pending {
+		if p == d {
+			producer.pending = append(producer.pending[:i], producer.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (producer *AvroProducer) watchErrors(ctx context.Context, errorsChannel chan error) {
+	for {
+		select {
+		case err, ok := <-errorsChannel:
+			if !ok {
+				return
+			}
+			if !producer.deliver(err) {
+				log.Error(ctx, "kafka producer error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliver hands err to the send it belongs to, returning false if nobody is waiting for it. Errors carrying the
+// failed message are matched on its value. Any others cannot be pinned on one send, so every waiting send fails -
+// the events are sent again and consumers dedupe them.
+func (producer *AvroProducer) deliver(err error) bool {
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+
+	var producerErr *sarama.ProducerError
+	if !errors.As(err, &producerErr) || producerErr.Msg == nil || producerErr.Msg.Value == nil {
+		for _, d := range producer.pending {
+			d.err <- err
+		}
+		failed := len(producer.pending) > 0
+		producer.pending = nil
+		return failed
+	}
+
+	value, encErr := producerErr.Msg.Value.Encode()
+	if encErr != nil {
+		return false
+	}
+	for i, d := range producer.pending {
+		if bytes.Equal(d.msg, value) {
+			producer.pending = append(producer.pending[:i], producer.pending[i+1:]...)
+			d.err <- err
+			return true
+		}
 	}
-	return nil
+	return false
 }
@@ -1,11 +1,14 @@
 package event_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ONSdigital/dp-interactives-api/event"
 	"github.com/ONSdigital/dp-interactives-api/event/mock"
 	"github.com/ONSdigital/dp-interactives-api/schema"
+	"github.com/Shopify/sarama"
 	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -33,7 +36,7 @@ func TestAvroProducer(t *testing.T) {
 		eventProducer := event.NewAvroProducer(outputChannel, marshallerMock)
 
 		Convey("when InteractiveUploaded is called with a nil event", func() {
-			err := eventProducer.InteractiveUploaded(nil)
+			err := eventProducer.InteractiveUploaded(context.Background(), nil)
 
 			Convey("then the expected error is returned", func() {
 				So(err.Error(), ShouldEqual, "event required but was nil")
@@ -49,7 +52,7 @@ func TestAvroProducer(t *testing.T) {
 				ID:       "myID",
 				FilePath: "myPath",
 			}
-			err := eventProducer.InteractiveUploaded(event)
+			err := eventProducer.InteractiveUploaded(context.Background(), event)
 
 			Convey("The expected event is available on the output channel", func() {
 				So(err, ShouldBeNil)
@@ -79,7 +82,7 @@ func TestAvroProducer(t *testing.T) {
 				ID:       "myID",
 				FilePath: "myPath",
 			}
-			err := eventProducer.InteractiveUploaded(event)
+			err := eventProducer.InteractiveUploaded(context.Background(), event)
 
 			Convey("The expected error is returned", func() {
 				So(err, ShouldResemble, errMarshal)
@@ -88,6 +91,93 @@ func TestAvroProducer(t *testing.T) {
 	})
 }
 
+func TestAvroProducerDelivery(t *testing.T) {
+	ctx := context.Background()
+	uploaded := &event.InteractiveUploaded{ID: "myID", FilePath: "myPath"}
+
+	Convey("Given a producer confirming delivery", t, func() {
+		outputChannel := make(chan []byte)
+		errorsChannel := make(chan error)
+		marshallerMock := &mock.MarshallerMock{
+			MarshalFunc: func(s interface{}) ([]byte, error) {
+				return []byte("hello world"), nil
+			},
+		}
+		eventProducer := event.NewAvroProducerWithDelivery(ctx, outputChannel, errorsChannel, marshallerMock, 50*time.Millisecond, 50*time.Millisecond)
+
+		Convey("When kafka reports the message failed", func() {
+			go func() {
+				msg := <-outputChannel
+				errorsChannel <- &sarama.ProducerError{
+					Msg: &sarama.ProducerMessage{Value: sarama.StringEncoder(msg)},
+					Err: sarama.ErrNotLeaderForPartition,
+				}
+			}()
+			err := eventProducer.InteractiveUploaded(ctx, uploaded)
+
+			Convey("Then the delivery error is returned", func() {
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, sarama.ErrNotLeaderForPartition), ShouldBeTrue)
+			})
+		})
+
+		Convey("When kafka reports an error for a different message", func() {
+			go func() {
+				<-outputChannel
+				errorsChannel <- &sarama.ProducerError{
+					Msg: &sarama.ProducerMessage{Value: sarama.StringEncoder("another message")},
+					Err: sarama.ErrNotLeaderForPartition,
+				}
+			}()
+			err := eventProducer.InteractiveUploaded(ctx, uploaded)
+
+			Convey("Then the send succeeds", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When kafka reports an error without the message", func() {
+			go func() {
+				<-outputChannel
+				errorsChannel <- errors.New("producer is not initialised")
+			}()
+			err := eventProducer.InteractiveUploaded(ctx, uploaded)
+
+			Convey("Then the send fails rather than being assumed delivered", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When nothing is reported within the delivery timeout", func() {
+			go func() { <-outputChannel }()
+			err := eventProducer.InteractiveUploaded(ctx, uploaded)
+
+			Convey("Then the send succeeds", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When the kafka producer never takes the message", func() {
+			err := eventProducer.InteractiveUploaded(ctx, uploaded)
+
+			Convey("Then the send times out instead of blocking", func() {
+				So(err, ShouldNotBeNil)
+				So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the context is cancelled before the message is taken", func() {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+			err := eventProducer.InteractiveUploaded(cancelled, uploaded)
+
+			Convey("Then the context error is returned", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+	})
+}
+
 // Unmarshal converts observation events to []byte.
 func unmarshal(bytes []byte) *event.InteractiveUploaded {
 	event := &event.InteractiveUploaded{}
@@ -0,0 +1,39 @@
+package event
+
+import (
+	"github.com/ONSdigital/dp-interactives-api/schema"
+	kafka "github.com/ONSdigital/dp-kafka/v3"
+)
+
+// ReadInteractiveUploaded decodes an InteractiveUploaded message written with any version of the
+// schema, using the version header to pick the writer schema. Fields added after the version the
+// message was written with are left at their defaults.
+func ReadInteractiveUploaded(msg kafka.Message) (*InteractiveUploaded, error) {
+	version, err := schema.InteractiveUploadedVersions.FromHeader(msg.GetHeader(schema.VersionHeader))
+	if err != nil {
+		return nil, err
+	}
+	s, err := schema.InteractiveUploadedVersions.Get(version)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == 1 {
+		v1 := &interactiveUploadedV1{}
+		if err = s.Unmarshal(msg.GetData(), v1); err != nil {
+			return nil, err
+		}
+		return &InteractiveUploaded{
+			FilePath:     v1.FilePath,
+			ID:           v1.ID,
+			Title:        v1.Title,
+			CollectionID: v1.CollectionID,
+		}, nil
+	}
+
+	e := &InteractiveUploaded{}
+	if err = s.Unmarshal(msg.GetData(), e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,77 @@
+package event_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/schema"
+	"github.com/ONSdigital/dp-kafka/v3/kafkatest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReadInteractiveUploaded(t *testing.T) {
+	uploaded := &event.InteractiveUploaded{
+		ID:           "ID",
+		FilePath:     "path/to/file.zip",
+		Title:        "title",
+		CollectionID: "collection",
+		EventID:      "event-id",
+		Size:         1024,
+		HTMLFiles:    []string{"index.html"},
+		UploadedBy:   "someone@ons.gov.uk",
+		AttemptID:    "attempt-id",
+	}
+
+	Convey("Given a message written with the latest schema and a version header", t, func() {
+		b, err := schema.InteractiveUploadedEvent.Marshal(uploaded)
+		So(err, ShouldBeNil)
+		msg, err := kafkatest.NewMessage(b, 0, kafkatest.OptionalHeaders(kafkatest.Headers{schema.VersionHeader: "2"}))
+		So(err, ShouldBeNil)
+
+		Convey("Then every field is read back", func() {
+			e, err := event.ReadInteractiveUploaded(msg)
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, uploaded)
+		})
+
+		Convey("Then a consumer still on version 1 can read it", func() {
+			var v1 struct {
+				ID           string `avro:"id"`
+				FilePath     string `avro:"path"`
+				Title        string `avro:"title"`
+				CollectionID string `avro:"collection_id"`
+			}
+			So(schema.InteractiveUploadedEventV1.Unmarshal(b, &v1), ShouldBeNil)
+			So(v1.ID, ShouldEqual, "ID")
+			So(v1.CollectionID, ShouldEqual, "collection")
+		})
+	})
+
+	Convey("Given a message written with version 1 and no version header", t, func() {
+		b, err := schema.InteractiveUploadedEventV1.Marshal(uploaded)
+		So(err, ShouldBeNil)
+		msg, err := kafkatest.NewMessage(b, 0)
+		So(err, ShouldBeNil)
+
+		Convey("Then the original fields are read and the rest left at their defaults", func() {
+			e, err := event.ReadInteractiveUploaded(msg)
+			So(err, ShouldBeNil)
+			So(e, ShouldResemble, &event.InteractiveUploaded{
+				ID:           "ID",
+				FilePath:     "path/to/file.zip",
+				Title:        "title",
+				CollectionID: "collection",
+			})
+		})
+	})
+
+	Convey("Given a message with an unknown version header", t, func() {
+		msg, err := kafkatest.NewMessage([]byte{}, 0, kafkatest.OptionalHeaders(kafkatest.Headers{schema.VersionHeader: "9"}))
+		So(err, ShouldBeNil)
+
+		Convey("Then an error is returned", func() {
+			_, err := event.ReadInteractiveUploaded(msg)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/ONSdigital/dp-interactives-api/config"
 	"github.com/ONSdigital/dp-interactives-api/internal/data"
 	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/dp-interactives-api/schema"
 	"github.com/ONSdigital/dp-interactives-api/service"
 	serviceMock "github.com/ONSdigital/dp-interactives-api/service/mock"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
@@ -180,7 +181,18 @@ func (c *InteractivesApiComponent) DoGetMockedKafkaProducerOk(ctx context.Contex
 		ChannelsFunc: func() *kafka.ProducerChannels {
 			return &kafka.ProducerChannels{}
 		},
-		CloseFunc: func(ctx context.Context) error { return nil },
+		CloseFunc:     func(ctx context.Context) error { return nil },
+		AddHeaderFunc: func(key, value string) {},
+	}, nil
+}
+
+func (c *InteractivesApiComponent) DoGetMockedKafkaConsumerOk(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+	return &kafkatest.IConsumerGroupMock{
+		RegisterHandlerFunc: func(ctx context.Context, h kafka.Handler) error { return nil },
+		LogErrorsFunc:       func(ctx context.Context) {},
+		StartFunc:           func() error { return nil },
+		StopAndWaitFunc:     func() error { return nil },
+		CloseFunc:           func(ctx context.Context, optFuncs ...kafka.OptFunc) error { return nil },
 	}, nil
 }
 
@@ -221,10 +233,23 @@ func (f *InteractivesApiComponent) DoGetResponder(_ context.Context, _ *config.C
 	return responder.New(), nil
 }
 
+func (f *InteractivesApiComponent) DoGetSchemaRegistry(_ context.Context, _ *config.Config) (schema.Registry, error) {
+	return schema.NewMemoryRegistry(), nil
+}
+
+func (f *InteractivesApiComponent) DoGetScanner(_ context.Context, _ *config.Config) (api.Scanner, error) {
+	return nil, nil
+}
+
+func (f *InteractivesApiComponent) DoGetContentStore(_ context.Context, _ *config.Config) (api.ContentStore, error) {
+	return nil, nil
+}
+
 func (c *InteractivesApiComponent) setInitialiserMock() {
 	c.initialiser = &serviceMock.InitialiserMock{
 		DoGetMongoDBFunc:                 c.DoGetMongoDB,
 		DoGetKafkaProducerFunc:           c.DoGetMockedKafkaProducerOk,
+		DoGetKafkaConsumerFunc:           c.DoGetMockedKafkaConsumerOk,
 		DoGetHealthCheckFunc:             c.DoGetHealthcheckOk,
 		DoGetHTTPServerFunc:              c.DoGetHTTPServer,
 		DoGetS3ClientFunc:                c.DoS3Client,
@@ -232,5 +257,8 @@ func (c *InteractivesApiComponent) setInitialiserMock() {
 		DoGetGeneratorsFunc:              c.DoGetGenerators,
 		DoGetFilesServiceFunc:            c.DoGetFSClient,
 		DoGetResponderFunc:               c.DoGetResponder,
+		DoGetSchemaRegistryFunc:          c.DoGetSchemaRegistry,
+		DoGetScannerFunc:                 c.DoGetScanner,
+		DoGetContentStoreFunc:            c.DoGetContentStore,
 	}
 }
@@ -70,4 +70,50 @@ Feature: Interactives API (Get interactive) - from public web access
                     "url": "http://preview_url/interactives/slug-abcde123/embed",
                     "uri": "/interactives/slug-abcde123"
                 }
-            """
\ No newline at end of file
+            """
+    Scenario: Published public access by resource id
+        And I have these interactives:
+            """
+            [
+                {
+                    "id": "ca99d09c-953a-4fe5-9b0a-51b3d40c01f7",
+                    "active": true,
+                    "published": true,
+                    "archive": {
+                        "name": "kqA7qPo1GeOJeff69lByWLbPiZM=/docker-vernemq-master.zip"
+                    },
+                    "last_updated": "2022-03-02T16:44:32.443Z",
+                    "metadata": {
+                        "title": "title123",
+                        "label": "ad fugiat cillum",
+                        "internal_id": "123",
+                        "resource_id": "abcde123",
+                        "slug": "slug"
+                    },
+                    "sha": "rhyCq4GCknxx0nzeqx2LE077Ruo=",
+                    "state": "ArchiveUploaded"
+                }
+            ]
+            """
+        When I GET "/v1/interactives/resource/abcde123"
+        Then I should receive the following model response with status "200":
+            """
+                {
+                    "id": "ca99d09c-953a-4fe5-9b0a-51b3d40c01f7",
+                    "published": true,
+                    "archive": {
+                        "name": "kqA7qPo1GeOJeff69lByWLbPiZM=/docker-vernemq-master.zip"
+                    },
+                    "metadata": {
+                        "title": "title123",
+                        "label": "ad fugiat cillum",
+                        "internal_id": "123",
+                        "resource_id": "abcde123",
+                        "slug": "slug"
+                    },
+                    "state": "ArchiveUploaded",
+                    "last_updated":"2021-01-01T00:00:00Z",
+                    "url": "http://preview_url/interactives/slug-abcde123/embed",
+                    "uri": "/interactives/slug-abcde123"
+                }
+            """
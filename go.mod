@@ -13,8 +13,10 @@ require (
 	github.com/ONSdigital/dp-mongodb/v3 v3.3.0
 	github.com/ONSdigital/dp-net v1.5.0
 	github.com/ONSdigital/dp-net/v2 v2.9.1
+	github.com/ONSdigital/dp-permissions-api v0.22.0
 	github.com/ONSdigital/dp-s3/v2 v2.0.0-beta.2
 	github.com/ONSdigital/log.go/v2 v2.4.1
+	github.com/Shopify/sarama v1.38.1
 	github.com/aws/aws-sdk-go v1.44.195
 	github.com/cucumber/godog v0.12.4
 	github.com/go-playground/mold/v4 v4.2.0
@@ -32,8 +34,6 @@ require (
 require (
 	github.com/ONSdigital/dp-api-clients-go v1.43.0 // indirect
 	github.com/ONSdigital/dp-mongodb-in-memory v1.3.1 // indirect
-	github.com/ONSdigital/dp-permissions-api v0.22.0 // indirect
-	github.com/Shopify/sarama v1.38.1 // indirect
 	github.com/chromedp/cdproto v0.0.0-20211126220118-81fa0469ad77 // indirect
 	github.com/chromedp/chromedp v0.7.6 // indirect
 	github.com/chromedp/sysutil v1.0.0 // indirect
@@ -0,0 +1,113 @@
+package content
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+var (
+	ErrNotFound     = errors.New("content not found")
+	ErrNotModified  = errors.New("content not modified")
+	ErrInvalidRange = errors.New("requested range not satisfiable")
+)
+
+// GetOptions are passed through to s3 so that ranges and conditional requests are answered there
+type GetOptions struct {
+	Range           string
+	IfNoneMatch     string
+	IfModifiedSince string
+}
+
+// Object is a file (or the requested range of one) being read from the store
+type Object struct {
+	Body          io.ReadCloser
+	ContentType   string
+	ContentLength int64
+	ContentRange  string
+	ETag          string
+	LastModified  time.Time
+}
+
+// Store reads the files of imported interactives from the bucket the importer extracts them into, and writes
+// the files served alongside them (such as thumbnails)
+type Store struct {
+	client s3iface.S3API
+	bucket string
+}
+
+func NewStore(client s3iface.S3API, bucket string) *Store {
+	return &Store{client: client, bucket: bucket}
+}
+
+func (s *Store) Get(ctx context.Context, key string, opts *GetOptions) (*Object, error) {
+	input := &s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)}
+	if opts != nil {
+		if opts.Range != "" {
+			input.Range = aws.String(opts.Range)
+		}
+		if opts.IfNoneMatch != "" {
+			input.IfNoneMatch = aws.String(opts.IfNoneMatch)
+		}
+		if t, err := http.ParseTime(opts.IfModifiedSince); err == nil {
+			input.IfModifiedSince = aws.Time(t)
+		}
+	}
+
+	out, err := s.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		var failure awserr.RequestFailure
+		if errors.As(err, &failure) {
+			switch failure.StatusCode() {
+			case http.StatusNotFound:
+				return nil, ErrNotFound
+			case http.StatusNotModified:
+				return nil, ErrNotModified
+			case http.StatusRequestedRangeNotSatisfiable:
+				return nil, ErrInvalidRange
+			}
+		}
+		return nil, fmt.Errorf("error reading %s from s3 %w", key, err)
+	}
+
+	return &Object{
+		Body:          out.Body,
+		ContentType:   aws.StringValue(out.ContentType),
+		ContentLength: aws.Int64Value(out.ContentLength),
+		ContentRange:  aws.StringValue(out.ContentRange),
+		ETag:          aws.StringValue(out.ETag),
+		LastModified:  aws.TimeValue(out.LastModified),
+	}, nil
+}
+
+// Put writes a file to the bucket, replacing any with the same key
+func (s *Store) Put(ctx context.Context, key, contentType string, body []byte) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Body:        bytes.NewReader(body),
+	})
+	if err != nil {
+		return fmt.Errorf("error writing %s to s3 %w", key, err)
+	}
+	return nil
+}
+
+// Checker reports whether the bucket can be reached
+func (s *Store) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	if _, err := s.client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)}); err != nil {
+		return state.Update(healthcheck.StatusCritical, fmt.Sprintf("content bucket unavailable: %s", err.Error()), 0)
+	}
+	return state.Update(healthcheck.StatusOK, "content bucket is ok", 0)
+}
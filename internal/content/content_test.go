@@ -0,0 +1,108 @@
+package content_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type fakeS3 struct {
+	s3iface.S3API
+	input  *s3.GetObjectInput
+	output *s3.GetObjectOutput
+	put    *s3.PutObjectInput
+	err    error
+}
+
+func (f *fakeS3) GetObjectWithContext(_ aws.Context, input *s3.GetObjectInput, _ ...request.Option) (*s3.GetObjectOutput, error) {
+	f.input = input
+	return f.output, f.err
+}
+
+func (f *fakeS3) PutObjectWithContext(_ aws.Context, input *s3.PutObjectInput, _ ...request.Option) (*s3.PutObjectOutput, error) {
+	f.put = input
+	return &s3.PutObjectOutput{}, f.err
+}
+
+func (f *fakeS3) HeadBucketWithContext(aws.Context, *s3.HeadBucketInput, ...request.Option) (*s3.HeadBucketOutput, error) {
+	return &s3.HeadBucketOutput{}, f.err
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	Convey("Given a store", t, func() {
+		client := &fakeS3{output: &s3.GetObjectOutput{
+			Body:          io.NopCloser(strings.NewReader("<html>")),
+			ContentType:   aws.String("text/html"),
+			ContentLength: aws.Int64(6),
+			ContentRange:  aws.String("bytes 0-5/100"),
+			ETag:          aws.String(`"etag"`),
+			LastModified:  aws.Time(modified),
+		}}
+		store := content.NewStore(client, "bucket")
+
+		Convey("Then ranges and conditions are passed on to s3", func() {
+			o, err := store.Get(ctx, "root/index.html", &content.GetOptions{
+				Range:           "bytes=0-5",
+				IfNoneMatch:     `"old"`,
+				IfModifiedSince: modified.Format(http.TimeFormat),
+			})
+			So(err, ShouldBeNil)
+			So(aws.StringValue(client.input.Bucket), ShouldEqual, "bucket")
+			So(aws.StringValue(client.input.Key), ShouldEqual, "root/index.html")
+			So(aws.StringValue(client.input.Range), ShouldEqual, "bytes=0-5")
+			So(aws.StringValue(client.input.IfNoneMatch), ShouldEqual, `"old"`)
+			So(aws.TimeValue(client.input.IfModifiedSince), ShouldEqual, modified)
+
+			So(o.ContentType, ShouldEqual, "text/html")
+			So(o.ContentLength, ShouldEqual, 6)
+			So(o.ContentRange, ShouldEqual, "bytes 0-5/100")
+			So(o.ETag, ShouldEqual, `"etag"`)
+			So(o.LastModified, ShouldEqual, modified)
+		})
+
+		Convey("Then files are written with their content type", func() {
+			So(store.Put(ctx, "thumbnails/an-id/v1/640.png", "image/png", []byte("png")), ShouldBeNil)
+			So(aws.StringValue(client.put.Bucket), ShouldEqual, "bucket")
+			So(aws.StringValue(client.put.Key), ShouldEqual, "thumbnails/an-id/v1/640.png")
+			So(aws.StringValue(client.put.ContentType), ShouldEqual, "image/png")
+			body, _ := io.ReadAll(client.put.Body)
+			So(string(body), ShouldEqual, "png")
+		})
+
+		Convey("Then s3 failures are mapped to the errors callers answer", func() {
+			for status, expected := range map[int]error{
+				http.StatusNotFound:                     content.ErrNotFound,
+				http.StatusNotModified:                  content.ErrNotModified,
+				http.StatusRequestedRangeNotSatisfiable: content.ErrInvalidRange,
+			} {
+				client.err = awserr.NewRequestFailure(awserr.New("code", "message", nil), status, "request-id")
+				_, err := store.Get(ctx, "root/index.html", nil)
+				So(err, ShouldEqual, expected)
+			}
+
+			client.err = errors.New("broken")
+			_, err := store.Get(ctx, "root/index.html", nil)
+			So(err.Error(), ShouldEqual, "error reading root/index.html from s3 broken")
+
+			state := healthcheck.NewCheckState("content")
+			So(store.Checker(ctx, state), ShouldBeNil)
+			So(state.Status(), ShouldEqual, healthcheck.StatusCritical)
+		})
+	})
+}
@@ -11,12 +11,34 @@ import (
 
 type Generator func(string) string
 
+// Languages interactives can be described in - English is the default
+const (
+	English = "en"
+	Welsh   = "cy"
+)
+
 var (
-	englishArticlesRegEx = regexp.MustCompile("\\b(a|an|and|the)\\b")
-	whitespaceRegEx      = regexp.MustCompile("\\s+")
-	resourceIdAlphabet   = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ" //default includes [-_]
+	slugRegEx          = regexp.MustCompile("^[\\p{Ll}\\p{Lo}\\p{Nd}]+(-[\\p{Ll}\\p{Lo}\\p{Nd}]+)*$")
+	resourceIdAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ" //default includes [-_]
+
+	// Languages lists the languages metadata can be given in, the default first
+	Languages = []string{English, Welsh}
+
+	// articles (and conjunctions) left out of slugs, by language
+	articles = map[string]map[string]bool{
+		English: {"a": true, "an": true, "and": true, "the": true},
+		Welsh:   {"y": true, "yr": true, "r": true, "a": true, "ac": true},
+	}
+	// elided articles are split off the word before them (e.g. i'r)
+	elides = map[string]bool{Welsh: true}
 )
 
+// IsLanguage is true for the languages metadata can be given in
+func IsLanguage(lang string) bool {
+	_, ok := articles[lang]
+	return ok
+}
+
 // GenerateResourceId should return "short, unique, non-incremental ID" - defined as: [A-Za-z0-9]{8}
 func GenerateResourceId() func(string) string {
 	return func(string) string {
@@ -26,22 +48,43 @@ func GenerateResourceId() func(string) string {
 
 // GenerateHumanReadableSlug will return a slug for given title - defined as:
 // 	  "Human readable slug is a short hyphenated slug that aligns with the title of the page, is clear and unambiguous, but is free from articles (a, an, the) and other superfluous words"
-// It can be edited manually removing superflous words before published.
+// It can be edited manually removing superflous words before published (see IsValidSlug).
 func GenerateHumanReadableSlug() func(string) string {
+	return GenerateLocalisedSlug(English)
+}
+
+// GenerateLocalisedSlug returns a slug generator that leaves out the articles of the given language, falling
+// back to English for languages it does not know
+func GenerateLocalisedSlug(lang string) func(string) string {
+	if !IsLanguage(lang) {
+		lang = English
+	}
+	omit, splitElisions := articles[lang], elides[lang]
 	return func(title string) string {
 		var stripped []rune
 		for _, c := range title {
 			if unicode.IsLetter(c) || unicode.IsDigit(c) {
 				stripped = append(stripped, unicode.ToLower(c))
-			} else if unicode.IsSpace(c) || c == '-' {
+			} else if unicode.IsSpace(c) || c == '-' || (splitElisions && (c == '\'' || c == '’')) {
 				stripped = append(stripped, ' ')
 			}
 		}
-		withoutArticles := englishArticlesRegEx.ReplaceAllString(string(stripped), "")
-		return whitespaceRegEx.ReplaceAllString(strings.TrimSpace(withoutArticles), "-")
+		var words []string
+		for _, word := range strings.Fields(string(stripped)) {
+			if !omit[word] {
+				words = append(words, word)
+			}
+		}
+		return strings.Join(words, "-")
 	}
 }
 
+// IsValidSlug is true for slugs that could have been generated: lowercase letters and digits, in words separated by
+// single hyphens. Slugs edited by hand must follow the same rules.
+func IsValidSlug(slug string) bool {
+	return slugRegEx.MatchString(slug)
+}
+
 func GenerateUUID() func(string) string {
 	return func(string) string {
 		if uid, err := uuid.NewV4(); err == nil {
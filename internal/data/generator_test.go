@@ -41,3 +41,38 @@ func TestGenerateHumanReadableSlug(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidSlug(t *testing.T) {
+	Convey("Generated slugs are valid", t, func() {
+		for _, title := range []string{"A Simple Title With An Article or Two", "GDP: 2021 - Q4", "Gŵyl y Gwanwyn"} {
+			So(data.IsValidSlug(slugGenerator(title)), ShouldBeTrue)
+		}
+	})
+
+	Convey("Slugs that could not have been generated are invalid", t, func() {
+		for _, slug := range []string{"", "Upper-case", "double--hyphen", "-leading", "trailing-", "with space", "under_score", "slash/es"} {
+			So(data.IsValidSlug(slug), ShouldBeFalse)
+		}
+	})
+}
+
+func TestGenerateLocalisedSlug(t *testing.T) {
+	welsh := data.GenerateLocalisedSlug(data.Welsh)
+
+	Convey("Welsh articles are left out of Welsh slugs", t, func() {
+		So(welsh("Y Gyfradd Chwyddiant a'r Economi"), ShouldEqual, "gyfradd-chwyddiant-economi")
+		So(welsh("Twf yr Economi"), ShouldEqual, "twf-economi")
+		So(welsh("Ystadegau i’r Cyhoedd"), ShouldEqual, "ystadegau-i-cyhoedd")
+		So(welsh("Gŵyl y Gwanwyn"), ShouldEqual, "gŵyl-gwanwyn")
+	})
+
+	Convey("English words are kept in Welsh slugs", t, func() {
+		So(welsh("The Economy"), ShouldEqual, "the-economy")
+	})
+
+	Convey("Unknown languages fall back to English", t, func() {
+		So(data.GenerateLocalisedSlug("xx")("The Economy"), ShouldEqual, "economy")
+		So(data.IsLanguage("xx"), ShouldBeFalse)
+		So(data.IsLanguage(data.Welsh), ShouldBeTrue)
+	})
+}
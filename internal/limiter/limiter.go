@@ -0,0 +1,145 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+)
+
+var (
+	// ErrFull is returned when every slot is taken and the queue is full
+	ErrFull = errors.New("too many uploads in progress")
+	// ErrQueueTimeout is returned when a slot did not come free in time
+	ErrQueueTimeout = errors.New("timed out waiting for an upload slot")
+)
+
+// Limiter bounds how many uploads are held at once, queueing a few more and turning the rest away.
+// A zero concurrency means uploads are not limited.
+type Limiter struct {
+	slots        chan struct{}
+	queueDepth   int
+	queueTimeout time.Duration
+	retryAfter   time.Duration
+
+	mu     sync.Mutex
+	queued int
+}
+
+func New(concurrency, queueDepth int, queueTimeout, retryAfter time.Duration) *Limiter {
+	l := &Limiter{queueDepth: queueDepth, queueTimeout: queueTimeout, retryAfter: retryAfter}
+	if concurrency > 0 {
+		l.slots = make(chan struct{}, concurrency)
+	}
+	return l
+}
+
+// Acquire takes a slot, waiting in the queue if they are all in use. The slot must be released once the
+// upload is no longer held, by whoever holds it last (see Slot.Transfer).
+func (l *Limiter) Acquire(ctx context.Context) (*Slot, error) {
+	if l == nil || l.slots == nil {
+		return nil, nil
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return &Slot{l: l}, nil
+	default:
+	}
+
+	l.mu.Lock()
+	if l.queued >= l.queueDepth {
+		l.mu.Unlock()
+		return nil, ErrFull
+	}
+	l.queued++
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		l.queued--
+		l.mu.Unlock()
+	}()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return &Slot{l: l}, nil
+	case <-timeout:
+		return nil, ErrQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// RetryAfter is how long a caller that was turned away should wait before trying again
+func (l *Limiter) RetryAfter() time.Duration {
+	if l == nil {
+		return 0
+	}
+	return l.retryAfter
+}
+
+// Utilisation is the number of slots in use and uploads queued, against the capacity of each
+type Utilisation struct {
+	InUse      int
+	Capacity   int
+	Queued     int
+	QueueDepth int
+}
+
+func (u Utilisation) String() string {
+	return fmt.Sprintf("%d of %d upload slots in use, %d of %d queued", u.InUse, u.Capacity, u.Queued, u.QueueDepth)
+}
+
+func (l *Limiter) Utilisation() Utilisation {
+	if l == nil || l.slots == nil {
+		return Utilisation{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Utilisation{InUse: len(l.slots), Capacity: cap(l.slots), Queued: l.queued, QueueDepth: l.queueDepth}
+}
+
+// Checker reports utilisation, warning once uploads are being turned away
+func (l *Limiter) Checker(_ context.Context, state *healthcheck.CheckState) error {
+	if l == nil || l.slots == nil {
+		return state.Update(healthcheck.StatusOK, "uploads are not limited", 0)
+	}
+	u := l.Utilisation()
+	if u.InUse == u.Capacity && u.Queued >= u.QueueDepth {
+		return state.Update(healthcheck.StatusWarning, fmt.Sprintf("at capacity: %s", u), 0)
+	}
+	return state.Update(healthcheck.StatusOK, u.String(), 0)
+}
+
+// Slot is held for as long as an upload is using disk, memory or bandwidth
+type Slot struct {
+	l    *Limiter
+	once sync.Once
+}
+
+// Release frees the slot. It is safe to call more than once, and on a nil slot.
+func (s *Slot) Release() {
+	if s == nil {
+		return
+	}
+	s.once.Do(func() { <-s.l.slots })
+}
+
+// Transfer hands the slot on (e.g. to a background upload), after which releasing this one does nothing.
+// A slot that has already been released transfers as nil.
+func (s *Slot) Transfer() (transferred *Slot) {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() { transferred = &Slot{l: s.l} })
+	return transferred
+}
@@ -0,0 +1,94 @@
+package limiter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-interactives-api/internal/limiter"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a limiter with one slot and a queue of one", t, func() {
+		l := limiter.New(1, 1, time.Second, 30*time.Second)
+		held, err := l.Acquire(ctx)
+		So(err, ShouldBeNil)
+		So(l.Utilisation(), ShouldResemble, limiter.Utilisation{InUse: 1, Capacity: 1, QueueDepth: 1})
+
+		Convey("Then a queued upload gets the slot once it is released", func() {
+			acquired := make(chan *limiter.Slot)
+			go func() {
+				slot, _ := l.Acquire(ctx)
+				acquired <- slot
+			}()
+			So(waitFor(func() bool { return l.Utilisation().Queued == 1 }), ShouldBeTrue)
+
+			held.Release()
+			held.Release()
+			slot := <-acquired
+			So(slot, ShouldNotBeNil)
+			So(l.Utilisation(), ShouldResemble, limiter.Utilisation{InUse: 1, Capacity: 1, QueueDepth: 1})
+		})
+
+		Convey("Then an upload beyond the queue is turned away and the health check warns", func() {
+			go l.Acquire(ctx)
+			So(waitFor(func() bool { return l.Utilisation().Queued == 1 }), ShouldBeTrue)
+
+			_, err := l.Acquire(ctx)
+			So(err, ShouldEqual, limiter.ErrFull)
+
+			state := healthcheck.NewCheckState("uploads")
+			So(l.Checker(ctx, state), ShouldBeNil)
+			So(state.Status(), ShouldEqual, healthcheck.StatusWarning)
+			So(state.Message(), ShouldEqual, "at capacity: 1 of 1 upload slots in use, 1 of 1 queued")
+		})
+
+		Convey("Then a queued upload gives up after the timeout", func() {
+			l := limiter.New(1, 1, time.Millisecond, 0)
+			_, err := l.Acquire(ctx)
+			So(err, ShouldBeNil)
+			_, err = l.Acquire(ctx)
+			So(err, ShouldEqual, limiter.ErrQueueTimeout)
+		})
+
+		Convey("Then a transferred slot is only released by its new holder", func() {
+			background := held.Transfer()
+			held.Release()
+			So(l.Utilisation().InUse, ShouldEqual, 1)
+			So(held.Transfer(), ShouldBeNil)
+
+			background.Release()
+			So(l.Utilisation().InUse, ShouldEqual, 0)
+
+			state := healthcheck.NewCheckState("uploads")
+			So(l.Checker(ctx, state), ShouldBeNil)
+			So(state.Status(), ShouldEqual, healthcheck.StatusOK)
+		})
+	})
+
+	Convey("Given a limiter without a concurrency", t, func() {
+		l := limiter.New(0, 0, 0, 0)
+
+		Convey("Then uploads are not limited", func() {
+			for i := 0; i < 10; i++ {
+				slot, err := l.Acquire(ctx)
+				So(err, ShouldBeNil)
+				So(slot, ShouldBeNil)
+				So(slot.Release, ShouldNotPanic)
+			}
+		})
+	})
+}
+
+func waitFor(cond func() bool) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,332 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// the operations of RFC 6902
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+var (
+	ErrInvalidOperation = errors.New("invalid patch operation")
+	ErrPathNotFound     = errors.New("path not found")
+	ErrTestFailed       = errors.New("test operation failed")
+)
+
+// Operation is one step of an RFC 6902 JSON patch. Paths are JSON pointers (RFC 6901).
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Decode reads a JSON patch, checking that each operation has the members it needs
+func Decode(b []byte) ([]Operation, error) {
+	var ops []Operation
+	if err := json.Unmarshal(b, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOperation, err.Error())
+	}
+	// an empty from points at the whole document, so whether it was given at all needs its own look
+	var froms []struct {
+		From *string `json:"from"`
+	}
+	if err := json.Unmarshal(b, &froms); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOperation, err.Error())
+	}
+	for n, op := range ops {
+		if (op.Op == OpMove || op.Op == OpCopy) && froms[n].From == nil {
+			return nil, fmt.Errorf("%w: %s needs a from (operation %d)", ErrInvalidOperation, op.Op, n)
+		}
+		if err := op.check(); err != nil {
+			return nil, fmt.Errorf("%w (operation %d)", err, n)
+		}
+	}
+	return ops, nil
+}
+
+func (op Operation) check() error {
+	if _, err := pointer(op.Path); err != nil {
+		return err
+	}
+	switch op.Op {
+	case OpAdd, OpReplace, OpTest:
+		if op.Value == nil {
+			return fmt.Errorf("%w: %s needs a value", ErrInvalidOperation, op.Op)
+		}
+	case OpMove, OpCopy:
+		if _, err := pointer(op.From); err != nil {
+			return err
+		}
+		if op.Op == OpMove && strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+			return fmt.Errorf("%w: cannot move %s into itself", ErrInvalidOperation, op.From)
+		}
+	case OpRemove:
+	default:
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidOperation, op.Op)
+	}
+	return nil
+}
+
+// Apply applies the operations to a JSON document in order. If any fails none of them are applied.
+func Apply(doc []byte, ops []Operation) ([]byte, error) {
+	var root interface{}
+	if err := unmarshal(doc, &root); err != nil {
+		return nil, fmt.Errorf("invalid document %w", err)
+	}
+	for n, op := range ops {
+		var err error
+		if root, err = op.apply(root); err != nil {
+			return nil, fmt.Errorf("%w (operation %d, %s %s)", err, n, op.Op, op.Path)
+		}
+	}
+	return json.Marshal(root)
+}
+
+func (op Operation) apply(root interface{}) (interface{}, error) {
+	path, err := pointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if op.Value != nil {
+		if err = unmarshal(op.Value, &value); err != nil {
+			return nil, fmt.Errorf("%w: invalid value %s", ErrInvalidOperation, err.Error())
+		}
+	}
+
+	switch op.Op {
+	case OpAdd:
+		return add(root, path, value)
+	case OpRemove:
+		return remove(root, path)
+	case OpReplace:
+		if len(path) == 0 {
+			return value, nil
+		}
+		if root, err = remove(root, path); err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case OpMove, OpCopy:
+		from, err := pointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = get(root, from); err != nil {
+			return nil, err
+		}
+		if op.Op == OpMove {
+			if root, err = remove(root, from); err != nil {
+				return nil, err
+			}
+		} else {
+			value = deepCopy(value)
+		}
+		return add(root, path, value)
+	case OpTest:
+		current, err := get(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, ErrTestFailed
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidOperation, op.Op)
+}
+
+// pointer splits a JSON pointer into its unescaped reference tokens - none for the whole document
+func pointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("%w: %q is not a JSON pointer", ErrInvalidOperation, path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for n, t := range tokens {
+		tokens[n] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[token]
+			if !ok {
+				return nil, ErrPathNotFound
+			}
+			node = child
+		case []interface{}:
+			i, err := index(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, ErrPathNotFound
+		}
+	}
+	return node, nil
+}
+
+func add(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modify(root, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[key] = value
+			return p, nil
+		case []interface{}:
+			if key == "-" {
+				return append(p, value), nil
+			}
+			i, err := index(key, len(p))
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[i+1:], p[i:])
+			p[i] = value
+			return p, nil
+		}
+		return nil, ErrPathNotFound
+	})
+}
+
+func remove(root interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidOperation)
+	}
+	return modify(root, path, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := p[key]; !ok {
+				return nil, ErrPathNotFound
+			}
+			delete(p, key)
+			return p, nil
+		case []interface{}:
+			i, err := index(key, len(p)-1)
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:i], p[i+1:]...), nil
+		}
+		return nil, ErrPathNotFound
+	})
+}
+
+// modify replaces the parent of the last token with the result of change. Arrays can grow or shrink, so each
+// container on the way down is put back into its own parent.
+func modify(node interface{}, path []string, change func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return change(node, path[0])
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, ErrPathNotFound
+		}
+		updated, err := modify(child, path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = updated
+		return n, nil
+	case []interface{}:
+		i, err := index(path[0], len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := modify(n[i], path[1:], change)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = updated
+		return n, nil
+	}
+	return nil, ErrPathNotFound
+}
+
+// index reads an array index, which must be no greater than max and written without leading zeros
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, ErrPathNotFound
+	}
+	return i, nil
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch n := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(n))
+		for k, child := range n {
+			c[k] = deepCopy(child)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(n))
+		for i, child := range n {
+			c[i] = deepCopy(child)
+		}
+		return c
+	}
+	return v
+}
+
+// equal compares JSON values, numbers by value rather than as written (1 and 1.0 are equal)
+func equal(a, b interface{}) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
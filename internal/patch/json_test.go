@@ -0,0 +1,77 @@
+package patch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/patch"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApply(t *testing.T) {
+	// examples from appendix A of RFC 6902
+	examples := []struct {
+		title, doc, patch, result string
+	}{
+		{"add an object member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"add an array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"remove an object member", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"remove an array element", `{"foo":["bar","qux","baz"]}`, `[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"replace a value", `{"baz":"qux","foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"move a value", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"move an array element", `{"foo":["all","grass","cows","eat"]}`, `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"test then add", `{"baz":"qux","foo":["a",2,"c"]}`, `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0},{"op":"add","path":"/foo/-","value":"d"}]`, `{"baz":"qux","foo":["a",2,"c","d"]}`},
+		{"add a nested member object", `{"foo":"bar"}`, `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{"escape ~ and /", `{"/":9,"~1":10}`, `[{"op":"test","path":"/~01","value":10},{"op":"remove","path":"/~1"}]`, `{"~1":10}`},
+		{"add an array value", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"add a null", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":null}]`, `{"baz":null,"foo":"bar"}`},
+		{"copy a value", `{"foo":{"bar":1}}`, `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`, `{"baz":{"bar":2},"foo":{"bar":1}}`},
+		{"replace the document", `{"foo":"bar"}`, `[{"op":"replace","path":"","value":{"baz":"qux"}}]`, `{"baz":"qux"}`},
+	}
+
+	Convey("Given the examples from the RFC", t, func() {
+		for _, e := range examples {
+			ops, err := patch.Decode([]byte(e.patch))
+			So(err, ShouldBeNil)
+			result, err := patch.Apply([]byte(e.doc), ops)
+			So(err, ShouldBeNil)
+			So(string(result), ShouldEqual, e.result)
+		}
+	})
+
+	failures := []struct {
+		title, doc, patch string
+		err               error
+	}{
+		{"remove a missing member", `{"foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, patch.ErrPathNotFound},
+		{"add to a missing parent", `{"foo":"bar"}`, `[{"op":"add","path":"/baz/bat","value":"qux"}]`, patch.ErrPathNotFound},
+		{"add beyond the end of an array", `{"foo":["bar"]}`, `[{"op":"add","path":"/foo/2","value":"qux"}]`, patch.ErrPathNotFound},
+		{"index with leading zeros", `{"foo":["bar","baz"]}`, `[{"op":"remove","path":"/foo/01"}]`, patch.ErrPathNotFound},
+		{"replace a missing member", `{"foo":"bar"}`, `[{"op":"replace","path":"/baz","value":"qux"}]`, patch.ErrPathNotFound},
+		{"test a different value", `{"baz":"qux"}`, `[{"op":"test","path":"/baz","value":"bar"}]`, patch.ErrTestFailed},
+		{"test a string against a number", `{"baz":"10"}`, `[{"op":"test","path":"/baz","value":10}]`, patch.ErrTestFailed},
+	}
+
+	Convey("Given operations that cannot be applied", t, func() {
+		for _, f := range failures {
+			ops, err := patch.Decode([]byte(f.patch))
+			So(err, ShouldBeNil)
+			_, err = patch.Apply([]byte(f.doc), ops)
+			So(errors.Is(err, f.err), ShouldBeTrue)
+		}
+	})
+
+	Convey("Given operations that are not valid", t, func() {
+		for _, p := range []string{
+			`{"op":"add","path":"/a","value":1}`,
+			`[{"op":"add","path":"/a"}]`,
+			`[{"op":"replace","path":"a","value":1}]`,
+			`[{"op":"move","from":"/a","path":"/a/b"}]`,
+			`[{"op":"copy","path":"/a"}]`,
+			`[{"op":"merge","path":"/a","value":1}]`,
+		} {
+			_, err := patch.Decode([]byte(p))
+			So(errors.Is(err, patch.ErrInvalidOperation), ShouldBeTrue)
+		}
+	})
+}
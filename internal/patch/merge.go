@@ -0,0 +1,53 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Merge applies an RFC 7396 merge patch to a JSON document. Members of the patch replace those of the document,
+// objects are merged recursively and a null removes the member. A patch that is not an object replaces the
+// document as a whole.
+func Merge(doc, patch []byte) ([]byte, error) {
+	var target, changes interface{}
+	if err := unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document %w", err)
+	}
+	if err := unmarshal(patch, &changes); err != nil {
+		return nil, fmt.Errorf("invalid merge patch %w", err)
+	}
+	return json.Marshal(merge(target, changes))
+}
+
+func merge(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]interface{})
+	if !ok {
+		doc = map[string]interface{}{}
+	}
+	for k, v := range changes {
+		if v == nil {
+			delete(doc, k)
+			continue
+		}
+		doc[k] = merge(doc[k], v)
+	}
+	return doc
+}
+
+// unmarshal keeps numbers as they were written rather than converting them to floats
+func unmarshal(b []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if d.More() {
+		return fmt.Errorf("unexpected data after the top-level value")
+	}
+	return nil
+}
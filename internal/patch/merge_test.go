@@ -0,0 +1,52 @@
+package patch_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/patch"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMerge(t *testing.T) {
+	// the examples from appendix A of RFC 7396
+	examples := []struct {
+		doc, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	Convey("Given the examples from the RFC", t, func() {
+		for _, e := range examples {
+			result, err := patch.Merge([]byte(e.doc), []byte(e.patch))
+			So(err, ShouldBeNil)
+			So(string(result), ShouldEqual, e.result)
+		}
+	})
+
+	Convey("Given large numbers, they are kept as written", t, func() {
+		result, err := patch.Merge([]byte(`{"size":9007199254740993}`), []byte(`{"a":1}`))
+		So(err, ShouldBeNil)
+		So(string(result), ShouldEqual, `{"a":1,"size":9007199254740993}`)
+	})
+
+	Convey("Given a patch that is not json, it is an error", t, func() {
+		_, err := patch.Merge([]byte(`{}`), []byte(`{"a":`))
+		So(err, ShouldNotBeNil)
+		_, err = patch.Merge([]byte(`{}`), []byte(`{} {}`))
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,209 @@
+package progress
+
+import (
+	"io"
+	"sync"
+)
+
+// Kinds of update sent to subscribers
+const (
+	TypeState    = "state"
+	TypeProgress = "progress"
+)
+
+// subscriberBuffer is how many updates a slow subscriber can fall behind before updates are dropped for it
+const subscriberBuffer = 32
+
+// Update is a change to an interactive as it moves through upload and import
+type Update struct {
+	Type          string `json:"-"`
+	InteractiveID string `json:"interactive_id"`
+	State         string `json:"state,omitempty"`
+	BytesUploaded int64  `json:"bytes_uploaded,omitempty"`
+	TotalBytes    int64  `json:"total_bytes,omitempty"`
+}
+
+// Broker fans updates out to everyone watching an interactive. It is in-process only - subscribers
+// hear about changes made by this instance of the api.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan *Update]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: map[string]map[chan *Update]struct{}{}}
+}
+
+// Subscribe returns a channel of updates for the interactive and a func that must be called to stop them
+func (b *Broker) Subscribe(id string) (<-chan *Update, func()) {
+	ch := make(chan *Update, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[id] == nil {
+		b.subscribers[id] = map[chan *Update]struct{}{}
+	}
+	b.subscribers[id][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[id], ch)
+			if len(b.subscribers[id]) == 0 {
+				delete(b.subscribers, id)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Publish sends the update to the interactive's subscribers without blocking - a subscriber whose buffer
+// is full misses it
+func (b *Broker) Publish(u *Update) {
+	if b == nil || u == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers[u.InteractiveID] {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
+
+// State announces that the interactive has moved to state
+func (b *Broker) State(id, state string) {
+	b.Publish(&Update{Type: TypeState, InteractiveID: id, State: state})
+}
+
+// Reader wraps r so that reading it reports upload progress for the interactive. Updates are sent
+// roughly every percent of total so that large archives do not flood subscribers. If r can also be read
+// at an offset and seeked, as a file can, so can the returned reader - s3manager then reads each part
+// from r as it is sent rather than buffering it - and each byte is counted once, however often it is read.
+func (b *Broker) Reader(id string, r io.Reader, total int64) io.Reader {
+	step := total / 100
+	if step < minStep {
+		step = minStep
+	}
+	pr := &reader{broker: b, id: id, r: r, total: total, step: step}
+	if rs, ok := r.(readSeekerAt); ok {
+		return &seekableReader{reader: pr, rs: rs}
+	}
+	return pr
+}
+
+// minStep is the fewest bytes read between progress updates
+const minStep = 64 * 1024
+
+type reader struct {
+	broker   *Broker
+	id       string
+	r        io.Reader
+	total    int64
+	step     int64
+	read     int64
+	reported int64
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.count(int64(n), err == io.EOF)
+	return n, err
+}
+
+// count adds n to the bytes read, publishing progress once step more have been read and at the end
+func (r *reader) count(n int64, end bool) {
+	r.read += n
+	if r.read-r.reported >= r.step || (end && r.read != r.reported) {
+		r.reported = r.read
+		r.broker.Publish(&Update{Type: TypeProgress, InteractiveID: r.id, BytesUploaded: r.read, TotalBytes: r.total})
+	}
+}
+
+type readSeekerAt interface {
+	io.ReadSeeker
+	io.ReaderAt
+}
+
+// seekableReader reports progress for a reader read at offsets, possibly concurrently and more than once
+// (s3manager reads each part to sign it before sending it)
+type seekableReader struct {
+	*reader
+	rs    readSeekerAt
+	mu    sync.Mutex
+	pos   int64
+	spans []span
+}
+
+// span is a range of bytes that have been read, from start up to end
+type span struct {
+	start, end int64
+}
+
+func (s *seekableReader) Read(p []byte) (int, error) {
+	n, err := s.rs.Read(p)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cover(s.pos, n, err == io.EOF)
+	s.pos += int64(n)
+	return n, err
+}
+
+func (s *seekableReader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := s.rs.ReadAt(p, off)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cover(off, n, err == io.EOF)
+	return n, err
+}
+
+func (s *seekableReader) Seek(offset int64, whence int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pos, err := s.rs.Seek(offset, whence)
+	if err == nil {
+		s.pos = pos
+	}
+	return pos, err
+}
+
+// cover counts the bytes from off to off+n that have not been read before. The caller holds mu.
+func (s *seekableReader) cover(off int64, n int, eof bool) {
+	start, end := off, off+int64(n)
+	covered := end - start
+	spans := s.spans[:0]
+	for _, sp := range s.spans {
+		if sp.end < start || sp.start > end {
+			spans = append(spans, sp)
+			continue
+		}
+		// overlapping or adjacent - merged into the new span, without counting the overlap again
+		covered -= overlap(sp, off, off+int64(n))
+		if sp.start < start {
+			start = sp.start
+		}
+		if sp.end > end {
+			end = sp.end
+		}
+	}
+	s.spans = append(spans, span{start: start, end: end})
+	s.count(covered, eof || s.read+covered >= s.total)
+}
+
+// overlap is how many bytes of sp lie between start and end
+func overlap(sp span, start, end int64) int64 {
+	if sp.start > start {
+		start = sp.start
+	}
+	if sp.end < end {
+		end = sp.end
+	}
+	if end < start {
+		return 0
+	}
+	return end - start
+}
@@ -0,0 +1,88 @@
+package progress_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/progress"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBroker(t *testing.T) {
+	Convey("Given a broker with a subscriber", t, func() {
+		b := progress.NewBroker()
+		updates, cancel := b.Subscribe("id")
+
+		Convey("Then state changes for the interactive are delivered", func() {
+			b.State("other-id", "ImportSuccess")
+			b.State("id", "ArchiveUploaded")
+
+			u := <-updates
+			So(u.Type, ShouldEqual, progress.TypeState)
+			So(u.InteractiveID, ShouldEqual, "id")
+			So(u.State, ShouldEqual, "ArchiveUploaded")
+			So(updates, ShouldBeEmpty)
+		})
+
+		Convey("Then nothing is delivered after cancelling", func() {
+			cancel()
+			cancel()
+			b.State("id", "ArchiveUploaded")
+			So(updates, ShouldBeEmpty)
+		})
+
+		Convey("Then a subscriber that falls behind does not block publishers", func() {
+			for i := 0; i < 100; i++ {
+				b.State("id", "ArchiveUploaded")
+			}
+			So(len(updates), ShouldBeLessThan, 100)
+		})
+
+		Convey("Then reading an upload reports progress through to the total", func() {
+			total := int64(1024 * 1024)
+			_, err := io.Copy(io.Discard, b.Reader("id", bytes.NewReader(make([]byte, total)), total))
+			So(err, ShouldBeNil)
+
+			var last *progress.Update
+			for len(updates) > 0 {
+				last = <-updates
+				So(last.Type, ShouldEqual, progress.TypeProgress)
+			}
+			So(last, ShouldNotBeNil)
+			So(last.BytesUploaded, ShouldEqual, total)
+			So(last.TotalBytes, ShouldEqual, total)
+		})
+
+		Convey("Then an upload read in parts, some more than once, keeps the source seekable and counts each byte once", func() {
+			total := int64(1024 * 1024)
+			r := b.Reader("id", bytes.NewReader(make([]byte, total)), total)
+			ra, ok := r.(io.ReaderAt)
+			So(ok, ShouldBeTrue)
+			_, ok = r.(io.Seeker)
+			So(ok, ShouldBeTrue)
+
+			part := make([]byte, total/4)
+			for _, off := range []int64{total / 4, 0, total / 4, 3 * total / 4, total / 2} {
+				_, err := ra.ReadAt(part, off)
+				So(err, ShouldBeNil)
+			}
+
+			var last *progress.Update
+			for len(updates) > 0 {
+				u := <-updates
+				if last != nil {
+					So(u.BytesUploaded, ShouldBeGreaterThan, last.BytesUploaded)
+				}
+				last = u
+			}
+			So(last, ShouldNotBeNil)
+			So(last.BytesUploaded, ShouldEqual, total)
+		})
+	})
+
+	Convey("A nil broker ignores updates", t, func() {
+		var b *progress.Broker
+		So(func() { b.State("id", "ArchiveUploaded") }, ShouldNotPanic)
+	})
+}
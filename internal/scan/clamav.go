@@ -0,0 +1,154 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+)
+
+const chunkSize = 64 << 10
+
+// ClamAV scans uploads by streaming them to clamd over TCP (the INSTREAM command). Timeout bounds connecting
+// and each read or write on the connection, not the whole scan, so slow uploads are not cut off.
+type ClamAV struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+// NewClamAV returns a scanner for the clamd listening on addr (host:port)
+func NewClamAV(addr string, timeout time.Duration) *ClamAV {
+	return &ClamAV{Addr: addr, Timeout: timeout}
+}
+
+// Scan streams r to clamd and returns its verdict. clamd closes the stream with an error once
+// its StreamMaxLength is reached, so that limit must be at least the maximum upload size.
+func (c *ClamAV) Scan(ctx context.Context, r io.Reader) (*Result, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("clamd write error %w", err)
+	}
+
+	buf := make([]byte, chunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err = conn.Write(size); err != nil {
+				return nil, fmt.Errorf("clamd write error %w", err)
+			}
+			if _, err = conn.Write(buf[:n]); err != nil {
+				return nil, fmt.Errorf("clamd write error %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	if _, err = conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, fmt.Errorf("clamd write error %w", err)
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseReply(reply)
+}
+
+// Checker pings clamd
+func (c *ClamAV) Checker(ctx context.Context, state *healthcheck.CheckState) error {
+	reply, err := c.ping(ctx)
+	if err != nil {
+		return state.Update(healthcheck.StatusCritical, fmt.Sprintf("clamd unavailable: %s", err.Error()), 0)
+	}
+	if reply != "PONG" {
+		return state.Update(healthcheck.StatusCritical, fmt.Sprintf("unexpected clamd reply: %s", reply), 0)
+	}
+	return state.Update(healthcheck.StatusOK, "clamd is ok", 0)
+}
+
+func (c *ClamAV) ping(ctx context.Context) (string, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte("zPING\x00")); err != nil {
+		return "", err
+	}
+	return readReply(conn)
+}
+
+func (c *ClamAV) dial(ctx context.Context) (net.Conn, error) {
+	d := net.Dialer{Timeout: c.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", c.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to clamd %w", err)
+	}
+	if c.Timeout > 0 {
+		return &idleConn{Conn: conn, timeout: c.Timeout}, nil
+	}
+	return conn, nil
+}
+
+// idleConn moves the deadline on before every read and write, so it only expires when clamd
+// or the upload stall for longer than timeout
+type idleConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *idleConn) Write(b []byte) (int, error) {
+	if err := c.Conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+func readReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return "", fmt.Errorf("clamd read error %w", err)
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// parseReply understands "stream: OK", "stream: <signature> FOUND" and "<message> ERROR"
+func parseReply(reply string) (*Result, error) {
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return &Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return &Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	case strings.HasSuffix(reply, " ERROR"):
+		return nil, fmt.Errorf("clamd error: %s", strings.TrimSuffix(reply, " ERROR"))
+	default:
+		return nil, fmt.Errorf("unexpected clamd reply: %s", reply)
+	}
+}
@@ -0,0 +1,181 @@
+package scan_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeClamd answers PING and INSTREAM like clamd, replying to each stream with reply(streamed)
+func fakeClamd(t *testing.T, reply func(streamed []byte) string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				cmd, err := r.ReadString(0)
+				if err != nil {
+					return
+				}
+				switch cmd {
+				case "zPING\x00":
+					conn.Write([]byte("PONG\x00"))
+				case "zINSTREAM\x00":
+					var streamed bytes.Buffer
+					size := make([]byte, 4)
+					for {
+						if _, err = io.ReadFull(r, size); err != nil {
+							return
+						}
+						n := binary.BigEndian.Uint32(size)
+						if n == 0 {
+							break
+						}
+						if _, err = io.CopyN(&streamed, r, int64(n)); err != nil {
+							return
+						}
+					}
+					conn.Write([]byte(reply(streamed.Bytes()) + "\x00"))
+				}
+			}(conn)
+		}
+	}()
+	return l.Addr().String()
+}
+
+func eicarReply(streamed []byte) string {
+	if bytes.Contains(streamed, []byte(scan.Eicar)) {
+		return "stream: Win.Test.EICAR_HDB-1 FOUND"
+	}
+	return "stream: OK"
+}
+
+// slowReader returns one chunk per read, waiting delay before each
+type slowReader struct {
+	chunks []string
+	delay  time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func TestClamAV(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given a clamd server", t, func() {
+		clamav := scan.NewClamAV(fakeClamd(t, eicarReply), time.Second)
+
+		Convey("Then a clean upload is not infected", func() {
+			result, err := clamav.Scan(ctx, strings.NewReader(strings.Repeat("clean ", 50000)))
+			So(err, ShouldBeNil)
+			So(result.Infected, ShouldBeFalse)
+		})
+
+		Convey("Then the eicar test file is reported with its signature", func() {
+			result, err := clamav.Scan(ctx, strings.NewReader("prefix "+scan.Eicar))
+			So(err, ShouldBeNil)
+			So(result.Infected, ShouldBeTrue)
+			So(result.Signature, ShouldEqual, "Win.Test.EICAR_HDB-1")
+		})
+
+		Convey("Then the health check is ok", func() {
+			state := healthcheck.NewCheckState("Malware scanner")
+			So(clamav.Checker(ctx, state), ShouldBeNil)
+			So(state.Status(), ShouldEqual, healthcheck.StatusOK)
+		})
+	})
+
+	Convey("Given an upload that arrives slower than the timeout", t, func() {
+		clamav := scan.NewClamAV(fakeClamd(t, eicarReply), 100*time.Millisecond)
+		upload := &slowReader{chunks: []string{"prefix ", scan.Eicar, " suffix"}, delay: 60 * time.Millisecond}
+
+		Convey("Then it is scanned as long as each chunk arrives within the timeout", func() {
+			result, err := clamav.Scan(ctx, upload)
+			So(err, ShouldBeNil)
+			So(result.Infected, ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a clamd server that cannot scan the stream", t, func() {
+		clamav := scan.NewClamAV(fakeClamd(t, func([]byte) string { return "INSTREAM size limit exceeded. ERROR" }), time.Second)
+
+		Convey("Then the scan fails", func() {
+			_, err := clamav.Scan(ctx, strings.NewReader("too big"))
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "size limit exceeded")
+		})
+	})
+
+	Convey("Given clamd is not running", t, func() {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		addr := l.Addr().String()
+		l.Close()
+		clamav := scan.NewClamAV(addr, time.Second)
+
+		Convey("Then the scan fails", func() {
+			_, err := clamav.Scan(ctx, strings.NewReader("anything"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Then the health check is critical", func() {
+			state := healthcheck.NewCheckState("Malware scanner")
+			So(clamav.Checker(ctx, state), ShouldBeNil)
+			So(state.Status(), ShouldEqual, healthcheck.StatusCritical)
+		})
+	})
+}
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given the fake scanner", t, func() {
+		fake := &scan.Fake{}
+
+		Convey("Then the eicar test file is infected", func() {
+			result, err := fake.Scan(ctx, strings.NewReader(scan.Eicar))
+			So(err, ShouldBeNil)
+			So(result.Infected, ShouldBeTrue)
+		})
+
+		Convey("Then anything else is clean", func() {
+			result, err := fake.Scan(ctx, strings.NewReader("clean"))
+			So(err, ShouldBeNil)
+			So(result.Infected, ShouldBeFalse)
+		})
+
+		Convey("Then a configured error is returned", func() {
+			fake.Err = errors.New("scanner down")
+			_, err := fake.Scan(ctx, strings.NewReader("clean"))
+			So(err, ShouldEqual, fake.Err)
+		})
+	})
+}
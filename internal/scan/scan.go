@@ -0,0 +1,50 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
+)
+
+// Eicar is the standard antivirus test file - every scanner reports it as infected
+const Eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// Result is the verdict on a scanned upload
+type Result struct {
+	Infected  bool
+	Signature string
+}
+
+// Fake is a stand-in scanner for local development and tests. Anything containing Signature
+// (the EICAR test string by default) is reported as infected, or Err is returned if set.
+type Fake struct {
+	Signature string
+	Err       error
+}
+
+// Scan reads the whole of r looking for the fake signature
+func (f *Fake) Scan(_ context.Context, r io.Reader) (*Result, error) {
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	signature := f.Signature
+	if signature == "" {
+		signature = Eicar
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(b, []byte(signature)) {
+		return &Result{Infected: true, Signature: "Fake-Signature"}, nil
+	}
+	return &Result{}, nil
+}
+
+// Checker always reports the fake as healthy
+func (f *Fake) Checker(_ context.Context, state *healthcheck.CheckState) error {
+	return state.Update(healthcheck.StatusOK, "fake scanner", 0)
+}
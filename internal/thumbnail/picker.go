@@ -0,0 +1,63 @@
+package thumbnail
+
+import (
+	"path"
+	"strings"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+)
+
+var (
+	conventionalNames      = []string{"thumbnail", "preview"}
+	conventionalExtensions = map[string]bool{".png": true, ".jpg": true, ".jpeg": true, ".gif": true}
+)
+
+// Picker is a content scanner that finds the image an archive holds as its thumbnail. By convention that is
+// thumbnail.png (or .jpg, .jpeg or .gif) at the root of the archive, or in the one folder the archive is
+// wrapped in, with preview.png and the like used if there is none. It reports no findings.
+type Picker struct {
+	image []byte
+	rank  int
+}
+
+// Wants only entries that could be a better thumbnail than the one already found
+func (p *Picker) Wants(entry string) bool {
+	rank := conventionalRank(entry)
+	return rank != 0 && (p.image == nil || rank < p.rank)
+}
+
+func (p *Picker) Scan(entry string, content []byte) []*models.ContentFinding {
+	if !p.Wants(entry) || len(content) == 0 || len(content) > MaxSize {
+		return nil
+	}
+	p.image, p.rank = append([]byte(nil), content...), conventionalRank(entry)
+	return nil
+}
+
+// Image is the thumbnail found in the archive, nil if there was none
+func (p *Picker) Image() []byte {
+	if p == nil {
+		return nil
+	}
+	return p.image
+}
+
+// conventionalRank orders the entries that could be the thumbnail, lowest first - 0 if it cannot be
+func conventionalRank(entry string) int {
+	dir, file := path.Split(strings.TrimPrefix(entry, "/"))
+	depth := strings.Count(dir, "/")
+	if depth > 1 || strings.HasPrefix(dir, "__MACOSX/") {
+		return 0
+	}
+	ext := strings.ToLower(path.Ext(file))
+	if !conventionalExtensions[ext] {
+		return 0
+	}
+	stem := strings.ToLower(strings.TrimSuffix(file, path.Ext(file)))
+	for i, name := range conventionalNames {
+		if stem == name {
+			return i*2 + depth + 1
+		}
+	}
+	return 0
+}
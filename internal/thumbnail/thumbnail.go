@@ -0,0 +1,162 @@
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registered so that gifs can be decoded
+	"image/jpeg"
+	"image/png"
+	"sort"
+)
+
+const (
+	// MaxSize is the largest thumbnail image accepted, in bytes
+	MaxSize = 10 << 20
+	// MaxPixels stops an image that is small on disk but huge once decoded from being resized
+	MaxPixels = 40_000_000
+
+	jpegQuality = 85
+)
+
+var (
+	ErrUnsupported = errors.New("thumbnail should be a png, jpeg or gif image")
+	ErrTooLarge    = fmt.Errorf("thumbnail should be no larger than %d MB and %d megapixels", MaxSize>>20, MaxPixels/1_000_000)
+)
+
+// Variant is the thumbnail resized to one of the configured widths
+type Variant struct {
+	Width       int
+	Height      int
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+// Check reads just enough of the image to know it can be made into thumbnails
+func Check(b []byte) error {
+	_, _, err := decodeConfig(b)
+	return err
+}
+
+func decodeConfig(b []byte) (image.Config, string, error) {
+	if len(b) > MaxSize {
+		return image.Config{}, "", ErrTooLarge
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return image.Config{}, "", ErrUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return image.Config{}, "", ErrUnsupported
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return image.Config{}, "", ErrTooLarge
+	}
+	return cfg, format, nil
+}
+
+// Generate resizes the image to each of the widths, keeping its aspect ratio. Images are never enlarged - a
+// width wider than the image gives a copy at its own width. Jpegs stay jpegs, anything else becomes a png.
+func Generate(b []byte, widths []int) ([]*Variant, error) {
+	cfg, format, err := decodeConfig(b)
+	if err != nil {
+		return nil, err
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, err.Error())
+	}
+	src := image.NewRGBA(image.Rect(0, 0, cfg.Width, cfg.Height))
+	draw.Draw(src, src.Bounds(), decoded, decoded.Bounds().Min, draw.Src)
+
+	var variants []*Variant
+	for _, width := range variantWidths(widths, cfg.Width) {
+		height := (cfg.Height*width + cfg.Width/2) / cfg.Width
+		if height < 1 {
+			height = 1
+		}
+		v := &Variant{Width: width, Height: height}
+		if err = v.encode(resize(src, width, height), format); err != nil {
+			return nil, err
+		}
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// variantWidths are the widths asked for, no wider than the image, widest first and without repeats
+func variantWidths(widths []int, max int) []int {
+	seen := map[int]bool{}
+	var result []int
+	for _, w := range widths {
+		if w > max {
+			w = max
+		}
+		if w > 0 && !seen[w] {
+			seen[w] = true
+			result = append(result, w)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(result)))
+	return result
+}
+
+func (v *Variant) encode(img image.Image, format string) error {
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		v.ContentType, v.Extension = "image/jpeg", ".jpg"
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		v.ContentType, v.Extension = "image/png", ".png"
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot encode %d wide thumbnail %w", v.Width, err)
+	}
+	v.Data = buf.Bytes()
+	return nil
+}
+
+// resize scales the image down by averaging the block of pixels that falls under each new one
+func resize(src *image.RGBA, width, height int) *image.RGBA {
+	srcWidth, srcHeight := src.Rect.Dx(), src.Rect.Dy()
+	if width == srcWidth && height == srcHeight {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := span(y, height, srcHeight)
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width, srcWidth)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[src.PixOffset(x0, sy):src.PixOffset(x1, sy)]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (x1 - x0) * (y1 - y0)
+			off := dst.PixOffset(x, y)
+			for c := range sum {
+				dst.Pix[off+c] = uint8((sum[c] + n/2) / n)
+			}
+		}
+	}
+	return dst
+}
+
+// span is the range of source pixels that destination pixel i of n covers, always at least one
+func span(i, n, srcN int) (int, int) {
+	start, end := i*srcN/n, (i+1)*srcN/n
+	if end <= start {
+		end = start + 1
+	}
+	return start, end
+}
@@ -0,0 +1,142 @@
+package thumbnail_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/internal/thumbnail"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func encodePNG(w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// pngClaiming is a png whose header claims dimensions far larger than its data
+func pngClaiming(w, h int) []byte {
+	b := encodePNG(1, 1)
+	// the IHDR chunk follows the 8 byte signature: length, type, width, height, ... then a crc of type + data
+	binary.BigEndian.PutUint32(b[16:], uint32(w))
+	binary.BigEndian.PutUint32(b[20:], uint32(h))
+	binary.BigEndian.PutUint32(b[29:], crc32.ChecksumIEEE(b[12:29]))
+	return b
+}
+
+func TestGenerate(t *testing.T) {
+	Convey("Given a png 800 pixels wide", t, func() {
+		src := encodePNG(800, 400)
+
+		Convey("When thumbnails are generated", func() {
+			variants, err := thumbnail.Generate(src, []int{160, 640, 320})
+
+			Convey("Then there is a png for each width, widest first, keeping the aspect ratio", func() {
+				So(err, ShouldBeNil)
+				So(variants, ShouldHaveLength, 3)
+				for i, width := range []int{640, 320, 160} {
+					So(variants[i].Width, ShouldEqual, width)
+					So(variants[i].Height, ShouldEqual, width/2)
+					So(variants[i].ContentType, ShouldEqual, "image/png")
+					So(variants[i].Extension, ShouldEqual, ".png")
+
+					decoded, err := png.Decode(bytes.NewReader(variants[i].Data))
+					So(err, ShouldBeNil)
+					So(decoded.Bounds().Dx(), ShouldEqual, width)
+					So(decoded.Bounds().Dy(), ShouldEqual, width/2)
+				}
+			})
+		})
+
+		Convey("When a width is wider than the image", func() {
+			variants, err := thumbnail.Generate(src, []int{1280, 800, 320})
+
+			Convey("Then the image is not enlarged", func() {
+				So(err, ShouldBeNil)
+				So(variants, ShouldHaveLength, 2)
+				So(variants[0].Width, ShouldEqual, 800)
+				So(variants[0].Height, ShouldEqual, 400)
+				So(variants[1].Width, ShouldEqual, 320)
+			})
+		})
+	})
+
+	Convey("Given a jpeg", t, func() {
+		var buf bytes.Buffer
+		So(jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 300, 200)), nil), ShouldBeNil)
+
+		Convey("Then the thumbnails are jpegs", func() {
+			variants, err := thumbnail.Generate(buf.Bytes(), []int{150})
+			So(err, ShouldBeNil)
+			So(variants, ShouldHaveLength, 1)
+			So(variants[0].ContentType, ShouldEqual, "image/jpeg")
+			So(variants[0].Extension, ShouldEqual, ".jpg")
+			So(variants[0].Height, ShouldEqual, 100)
+		})
+	})
+}
+
+func TestCheck(t *testing.T) {
+	Convey("Given images that can and cannot be made into thumbnails", t, func() {
+		So(thumbnail.Check(encodePNG(10, 10)), ShouldBeNil)
+		So(thumbnail.Check([]byte("<svg></svg>")), ShouldEqual, thumbnail.ErrUnsupported)
+		So(thumbnail.Check(pngClaiming(10000, 10000)), ShouldEqual, thumbnail.ErrTooLarge)
+		So(thumbnail.Check(make([]byte, thumbnail.MaxSize+1)), ShouldEqual, thumbnail.ErrTooLarge)
+	})
+}
+
+func TestPicker(t *testing.T) {
+	img := encodePNG(2, 2)
+
+	Convey("Given an archive with no conventional image", t, func() {
+		p := &thumbnail.Picker{}
+		p.Scan("index.html", []byte("<html></html>"))
+		p.Scan("site/images/thumbnail.png", img)
+		p.Scan("logo.png", img)
+
+		Convey("Then no thumbnail is picked", func() {
+			So(p.Image(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given an archive with a preview and a thumbnail", t, func() {
+		p := &thumbnail.Picker{}
+		p.Scan("interactive/preview.jpg", []byte("preview"))
+		p.Scan("interactive/Thumbnail.PNG", img)
+		p.Scan("__MACOSX/thumbnail.png", []byte("resource fork"))
+
+		Convey("Then the thumbnail is picked", func() {
+			So(p.Image(), ShouldResemble, img)
+		})
+	})
+
+	Convey("Given a picker", t, func() {
+		p := &thumbnail.Picker{}
+
+		Convey("Then it only wants entries that could be a better thumbnail than the one it has", func() {
+			So(p.Wants("index.html"), ShouldBeFalse)
+			So(p.Wants("site/images/thumbnail.png"), ShouldBeFalse)
+			So(p.Wants("preview.png"), ShouldBeTrue)
+			p.Scan("thumbnail.png", img)
+			So(p.Wants("preview.png"), ShouldBeFalse)
+			So(p.Wants("thumbnail.jpg"), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a nil picker", t, func() {
+		var p *thumbnail.Picker
+		So(p.Image(), ShouldBeNil)
+	})
+}
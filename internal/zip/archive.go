@@ -0,0 +1,184 @@
+package zip
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a kind of upload the api accepts
+type Format int
+
+const (
+	FormatZip Format = iota
+	FormatTar
+	FormatTarGz
+	FormatHTML
+)
+
+// HTMLEntry is the name a bare html upload is given inside its archive
+const HTMLEntry = "index.html"
+
+var (
+	ErrUnsupportedFormat = errors.New("file should be a zip, tar, tar.gz or html file")
+
+	// longest suffix first so that .tar.gz is not taken for .gz
+	extensions = []struct {
+		ext    string
+		format Format
+	}{
+		{".tar.gz", FormatTarGz},
+		{".tgz", FormatTarGz},
+		{".tar", FormatTar},
+		{".zip", FormatZip},
+		{".html", FormatHTML},
+		{".htm", FormatHTML},
+	}
+)
+
+// DetectFormat works out the format of an upload from its file name
+func DetectFormat(filename string) (Format, error) {
+	lower := strings.ToLower(filename)
+	for _, e := range extensions {
+		if strings.HasSuffix(lower, e.ext) {
+			return e.format, nil
+		}
+	}
+	return 0, ErrUnsupportedFormat
+}
+
+// NormalisedName is the file name of the zip an upload is normalised to
+func NormalisedName(filename string) string {
+	lower := strings.ToLower(filename)
+	for _, e := range extensions {
+		if strings.HasSuffix(lower, e.ext) {
+			return filename[:len(filename)-len(e.ext)] + ".zip"
+		}
+	}
+	return filename + ".zip"
+}
+
+// Normalise converts the upload at name into a zip, which is what the rest of the pipeline (validation,
+// content scanning and the importer) understands. Zips are returned as they are, anything else is written
+// to a new temporary file which the caller must remove. Entry count and size limits are applied while
+// converting so that a compressed tar cannot fill the disk - the zip is validated in full by Open.
+func Normalise(name string, format Format, limits Limits) (string, error) {
+	if format == FormatZip {
+		return name, nil
+	}
+
+	in, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(name), "normalised_*.zip")
+	if err != nil {
+		return "", err
+	}
+	w := zip.NewWriter(out)
+
+	switch format {
+	case FormatHTML:
+		err = writeHTML(w, in)
+	case FormatTar:
+		err = writeTar(w, in, limits)
+	case FormatTarGz:
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(in); err == nil {
+			err = writeTar(w, gz, limits)
+			gz.Close()
+		}
+	default:
+		err = ErrUnsupportedFormat
+	}
+
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
+
+func writeHTML(w *zip.Writer, in io.Reader) error {
+	entry, err := w.CreateHeader(&zip.FileHeader{Name: HTMLEntry, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(entry, in)
+	return err
+}
+
+// writeTar copies regular files from the tar into the zip. Links and special files are refused here
+// as the zip cannot represent them for Validate to reject.
+func writeTar(w *zip.Writer, in io.Reader, limits Limits) error {
+	var errs []*EntryError
+	fail := func(entry, reason string, args ...interface{}) {
+		errs = append(errs, &EntryError{Entry: entry, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	tr := tar.NewReader(in)
+	var entries int
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read tar %w", err)
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+		case tar.TypeDir, tar.TypeXGlobalHeader:
+			continue
+		case tar.TypeSymlink, tar.TypeLink:
+			fail(hdr.Name, "symlinks are not allowed")
+			continue
+		default:
+			fail(hdr.Name, "unsupported entry type")
+			continue
+		}
+
+		entries++
+		if limits.MaxEntries > 0 && entries > limits.MaxEntries {
+			fail("", "archive has more than %d entries", limits.MaxEntries)
+			break
+		}
+		total += hdr.Size
+		if limits.MaxUncompressedSize > 0 && total > limits.MaxUncompressedSize {
+			fail("", "uncompressed size exceeds the limit of %d bytes", limits.MaxUncompressedSize)
+			break
+		}
+
+		// tools commonly prefix entries with ./ - unsafe paths are kept as they are for Validate
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "./")
+		entry, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: hdr.ModTime})
+		if err != nil {
+			return err
+		}
+		if _, err = io.Copy(entry, tr); err != nil {
+			return fmt.Errorf("cannot read %s %w", hdr.Name, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Entries: errs}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package zip_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	zip2 "github.com/ONSdigital/dp-interactives-api/internal/zip"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	body     string
+}
+
+func writeTarGz(t *testing.T, entries ...tarEntry) string {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Mode: 0644, Size: int64(len(e.body))}
+		if e.typeflag != tar.TypeReg {
+			hdr.Size = 0
+			hdr.Linkname = "/etc/passwd"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			tw.Write([]byte(e.body))
+		}
+	}
+	tw.Close()
+	gz.Close()
+
+	name := filepath.Join(t.TempDir(), "upload.tar.gz")
+	if err := os.WriteFile(name, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func zipEntries(name string) []string {
+	r, err := zip.OpenReader(name)
+	So(err, ShouldBeNil)
+	defer r.Close()
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestDetectFormat(t *testing.T) {
+	Convey("Upload formats are detected from the file name", t, func() {
+		for name, expected := range map[string]zip2.Format{
+			"a.zip":     zip2.FormatZip,
+			"a.tar":     zip2.FormatTar,
+			"a.TAR.GZ":  zip2.FormatTarGz,
+			"a.tgz":     zip2.FormatTarGz,
+			"index.htm": zip2.FormatHTML,
+		} {
+			format, err := zip2.DetectFormat(name)
+			So(err, ShouldBeNil)
+			So(format, ShouldEqual, expected)
+		}
+
+		_, err := zip2.DetectFormat("a.gz")
+		So(err, ShouldEqual, zip2.ErrUnsupportedFormat)
+		So(zip2.NormalisedName("chart.tar.gz"), ShouldEqual, "chart.zip")
+		So(zip2.NormalisedName("index.html"), ShouldEqual, "index.zip")
+	})
+}
+
+func TestNormalise(t *testing.T) {
+	Convey("Given a tar.gz interactive", t, func() {
+		name := writeTarGz(t,
+			tarEntry{name: "./", typeflag: tar.TypeDir},
+			tarEntry{name: "./index.html", typeflag: tar.TypeReg, body: "<html></html>"},
+			tarEntry{name: "./js/app.js", typeflag: tar.TypeReg, body: "var a = 1;"},
+		)
+
+		Convey("Then it is converted to a zip that opens like any other", func() {
+			normalised, err := zip2.Normalise(name, zip2.FormatTarGz, zip2.Limits{})
+			So(err, ShouldBeNil)
+			defer os.Remove(normalised)
+			So(zipEntries(normalised), ShouldResemble, []string{"index.html", "js/app.js"})
+
+			c, err := zip2.Open(normalised, zip2.Limits{})
+			So(err, ShouldBeNil)
+			So(c.HTMLFiles, ShouldHaveLength, 1)
+		})
+
+		Convey("Then the entry limit is applied while converting", func() {
+			_, err := zip2.Normalise(name, zip2.FormatTarGz, zip2.Limits{MaxEntries: 1})
+			So(err, ShouldHaveSameTypeAs, &zip2.ValidationError{})
+		})
+	})
+
+	Convey("Given a tar.gz with a symlink", t, func() {
+		name := writeTarGz(t,
+			tarEntry{name: "index.html", typeflag: tar.TypeReg, body: "<html></html>"},
+			tarEntry{name: "passwd", typeflag: tar.TypeSymlink},
+		)
+
+		Convey("Then the link is refused while converting", func() {
+			_, err := zip2.Normalise(name, zip2.FormatTarGz, zip2.Limits{})
+			So(err, ShouldNotBeNil)
+			validationErr, ok := err.(*zip2.ValidationError)
+			So(ok, ShouldBeTrue)
+			So(validationErr.Entries, ShouldHaveLength, 1)
+			So(validationErr.Entries[0].Entry, ShouldEqual, "passwd")
+		})
+	})
+
+	Convey("Given a tar.gz with a traversal", t, func() {
+		name := writeTarGz(t,
+			tarEntry{name: "index.html", typeflag: tar.TypeReg, body: "<html></html>"},
+			tarEntry{name: "../../evil.html", typeflag: tar.TypeReg, body: "evil"},
+		)
+
+		Convey("Then the path is kept for validation to reject", func() {
+			normalised, err := zip2.Normalise(name, zip2.FormatTarGz, zip2.Limits{})
+			So(err, ShouldBeNil)
+			defer os.Remove(normalised)
+
+			_, err = zip2.Open(normalised, zip2.Limits{})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "path traversal")
+		})
+	})
+
+	Convey("Given a single html file", t, func() {
+		name := filepath.Join(t.TempDir(), "chart.html")
+		So(os.WriteFile(name, []byte("<html><body>chart</body></html>"), 0600), ShouldBeNil)
+
+		Convey("Then it is wrapped as the index of a zip", func() {
+			normalised, err := zip2.Normalise(name, zip2.FormatHTML, zip2.Limits{})
+			So(err, ShouldBeNil)
+			defer os.Remove(normalised)
+			So(zipEntries(normalised), ShouldResemble, []string{zip2.HTMLEntry})
+		})
+	})
+
+	Convey("Given a zip", t, func() {
+		Convey("Then it is used as it is", func() {
+			normalised, err := zip2.Normalise("any.zip", zip2.FormatZip, zip2.Limits{})
+			So(err, ShouldBeNil)
+			So(normalised, ShouldEqual, "any.zip")
+		})
+	})
+}
@@ -0,0 +1,208 @@
+package zip
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+)
+
+const (
+	RuleExternalScript    = "external-script"
+	RuleEval              = "eval"
+	RuleTrackingPixel     = "tracking-pixel"
+	RuleMixedContent      = "mixed-content"
+	RuleForbiddenFileType = "forbidden-file-type"
+
+	// entries bigger than this are only partly scanned
+	maxScannedEntrySize = 20 << 20
+)
+
+// DefaultForbiddenFileTypes are extensions never served as part of an interactive
+var DefaultForbiddenFileTypes = []string{".exe", ".dll", ".com", ".bat", ".cmd", ".msi", ".scr", ".ps1", ".vbs", ".sh", ".jar", ".apk", ".dmg", ".app"}
+
+// mediaFileTypes are the binary assets (images, fonts, audio, video and documents) an interactive ships.
+// The content policy has no rules for them, so they are not read.
+var mediaFileTypes = []string{
+	".png", ".jpg", ".jpeg", ".gif", ".webp", ".avif", ".bmp", ".ico", ".tif", ".tiff",
+	".woff", ".woff2", ".ttf", ".otf", ".eot",
+	".mp3", ".wav", ".ogg", ".mp4", ".webm", ".mov",
+	".pdf",
+}
+
+// ContentScanner checks a single archive entry against a content policy. The scanners used for an
+// upload are run in turn, so further checks can be added without touching the existing ones. An entry
+// is only read (and held in memory) for the scanners that want it.
+type ContentScanner interface {
+	Wants(entry string) bool
+	Scan(entry string, content []byte) []*models.ContentFinding
+}
+
+// wanting are the scanners that want the entry
+func wanting(entry string, scanners []ContentScanner) []ContentScanner {
+	var wanted []ContentScanner
+	for _, s := range scanners {
+		if s.Wants(entry) {
+			wanted = append(wanted, s)
+		}
+	}
+	return wanted
+}
+
+// ContentPolicy is the built-in ContentScanner for html, js and css entries
+type ContentPolicy struct {
+	// AllowedScriptOrigins are hosts external scripts may be loaded from - a leading "*." allows subdomains
+	AllowedScriptOrigins []string
+	// ForbiddenFileTypes are extensions (with the dot) that may not appear in an archive at all
+	ForbiddenFileTypes []string
+}
+
+var (
+	scriptSrcRegex    = regexp.MustCompile(`(?is)<script\b[^>]*?\bsrc\s*=\s*["']?([^"'\s>]+)`)
+	evalRegex         = regexp.MustCompile(`\beval\s*\(|\bnew\s+Function\s*\(`)
+	imgRegex          = regexp.MustCompile(`(?is)<img\b[^>]*>`)
+	pixelWidthRegex   = regexp.MustCompile(`(?i)\bwidth\s*(=\s*["']?|:\s*)[01](px)?\b`)
+	pixelHeightRegex  = regexp.MustCompile(`(?i)\bheight\s*(=\s*["']?|:\s*)[01](px)?\b`)
+	htmlInsecureRegex = regexp.MustCompile(`(?i)\b(?:src|href|action|data)\s*=\s*["']?(http://[^"'\s>]+)`)
+	cssInsecureRegex  = regexp.MustCompile(`(?i)url\(\s*["']?(http://[^"')\s]+)`)
+	jsInsecureRegex   = regexp.MustCompile(`["'](http://[^"'\s]+)`)
+)
+
+// Wants the entries there are rules for: html, js and css, those of a forbidden type, and those without a
+// known media type, which may be executables under another name
+func (p *ContentPolicy) Wants(entry string) bool {
+	ext := strings.ToLower(path.Ext(entry))
+	for _, forbidden := range p.ForbiddenFileTypes {
+		if ext == strings.ToLower(forbidden) {
+			return true
+		}
+	}
+	for _, media := range mediaFileTypes {
+		if ext == media {
+			return false
+		}
+	}
+	return true
+}
+
+// Scan runs every rule that applies to the entry's file type
+func (p *ContentPolicy) Scan(entry string, content []byte) []*models.ContentFinding {
+	var findings []*models.ContentFinding
+	add := func(rule, detail string) {
+		findings = append(findings, &models.ContentFinding{Entry: entry, Rule: rule, Detail: detail})
+	}
+
+	ext := strings.ToLower(path.Ext(entry))
+	for _, forbidden := range p.ForbiddenFileTypes {
+		if ext == strings.ToLower(forbidden) {
+			add(RuleForbiddenFileType, ext)
+			return findings
+		}
+	}
+	if bytes.HasPrefix(content, []byte("MZ")) || bytes.HasPrefix(content, []byte("\x7fELF")) {
+		add(RuleForbiddenFileType, "executable")
+		return findings
+	}
+
+	switch ext {
+	case ".html", ".htm":
+		for _, m := range scriptSrcRegex.FindAllSubmatch(content, -1) {
+			if origin, ok := p.externalOrigin(string(m[1])); !ok {
+				add(RuleExternalScript, origin)
+			}
+		}
+		for _, img := range imgRegex.FindAll(content, -1) {
+			if pixelWidthRegex.Match(img) && pixelHeightRegex.Match(img) {
+				add(RuleTrackingPixel, string(img))
+			}
+		}
+		p.scanInsecure(content, htmlInsecureRegex, add)
+		p.scanInsecure(content, cssInsecureRegex, add)
+		if evalRegex.Match(content) {
+			add(RuleEval, "inline script")
+		}
+	case ".js", ".mjs":
+		p.scanInsecure(content, jsInsecureRegex, add)
+		if evalRegex.Match(content) {
+			add(RuleEval, "script")
+		}
+	case ".css":
+		p.scanInsecure(content, cssInsecureRegex, add)
+	}
+	return findings
+}
+
+// externalOrigin returns the host of an absolute script url, and whether it may be loaded
+func (p *ContentPolicy) externalOrigin(src string) (string, bool) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return src, false
+	}
+	if u.Host == "" {
+		// relative to the interactive itself
+		return "", true
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range p.AllowedScriptOrigins {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return host, true
+		}
+	}
+	return host, false
+}
+
+func (p *ContentPolicy) scanInsecure(content []byte, re *regexp.Regexp, add func(rule, detail string)) {
+	for _, m := range re.FindAllSubmatch(content, -1) {
+		// xml namespaces (e.g. svg in d3) are identifiers rather than requests
+		if link := string(m[1]); !strings.HasPrefix(link, "http://www.w3.org/") {
+			add(RuleMixedContent, link)
+		}
+	}
+}
+
+// ScanContent runs the scanners over the entries they want in the named archive
+func ScanContent(name string, scanners ...ContentScanner) ([]*models.ContentFinding, error) {
+	if len(scanners) == 0 {
+		return nil, nil
+	}
+	zipReader, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	var findings []*models.ContentFinding
+	for _, f := range zipReader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		wanted := wanting(f.Name, scanners)
+		if len(wanted) == 0 {
+			continue
+		}
+		content, err := readEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s %w", f.Name, err)
+		}
+		for _, s := range wanted {
+			findings = append(findings, s.Scan(f.Name, content)...)
+		}
+	}
+	return findings, nil
+}
+
+func readEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, maxScannedEntrySize))
+}
@@ -0,0 +1,166 @@
+package zip_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	zip2 "github.com/ONSdigital/dp-interactives-api/internal/zip"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestContentPolicy(t *testing.T) {
+	policy := &zip2.ContentPolicy{
+		AllowedScriptOrigins: []string{"cdn.ons.gov.uk", "*.jsdelivr.net"},
+		ForbiddenFileTypes:   zip2.DefaultForbiddenFileTypes,
+	}
+	rules := func(findings []*models.ContentFinding) []string {
+		var r []string
+		for _, f := range findings {
+			r = append(r, f.Rule)
+		}
+		return r
+	}
+
+	Convey("Given html loading scripts from allowed, relative and unknown origins", t, func() {
+		findings := policy.Scan("index.html", []byte(`<html>
+<script src="js/app.js"></script>
+<script src="https://cdn.ons.gov.uk/lib.js"></script>
+<script type="text/javascript" src="https://fastly.jsdelivr.net/npm/d3"></script>
+<script src='//tracker.example.com/t.js'></script>
+</html>`))
+
+		Convey("Then only the unknown origin is flagged", func() {
+			So(findings, ShouldHaveLength, 1)
+			So(findings[0].Rule, ShouldEqual, zip2.RuleExternalScript)
+			So(findings[0].Detail, ShouldEqual, "tracker.example.com")
+			So(findings[0].Entry, ShouldEqual, "index.html")
+		})
+	})
+
+	Convey("Given html with an inline eval, a tracking pixel and insecure links", t, func() {
+		findings := policy.Scan("page.htm", []byte(`<html>
+<svg xmlns="http://www.w3.org/2000/svg"></svg>
+<img src="chart.png" width="600" height="400">
+<img src="https://pixel.example.com/p.gif" width="1" height="1">
+<a href="http://www.ons.gov.uk">ons</a>
+<div style="background: url('http://example.com/bg.png')"></div>
+<script>eval(data)</script>
+</html>`))
+
+		Convey("Then each is flagged", func() {
+			So(rules(findings), ShouldResemble, []string{zip2.RuleTrackingPixel, zip2.RuleMixedContent, zip2.RuleMixedContent, zip2.RuleEval})
+			So(findings[1].Detail, ShouldEqual, "http://www.ons.gov.uk")
+			So(findings[2].Detail, ShouldEqual, "http://example.com/bg.png")
+		})
+	})
+
+	Convey("Given javascript", t, func() {
+		Convey("Then eval, new Function and insecure urls are flagged", func() {
+			findings := policy.Scan("js/app.js", []byte(`var f = new Function("return 1"); fetch("http://api.example.com/data");`))
+			So(rules(findings), ShouldResemble, []string{zip2.RuleMixedContent, zip2.RuleEval})
+		})
+
+		Convey("Then svg namespaces and evaluate() are not", func() {
+			findings := policy.Scan("js/d3.js", []byte(`d3.namespaces.svg = "http://www.w3.org/2000/svg"; chart.evaluate(x);`))
+			So(findings, ShouldBeEmpty)
+		})
+	})
+
+	Convey("Given forbidden file types", t, func() {
+		Convey("Then they are flagged by extension", func() {
+			findings := policy.Scan("tools/setup.EXE", []byte("anything"))
+			So(rules(findings), ShouldResemble, []string{zip2.RuleForbiddenFileType})
+		})
+
+		Convey("Then executables are flagged whatever they are called", func() {
+			findings := policy.Scan("data/chart.json", []byte("\x7fELF\x02\x01"))
+			So(rules(findings), ShouldResemble, []string{zip2.RuleForbiddenFileType})
+		})
+	})
+
+	Convey("Given entries of each kind", t, func() {
+		Convey("Then the policy wants those it has rules for", func() {
+			So(policy.Wants("index.html"), ShouldBeTrue)
+			So(policy.Wants("js/app.mjs"), ShouldBeTrue)
+			So(policy.Wants("css/site.css"), ShouldBeTrue)
+			So(policy.Wants("tools/setup.EXE"), ShouldBeTrue)
+			So(policy.Wants("data/chart.json"), ShouldBeTrue)
+			So(policy.Wants("bin/tool"), ShouldBeTrue)
+		})
+
+		Convey("Then it does not want binary media", func() {
+			So(policy.Wants("img/chart.png"), ShouldBeFalse)
+			So(policy.Wants("fonts/ons.WOFF2"), ShouldBeFalse)
+			So(policy.Wants("video/intro.mp4"), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an archive with binary media", t, func() {
+		// each entry holds its own name, so both look like windows executables when read
+		archiveName, _, err := createTestZip("index.html", "MZchart.png", "MZtool")
+		So(err, ShouldBeNil)
+		defer os.Remove(archiveName)
+
+		Convey("Then the media is not read", func() {
+			findings, err := zip2.ScanContent(archiveName, policy)
+			So(err, ShouldBeNil)
+			So(findings, ShouldHaveLength, 1)
+			So(findings[0].Entry, ShouldEqual, "MZtool")
+			So(findings[0].Detail, ShouldEqual, "executable")
+		})
+	})
+
+	Convey("Given an archive", t, func() {
+		archiveName, _, err := createTestZip("index.html", "js/app.js")
+		So(err, ShouldBeNil)
+		defer os.Remove(archiveName)
+
+		Convey("Then every entry is passed to the scanners", func() {
+			findings, err := zip2.ScanContent(archiveName, policy)
+			So(err, ShouldBeNil)
+			So(findings, ShouldBeEmpty)
+
+			var scanned []string
+			_, err = zip2.ScanContent(archiveName, scannerFunc(func(entry string, content []byte) []*models.ContentFinding {
+				scanned = append(scanned, entry)
+				return nil
+			}))
+			So(err, ShouldBeNil)
+			So(scanned, ShouldResemble, []string{"index.html", "js/app.js"})
+		})
+
+		Convey("Then entries are only passed to the scanners that want them", func() {
+			scripts := &pickyScanner{wants: ".js"}
+			_, err := zip2.ScanContent(archiveName, scripts)
+			So(err, ShouldBeNil)
+			So(scripts.scanned, ShouldResemble, []string{"js/app.js"})
+		})
+	})
+}
+
+type scannerFunc func(entry string, content []byte) []*models.ContentFinding
+
+func (f scannerFunc) Wants(string) bool {
+	return true
+}
+
+func (f scannerFunc) Scan(entry string, content []byte) []*models.ContentFinding {
+	return f(entry, content)
+}
+
+// pickyScanner only wants entries with one extension
+type pickyScanner struct {
+	wants   string
+	scanned []string
+}
+
+func (p *pickyScanner) Wants(entry string) bool {
+	return path.Ext(entry) == p.wants
+}
+
+func (p *pickyScanner) Scan(entry string, content []byte) []*models.ContentFinding {
+	p.scanned = append(p.scanned, entry)
+	return nil
+}
@@ -0,0 +1,631 @@
+package zip
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+)
+
+// Signatures and flags from the zip specification (APPNOTE.TXT)
+const (
+	localHeaderSig    = 0x04034b50
+	dataDescriptorSig = 0x08074b50
+	centralHeaderSig  = 0x02014b50
+	zip64EndSig       = 0x06064b50
+	zip64LocatorSig   = 0x07064b50
+	endSig            = 0x06054b50
+
+	dataDescriptorFlag = 0x8
+	zip64ExtraID       = 0x0001
+	uint16Max          = 0xffff
+	uint32Max          = 0xffffffff
+)
+
+var errStreamClosed = errors.New("stream closed")
+
+// Verifier is called with what was found in a streamed archive once it has been read in full.
+// Returning an error stops the stream completing.
+type Verifier func(contents *Contents, findings []*models.ContentFinding) error
+
+// Stream validates a zip as it is read, so that an upload can be passed straight on (e.g. to s3) without being
+// written to disk first. Entries are checked and decompressed from their local headers as the bytes go by, then
+// the central directory - which is what everything downstream reads - must agree with them exactly. Any problem
+// is returned from Read in place of io.EOF, so whatever is consuming the stream never sees the archive complete.
+//
+// Memory use is bounded by the entry limit (a few fields are kept per entry until the central directory is
+// checked) and the part of each entry given to the content scanners.
+type Stream struct {
+	src    io.Reader
+	pw     *io.PipeWriter
+	parser *streamParser
+	done   chan struct{}
+	verify Verifier
+	hash   hash.Hash
+	size   int64
+
+	ended    bool
+	err      error
+	contents *Contents
+}
+
+// NewStream starts validating the zip read from r. Close must be called if the stream is not read to the end.
+func NewStream(r io.Reader, limits Limits, scanners []ContentScanner, verify Verifier) *Stream {
+	pr, pw := io.Pipe()
+	s := &Stream{
+		src:    r,
+		pw:     pw,
+		parser: newStreamParser(pr, limits, scanners),
+		done:   make(chan struct{}),
+		verify: verify,
+		hash:   sha256.New(),
+	}
+
+	go func() {
+		defer close(s.done)
+		s.parser.err = s.parser.parse()
+		// nothing more is read once the archive has ended or been found invalid
+		pr.CloseWithError(s.parser.err)
+	}()
+	return s
+}
+
+func (s *Stream) Read(p []byte) (int, error) {
+	if s.ended {
+		return 0, s.result()
+	}
+
+	n, err := s.src.Read(p)
+	if n > 0 {
+		s.hash.Write(p[:n])
+		s.size += int64(n)
+		if _, wErr := s.pw.Write(p[:n]); wErr != nil {
+			// the parser has stopped early, so the archive is invalid
+			return n, s.finish(nil)
+		}
+	}
+
+	switch {
+	case err == io.EOF:
+		s.pw.Close()
+		return n, s.finish(nil)
+	case err != nil:
+		s.pw.CloseWithError(err)
+		return n, s.finish(err)
+	}
+	return n, nil
+}
+
+// Close stops validation, which is abandoned if the archive has not been read in full
+func (s *Stream) Close() error {
+	s.pw.CloseWithError(errStreamClosed)
+	<-s.done
+	return nil
+}
+
+// Err is why the stream failed, once it has ended - nil if it completed
+func (s *Stream) Err() error {
+	if !s.ended {
+		return nil
+	}
+	return s.err
+}
+
+// Contents is what was found in the archive, once it has been read in full and passed validation
+func (s *Stream) Contents() *Contents {
+	return s.contents
+}
+
+func (s *Stream) finish(readErr error) error {
+	<-s.done
+	s.ended = true
+
+	switch {
+	case readErr != nil:
+		s.err = readErr
+	case s.parser.err != nil:
+		s.err = s.parser.err
+	default:
+		s.contents = s.parser.contents(s.size, hex.EncodeToString(s.hash.Sum(nil)))
+		if s.verify != nil {
+			s.err = s.verify(s.contents, s.parser.findings)
+		}
+		if s.err != nil {
+			s.contents = nil
+		}
+	}
+	return s.result()
+}
+
+func (s *Stream) result() error {
+	if s.err != nil {
+		return s.err
+	}
+	return io.EOF
+}
+
+// streamEntry is what is kept of a local header to check the central directory against
+type streamEntry struct {
+	name         string
+	encrypted    bool
+	crc32        uint32
+	compressed   uint64
+	uncompressed uint64
+	listed       bool
+}
+
+type streamParser struct {
+	r        *countingReader
+	limits   Limits
+	scanners []ContentScanner
+
+	errs      []*EntryError
+	total     uint64
+	local     map[int64]*streamEntry
+	order     []*streamEntry
+	central   int
+	files     []*models.ArchiveFile
+	htmlFiles []*models.HTMLFile
+	findings  []*models.ContentFinding
+	err       error
+}
+
+func newStreamParser(r io.Reader, limits Limits, scanners []ContentScanner) *streamParser {
+	return &streamParser{
+		r:        &countingReader{r: bufio.NewReader(r)},
+		limits:   limits,
+		scanners: scanners,
+		local:    map[int64]*streamEntry{},
+	}
+}
+
+func (p *streamParser) contents(size int64, sha string) *Contents {
+	return &Contents{
+		Archive:   &models.Archive{Size: size},
+		HTMLFiles: p.htmlFiles,
+		Manifest:  &models.Manifest{SHA: sha, Files: p.files},
+	}
+}
+
+func (p *streamParser) fail(entry, reason string, args ...interface{}) {
+	p.errs = append(p.errs, &EntryError{Entry: entry, Reason: fmt.Sprintf(reason, args...)})
+}
+
+// fatal records a problem that stops the archive being read any further
+func (p *streamParser) fatal(entry, reason string, args ...interface{}) error {
+	p.fail(entry, reason, args...)
+	return &ValidationError{Entries: p.errs}
+}
+
+// readErr reports an archive that ends too soon as invalid - anything else is a problem reading the upload
+func (p *streamParser) readErr(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return p.fatal("", "archive is truncated")
+	}
+	return err
+}
+
+func (p *streamParser) parse() error {
+	inCentralDirectory := false
+	for {
+		b, err := p.read(4)
+		if err != nil {
+			return p.readErr(err)
+		}
+
+		switch sig := binary.LittleEndian.Uint32(b); {
+		case sig == localHeaderSig && !inCentralDirectory:
+			err = p.localEntry()
+		case sig == centralHeaderSig:
+			inCentralDirectory = true
+			err = p.centralEntry()
+		case sig == zip64EndSig:
+			if b, err = p.read(8); err == nil {
+				err = p.skip(binary.LittleEndian.Uint64(b))
+			}
+		case sig == zip64LocatorSig:
+			err = p.skip(16)
+		case sig == endSig:
+			return p.end()
+		default:
+			return p.fatal("", "not a zip archive, or unexpected data at offset %d", p.r.n-4)
+		}
+		if err != nil {
+			return p.readErr(err)
+		}
+	}
+}
+
+func (p *streamParser) localEntry() error {
+	offset := p.r.n - 4
+	b, err := p.read(26)
+	if err != nil {
+		return err
+	}
+	flags := binary.LittleEndian.Uint16(b[2:])
+	method := binary.LittleEndian.Uint16(b[4:])
+	crc := binary.LittleEndian.Uint32(b[10:])
+	compressed := uint64(binary.LittleEndian.Uint32(b[14:]))
+	uncompressed := uint64(binary.LittleEndian.Uint32(b[18:]))
+	nameLen := binary.LittleEndian.Uint16(b[22:])
+	extraLen := binary.LittleEndian.Uint16(b[24:])
+
+	nameBytes, err := p.read(int(nameLen))
+	if err != nil {
+		return err
+	}
+	extra, err := p.read(int(extraLen))
+	if err != nil {
+		return err
+	}
+	name := string(nameBytes)
+
+	// a zip64 local header gives both sizes in its extra field
+	zip64 := compressed == uint32Max || uncompressed == uint32Max
+	if zip64 {
+		sizes := zip64Extra(extra)
+		if len(sizes) < 2 {
+			return p.fatal(name, "zip64 sizes are missing")
+		}
+		uncompressed, compressed = sizes[0], sizes[1]
+	}
+
+	if p.limits.MaxEntries > 0 && len(p.order) >= p.limits.MaxEntries {
+		return p.fatal("", "archive has more than %d entries", p.limits.MaxEntries)
+	}
+	if reason := unsafePath(name); reason != "" {
+		p.fail(name, reason)
+	}
+
+	entry := &streamEntry{name: name, encrypted: flags&encryptedFlag != 0}
+	p.local[offset] = entry
+	p.order = append(p.order, entry)
+
+	descriptor := flags&dataDescriptorFlag != 0
+	if entry.encrypted || (method != zip.Store && method != zip.Deflate) {
+		if entry.encrypted {
+			p.fail(name, "encrypted entries are not allowed")
+		} else {
+			p.fail(name, "unsupported compression method %d", method)
+		}
+		if descriptor {
+			return p.fatal(name, "entry size is unknown so the rest of the archive cannot be read")
+		}
+		entry.crc32, entry.compressed, entry.uncompressed = crc, compressed, uncompressed
+		return p.skip(compressed)
+	}
+	if descriptor && method == zip.Store {
+		return p.fatal(name, "uncompressed entries must give their size up front")
+	}
+
+	start := p.r.n
+	var data io.Reader = p.r
+	if !descriptor {
+		data = io.LimitReader(p.r, int64(compressed))
+	}
+	if method == zip.Deflate {
+		fr := flate.NewReader(data)
+		defer fr.Close()
+		data = fr
+	}
+
+	// never decompress more than the header or the size limit allows
+	limit := uint64(1 << 62)
+	if !descriptor {
+		limit = uncompressed
+	}
+	sizeLimited := false
+	if max := p.limits.MaxUncompressedSize; max > 0 && uint64(max)-p.total < limit {
+		if !descriptor {
+			return p.fatal("", "uncompressed size exceeds the limit of %d bytes", max)
+		}
+		limit, sizeLimited = uint64(max)-p.total, true
+	}
+	read, sum, err := p.readEntry(name, io.LimitReader(data, int64(limit)+1))
+	if err != nil {
+		return p.fatal(name, "entry cannot be decompressed")
+	}
+	if read > limit {
+		if sizeLimited {
+			return p.fatal("", "uncompressed size exceeds the limit of %d bytes", p.limits.MaxUncompressedSize)
+		}
+		return p.fatal(name, "entry is larger than its header says")
+	}
+	p.total += read
+
+	if descriptor {
+		compressed = uint64(p.r.n - start)
+		if crc, err = p.dataDescriptor(entry, compressed, read, zip64); err != nil {
+			return err
+		}
+	} else if uint64(p.r.n-start) != compressed || read != uncompressed {
+		return p.fatal(name, "entry does not match its header")
+	}
+	if crc != sum {
+		return p.fatal(name, "checksum does not match")
+	}
+	entry.crc32, entry.compressed, entry.uncompressed = crc, compressed, read
+
+	if p.limits.MaxCompressionRatio > 0 && read > 0 {
+		if compressed == 0 || float64(read)/float64(compressed) > p.limits.MaxCompressionRatio {
+			p.fail(name, "compression ratio exceeds %g", p.limits.MaxCompressionRatio)
+		}
+	}
+	return nil
+}
+
+// dataDescriptor reads the sizes and checksum that follow an entry written without them, checking the sizes
+// against what was read. Sizes are 8 bytes for zip64 entries, which not every writer flags in the local header.
+func (p *streamParser) dataDescriptor(entry *streamEntry, compressed, uncompressed uint64, zip64 bool) (uint32, error) {
+	b, err := p.read(4)
+	if err != nil {
+		return 0, err
+	}
+	// the signature is optional
+	if binary.LittleEndian.Uint32(b) == dataDescriptorSig {
+		if b, err = p.read(4); err != nil {
+			return 0, err
+		}
+	}
+	crc := binary.LittleEndian.Uint32(b)
+
+	var c, u uint64
+	if zip64 || compressed >= uint32Max || uncompressed >= uint32Max {
+		if b, err = p.read(16); err != nil {
+			return 0, err
+		}
+		c, u = binary.LittleEndian.Uint64(b), binary.LittleEndian.Uint64(b[8:])
+	} else {
+		if b, err = p.read(8); err != nil {
+			return 0, err
+		}
+		c, u = uint64(binary.LittleEndian.Uint32(b)), uint64(binary.LittleEndian.Uint32(b[4:]))
+	}
+	if c != compressed || u != uncompressed {
+		return 0, p.fatal(entry.name, "entry does not match its data descriptor")
+	}
+	return crc, nil
+}
+
+// readEntry decompresses an entry, adding it to the manifest and running the content scanners that want it over it
+func (p *streamParser) readEntry(name string, r io.Reader) (uint64, uint32, error) {
+	isDir := strings.HasSuffix(name, "/")
+	sha := sha256.New()
+	crc := crc32.NewIEEE()
+	var scanned bytes.Buffer
+
+	var scanners []ContentScanner
+	if !isDir {
+		scanners = wanting(name, p.scanners)
+	}
+	w := io.MultiWriter(sha, crc)
+	if len(scanners) > 0 {
+		w = io.MultiWriter(sha, crc, &limitedWriter{w: &scanned, n: maxScannedEntrySize})
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, 0, err
+	}
+	w.Write(head[:n])
+	rest, err := io.Copy(w, r)
+	if err != nil {
+		return 0, 0, err
+	}
+	read := uint64(n) + uint64(rest)
+
+	if isDir {
+		return read, crc.Sum32(), nil
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(head[:n])
+	}
+	p.files = append(p.files, &models.ArchiveFile{
+		Path:        name,
+		Size:        int64(read),
+		ContentType: contentType,
+		SHA256:      hex.EncodeToString(sha.Sum(nil)),
+	})
+	if f := htmlFile(name); f != nil {
+		p.htmlFiles = append(p.htmlFiles, f)
+	}
+	for _, s := range scanners {
+		p.findings = append(p.findings, s.Scan(name, scanned.Bytes())...)
+	}
+	return read, crc.Sum32(), nil
+}
+
+func (p *streamParser) centralEntry() error {
+	b, err := p.read(42)
+	if err != nil {
+		return err
+	}
+	creatorVersion := binary.LittleEndian.Uint16(b[0:])
+	flags := binary.LittleEndian.Uint16(b[4:])
+	crc := binary.LittleEndian.Uint32(b[12:])
+	compressed := uint64(binary.LittleEndian.Uint32(b[16:]))
+	uncompressed := uint64(binary.LittleEndian.Uint32(b[20:]))
+	nameLen := binary.LittleEndian.Uint16(b[24:])
+	extraLen := binary.LittleEndian.Uint16(b[26:])
+	commentLen := binary.LittleEndian.Uint16(b[28:])
+	externalAttrs := binary.LittleEndian.Uint32(b[34:])
+	offset := uint64(binary.LittleEndian.Uint32(b[38:]))
+
+	nameBytes, err := p.read(int(nameLen))
+	if err != nil {
+		return err
+	}
+	extra, err := p.read(int(extraLen))
+	if err != nil {
+		return err
+	}
+	if err = p.skip(uint64(commentLen)); err != nil {
+		return err
+	}
+	name := string(nameBytes)
+
+	// zip64 values are only present for the fields that overflowed, in this order
+	values := zip64Extra(extra)
+	for _, field := range []*uint64{&uncompressed, &compressed, &offset} {
+		if *field == uint32Max {
+			if len(values) == 0 {
+				return p.fatal(name, "zip64 sizes are missing")
+			}
+			*field, values = values[0], values[1:]
+		}
+	}
+
+	p.central++
+	if p.central > len(p.order) {
+		return p.fatal("", "central directory lists more entries than the archive holds")
+	}
+
+	entry := p.local[int64(offset)]
+	if entry == nil || entry.listed {
+		p.fail(name, "central directory does not match the entries in the archive")
+		return nil
+	}
+	entry.listed = true
+	if entry.name != name || entry.encrypted != (flags&encryptedFlag != 0) || entry.crc32 != crc ||
+		entry.compressed != compressed || entry.uncompressed != uncompressed {
+		p.fail(name, "central directory does not match the local header")
+	}
+
+	// only the central directory has the file mode
+	fh := zip.FileHeader{Name: name, CreatorVersion: creatorVersion, ExternalAttrs: externalAttrs}
+	if fh.Mode()&os.ModeSymlink != 0 {
+		p.fail(name, "symlinks are not allowed")
+	}
+	return nil
+}
+
+func (p *streamParser) end() error {
+	b, err := p.read(18)
+	if err != nil {
+		return p.readErr(err)
+	}
+	if binary.LittleEndian.Uint16(b[0:]) != 0 || binary.LittleEndian.Uint16(b[2:]) != 0 {
+		return p.fatal("", "multi-part archives are not supported")
+	}
+	if entries := binary.LittleEndian.Uint16(b[6:]); entries != uint16Max && int(entries) != p.central {
+		p.fail("", "end of central directory does not match the central directory")
+	}
+	if err = p.skip(uint64(binary.LittleEndian.Uint16(b[16:]))); err != nil {
+		return p.readErr(err)
+	}
+
+	// anything else would be invisible to a reader going by the central directory
+	for _, entry := range p.order {
+		if !entry.listed {
+			p.fail(entry.name, "not listed in the central directory")
+		}
+	}
+	if _, err = p.r.ReadByte(); err != io.EOF {
+		if err != nil {
+			return err
+		}
+		return p.fatal("", "unexpected data after the end of the archive")
+	}
+
+	if len(p.errs) > 0 {
+		return &ValidationError{Entries: p.errs}
+	}
+	if len(p.htmlFiles) == 0 {
+		return ErrNoIndexHtml
+	}
+	return nil
+}
+
+func (p *streamParser) read(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(p.r, b)
+	return b, err
+}
+
+func (p *streamParser) skip(n uint64) error {
+	skipped, err := io.CopyN(io.Discard, p.r, int64(n))
+	if err == io.EOF && uint64(skipped) < n {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// zip64Extra returns the values in the zip64 extended information extra field, if there is one
+func zip64Extra(extra []byte) []uint64 {
+	for len(extra) >= 4 {
+		id, size := binary.LittleEndian.Uint16(extra), int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			return nil
+		}
+		if id == zip64ExtraID {
+			var values []uint64
+			for field := extra[:size]; len(field) >= 8; field = field[8:] {
+				values = append(values, binary.LittleEndian.Uint64(field))
+			}
+			return values
+		}
+		extra = extra[size:]
+	}
+	return nil
+}
+
+// countingReader keeps track of the offset into the archive. It is also an io.ByteReader so that flate
+// reads no further than the end of each entry.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// limitedWriter keeps the first n bytes written to it and quietly drops the rest
+type limitedWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if int64(len(keep)) > l.n {
+			keep = keep[:l.n]
+		}
+		n, err := l.w.Write(keep)
+		l.n -= int64(n)
+		if err != nil {
+			return n, err
+		}
+	}
+	return len(p), nil
+}
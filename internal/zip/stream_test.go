@@ -0,0 +1,217 @@
+package zip_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"testing"
+
+	zip2 "github.com/ONSdigital/dp-interactives-api/internal/zip"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStream(t *testing.T) {
+	limits := zip2.Limits{MaxEntries: 10, MaxUncompressedSize: 1 << 20, MaxCompressionRatio: 100}
+
+	Convey("Given a valid archive", t, func() {
+		archive := writeZip(
+			entry{name: "index.html", body: []byte("<html><script src=\"https://evil.com/x.js\"></script></html>")},
+			entry{name: "js/"},
+			entry{name: "js/app.js", body: []byte("app")},
+		)
+
+		Convey("Then it passes through unchanged with its contents found along the way", func() {
+			var findings []*models.ContentFinding
+			stream := zip2.NewStream(bytes.NewReader(archive), limits, []zip2.ContentScanner{&zip2.ContentPolicy{}}, func(c *zip2.Contents, f []*models.ContentFinding) error {
+				findings = f
+				return nil
+			})
+			read, err := io.ReadAll(stream)
+			So(err, ShouldBeNil)
+			So(read, ShouldResemble, archive)
+			So(stream.Err(), ShouldBeNil)
+
+			c := stream.Contents()
+			So(c.Archive.Size, ShouldEqual, len(archive))
+			So(c.Manifest.SHA, ShouldEqual, fmt.Sprintf("%x", sha256.Sum256(archive)))
+			So(c.HTMLPaths(), ShouldResemble, []string{"index.html"})
+			So(c.Manifest.Files, ShouldHaveLength, 2)
+			So(c.Manifest.Files[1].Path, ShouldEqual, "js/app.js")
+			So(c.Manifest.Files[1].Size, ShouldEqual, 3)
+			So(c.Manifest.Files[1].SHA256, ShouldEqual, fmt.Sprintf("%x", sha256.Sum256([]byte("app"))))
+			So(findings, ShouldHaveLength, 1)
+			So(findings[0].Rule, ShouldEqual, zip2.RuleExternalScript)
+		})
+
+		Convey("Then the contents match what Open finds", func() {
+			name := writeTemp(archive)
+			defer os.Remove(name)
+			opened, err := zip2.Open(name, limits)
+			So(err, ShouldBeNil)
+
+			stream := zip2.NewStream(bytes.NewReader(archive), limits, nil, nil)
+			_, err = io.Copy(io.Discard, stream)
+			So(err, ShouldBeNil)
+			So(stream.Contents(), ShouldResemble, opened)
+		})
+
+		Convey("Then an error from the verifier is returned in place of the end of the archive", func() {
+			rejected := errors.New("rejected")
+			stream := zip2.NewStream(bytes.NewReader(archive), limits, nil, func(*zip2.Contents, []*models.ContentFinding) error { return rejected })
+			_, err := io.Copy(io.Discard, stream)
+			So(err, ShouldEqual, rejected)
+			So(stream.Err(), ShouldEqual, rejected)
+			So(stream.Contents(), ShouldBeNil)
+		})
+
+		Convey("Then a truncated copy is rejected", func() {
+			So(streamErrors(archive[:len(archive)-10], limits)[0].Reason, ShouldEqual, "archive is truncated")
+		})
+
+		Convey("Then data after the end of the archive is rejected", func() {
+			So(streamErrors(append(archive, 'x'), limits)[0].Reason, ShouldEqual, "unexpected data after the end of the archive")
+		})
+
+		Convey("Then a central directory that disagrees with the entries is rejected", func() {
+			tampered := append([]byte{}, archive...)
+			cd := bytes.Index(tampered, []byte{0x50, 0x4b, 0x01, 0x02})
+			tampered[cd+16]++ // crc32 of the first entry
+			entries := streamErrors(tampered, limits)
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Error(), ShouldEqual, "index.html: central directory does not match the local header")
+		})
+
+		Convey("Then it is rejected when over the size limit", func() {
+			entries := streamErrors(archive, zip2.Limits{MaxUncompressedSize: 10})
+			So(entries[len(entries)-1].Reason, ShouldEqual, "uncompressed size exceeds the limit of 10 bytes")
+		})
+
+		Convey("Then it can be abandoned part way through", func() {
+			stream := zip2.NewStream(bytes.NewReader(archive), limits, nil, nil)
+			_, err := stream.Read(make([]byte, 10))
+			So(err, ShouldBeNil)
+			So(stream.Close(), ShouldBeNil)
+		})
+	})
+
+	Convey("Given a real interactive", t, func() {
+		name := "../test-support/resources/single-interactive.zip"
+		archive, err := os.ReadFile(name)
+		So(err, ShouldBeNil)
+
+		Convey("Then it streams with the same contents Open finds", func() {
+			opened, err := zip2.Open(name, zip2.Limits{})
+			So(err, ShouldBeNil)
+
+			stream := zip2.NewStream(bytes.NewReader(archive), zip2.Limits{}, nil, nil)
+			_, err = io.Copy(io.Discard, stream)
+			So(err, ShouldBeNil)
+			So(stream.Contents(), ShouldResemble, opened)
+		})
+	})
+
+	Convey("Given an archive written with sizes up front", t, func() {
+		archive := writeRawZip(entry{name: "index.html", body: []byte("<html></html>")})
+
+		Convey("Then stored entries can be read", func() {
+			stream := zip2.NewStream(bytes.NewReader(archive), limits, nil, nil)
+			_, err := io.Copy(io.Discard, stream)
+			So(err, ShouldBeNil)
+			So(stream.Contents().Manifest.Files[0].Size, ShouldEqual, len("<html></html>"))
+		})
+	})
+
+	Convey("Given entries that are not allowed", t, func() {
+		archive := writeZip(
+			entry{name: "index.html"},
+			entry{name: "../evil.html"},
+			entry{name: "link", mode: os.ModeSymlink | 0777, body: []byte("/etc/passwd")},
+		)
+
+		Convey("Then every one is rejected", func() {
+			entries := streamErrors(archive, limits)
+			So(entries, ShouldHaveLength, 2)
+			So(entries[0].Error(), ShouldEqual, "../evil.html: path traversal is not allowed")
+			So(entries[1].Error(), ShouldEqual, "link: symlinks are not allowed")
+		})
+	})
+
+	Convey("Given an archive with no html file", t, func() {
+		archive := writeZip(entry{name: "app.js", body: []byte("app")})
+
+		Convey("Then it is rejected", func() {
+			_, err := io.Copy(io.Discard, zip2.NewStream(bytes.NewReader(archive), limits, nil, nil))
+			So(err, ShouldEqual, zip2.ErrNoIndexHtml)
+		})
+	})
+
+	Convey("Given something other than a zip", t, func() {
+		Convey("Then it is rejected", func() {
+			So(streamErrors([]byte("<html></html>"), limits)[0].Reason, ShouldStartWith, "not a zip archive")
+		})
+	})
+}
+
+// writeZip builds an archive the way archive/zip streams one, with sizes in data descriptors
+func writeZip(entries ...entry) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		if e.mode != 0 {
+			header.SetMode(e.mode)
+		}
+		f, err := w.CreateHeader(header)
+		So(err, ShouldBeNil)
+		_, err = f.Write(e.body)
+		So(err, ShouldBeNil)
+	}
+	So(w.Close(), ShouldBeNil)
+	return buf.Bytes()
+}
+
+// writeRawZip builds an archive of stored entries with their sizes in the local headers
+func writeRawZip(entries ...entry) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for _, e := range entries {
+		f, err := w.CreateRaw(&zip.FileHeader{
+			Name:               e.name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE(e.body),
+			CompressedSize64:   uint64(len(e.body)),
+			UncompressedSize64: uint64(len(e.body)),
+		})
+		So(err, ShouldBeNil)
+		_, err = f.Write(e.body)
+		So(err, ShouldBeNil)
+	}
+	So(w.Close(), ShouldBeNil)
+
+	// CreateRaw leaves the data descriptor flag as given, so the local headers carry the sizes
+	So(binary.LittleEndian.Uint16(buf.Bytes()[6:])&0x8, ShouldEqual, 0)
+	return buf.Bytes()
+}
+
+func writeTemp(b []byte) string {
+	f, err := os.CreateTemp("", "test-zip_*.zip")
+	So(err, ShouldBeNil)
+	_, err = f.Write(b)
+	So(err, ShouldBeNil)
+	So(f.Close(), ShouldBeNil)
+	return f.Name()
+}
+
+func streamErrors(archive []byte, limits zip2.Limits) []*zip2.EntryError {
+	stream := zip2.NewStream(bytes.NewReader(archive), limits, nil, nil)
+	_, err := io.Copy(io.Discard, stream)
+	So(stream.Err(), ShouldEqual, err)
+	return validationErrors(err)
+}
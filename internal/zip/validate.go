@@ -0,0 +1,115 @@
+package zip
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const encryptedFlag = 0x1
+
+// Limits bounds what an uploaded archive may contain - a zero limit is not enforced.
+// Unsafe paths, symlinks and encrypted entries are always rejected.
+type Limits struct {
+	MaxEntries          int
+	MaxUncompressedSize int64
+	MaxCompressionRatio float64
+}
+
+// EntryError is a single archive entry that failed validation. Entry is empty when the
+// problem is with the archive as a whole.
+type EntryError struct {
+	Entry  string
+	Reason string
+}
+
+func (e *EntryError) Error() string {
+	if e.Entry == "" {
+		return e.Reason
+	}
+	return fmt.Sprintf("%s: %s", e.Entry, e.Reason)
+}
+
+// LogData gives the entry and reason separately in logs
+func (e *EntryError) LogData() map[string]interface{} {
+	return map[string]interface{}{"entry": e.Entry, "reason": e.Reason}
+}
+
+// ValidationError holds every problem found in an archive
+type ValidationError struct {
+	Entries []*EntryError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Entries))
+	for _, entry := range e.Entries {
+		msgs = append(msgs, entry.Error())
+	}
+	return fmt.Sprintf("invalid archive: %s", strings.Join(msgs, "; "))
+}
+
+// Errors returns each problem as a separate error, ready for an error response
+func (e *ValidationError) Errors() []error {
+	errs := make([]error, 0, len(e.Entries))
+	for _, entry := range e.Entries {
+		errs = append(errs, entry)
+	}
+	return errs
+}
+
+// Validate checks every entry in the archive, returning a *ValidationError listing all problems found.
+// Sizes are taken from the archive headers - archive/zip refuses to read an entry that does not match them.
+func Validate(files []*zip.File, limits Limits) error {
+	var errs []*EntryError
+	fail := func(entry, reason string, args ...interface{}) {
+		errs = append(errs, &EntryError{Entry: entry, Reason: fmt.Sprintf(reason, args...)})
+	}
+
+	if limits.MaxEntries > 0 && len(files) > limits.MaxEntries {
+		fail("", "archive has %d entries, the limit is %d", len(files), limits.MaxEntries)
+	}
+
+	var total uint64
+	for _, f := range files {
+		if reason := unsafePath(f.Name); reason != "" {
+			fail(f.Name, reason)
+		}
+		if f.Mode()&os.ModeSymlink != 0 {
+			fail(f.Name, "symlinks are not allowed")
+		}
+		if f.Flags&encryptedFlag != 0 {
+			fail(f.Name, "encrypted entries are not allowed")
+		}
+
+		if limits.MaxCompressionRatio > 0 && f.UncompressedSize64 > 0 {
+			if f.CompressedSize64 == 0 || float64(f.UncompressedSize64)/float64(f.CompressedSize64) > limits.MaxCompressionRatio {
+				fail(f.Name, "compression ratio exceeds %g", limits.MaxCompressionRatio)
+			}
+		}
+		total += f.UncompressedSize64
+	}
+
+	if limits.MaxUncompressedSize > 0 && total > uint64(limits.MaxUncompressedSize) {
+		fail("", "uncompressed size %d bytes exceeds the limit of %d bytes", total, limits.MaxUncompressedSize)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Entries: errs}
+	}
+	return nil
+}
+
+// unsafePath returns why the entry name could be written outside the directory it is extracted to
+func unsafePath(name string) string {
+	normalised := strings.ReplaceAll(name, "\\", "/")
+	if strings.HasPrefix(normalised, "/") || (len(normalised) > 1 && normalised[1] == ':') {
+		return "absolute paths are not allowed"
+	}
+	for _, part := range strings.Split(normalised, "/") {
+		if part == ".." {
+			return "path traversal is not allowed"
+		}
+	}
+	return ""
+}
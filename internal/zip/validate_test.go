@@ -0,0 +1,123 @@
+package zip_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	zip2 "github.com/ONSdigital/dp-interactives-api/internal/zip"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidate(t *testing.T) {
+	limits := zip2.Limits{MaxEntries: 3, MaxUncompressedSize: 1 << 20, MaxCompressionRatio: 100}
+
+	Convey("Given an archive within the limits", t, func() {
+		files := readEntries(entry{name: "index.html", body: []byte("<html></html>")}, entry{name: "js/app.js", body: []byte("app")})
+
+		Convey("Then it is valid", func() {
+			So(zip2.Validate(files, limits), ShouldBeNil)
+		})
+	})
+
+	Convey("Given entries that would be extracted outside the upload", t, func() {
+		files := readEntries(
+			entry{name: "../evil.html"},
+			entry{name: "js/../../evil.js"},
+			entry{name: "/etc/passwd"},
+			entry{name: `C:\windows\evil.html`},
+		)
+
+		Convey("Then every one is rejected", func() {
+			entries := validationErrors(zip2.Validate(files, zip2.Limits{}))
+			So(entries, ShouldHaveLength, 4)
+			So(entries[0].Entry, ShouldEqual, "../evil.html")
+			So(entries[0].Reason, ShouldEqual, "path traversal is not allowed")
+			So(entries[1].Reason, ShouldEqual, "path traversal is not allowed")
+			So(entries[2].Reason, ShouldEqual, "absolute paths are not allowed")
+			So(entries[3].Reason, ShouldEqual, "absolute paths are not allowed")
+		})
+	})
+
+	Convey("Given an archive with a symlink and an encrypted entry", t, func() {
+		files := readEntries(
+			entry{name: "index.html"},
+			entry{name: "link", mode: os.ModeSymlink | 0777, body: []byte("/etc/passwd")},
+			entry{name: "secret.html", flags: 0x1},
+		)
+
+		Convey("Then both are rejected", func() {
+			entries := validationErrors(zip2.Validate(files, limits))
+			So(entries, ShouldHaveLength, 2)
+			So(entries[0].Error(), ShouldEqual, "link: symlinks are not allowed")
+			So(entries[1].Error(), ShouldEqual, "secret.html: encrypted entries are not allowed")
+		})
+	})
+
+	Convey("Given a highly compressed entry", t, func() {
+		files := readEntries(entry{name: "index.html", body: make([]byte, 512<<10)})
+
+		Convey("Then it is rejected as a zip bomb", func() {
+			entries := validationErrors(zip2.Validate(files, limits))
+			So(entries, ShouldHaveLength, 1)
+			So(entries[0].Reason, ShouldEqual, "compression ratio exceeds 100")
+		})
+
+		Convey("Then it is allowed when the ratio is not limited", func() {
+			So(zip2.Validate(files, zip2.Limits{}), ShouldBeNil)
+		})
+	})
+
+	Convey("Given an archive over the entry and size limits", t, func() {
+		files := readEntries(
+			entry{name: "a.html", body: bytes.Repeat([]byte("abcdefgh"), 100<<10)},
+			entry{name: "b.html", body: bytes.Repeat([]byte("abcdefgh"), 100<<10)},
+			entry{name: "c.html"},
+			entry{name: "d.html"},
+		)
+
+		Convey("Then the archive as a whole is rejected", func() {
+			entries := validationErrors(zip2.Validate(files, zip2.Limits{MaxEntries: 3, MaxUncompressedSize: 1 << 20}))
+			So(entries, ShouldHaveLength, 2)
+			So(entries[0].Entry, ShouldBeEmpty)
+			So(entries[0].Error(), ShouldEqual, "archive has 4 entries, the limit is 3")
+			So(entries[1].Error(), ShouldStartWith, "uncompressed size 1638400 bytes exceeds")
+		})
+	})
+}
+
+type entry struct {
+	name  string
+	body  []byte
+	mode  os.FileMode
+	flags uint16
+}
+
+// readEntries builds an in-memory archive and reads its entries back
+func readEntries(entries ...entry) []*zip.File {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate, Flags: e.flags}
+		if e.mode != 0 {
+			header.SetMode(e.mode)
+		}
+		f, err := w.CreateHeader(header)
+		So(err, ShouldBeNil)
+		_, err = f.Write(e.body)
+		So(err, ShouldBeNil)
+	}
+	So(w.Close(), ShouldBeNil)
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	So(err, ShouldBeNil)
+	return r.File
+}
+
+func validationErrors(err error) []*zip2.EntryError {
+	var validationErr *zip2.ValidationError
+	So(errors.As(err, &validationErr), ShouldBeTrue)
+	return validationErr.Entries
+}
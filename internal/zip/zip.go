@@ -2,56 +2,157 @@ package zip
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
-	"github.com/ONSdigital/dp-interactives-api/models"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
 )
 
 var (
 	ErrNoIndexHtml = errors.New("interactive must contain 1 htm(l) file")
 )
 
+// Contents is what Open found in an archive
+type Contents struct {
+	Archive   *models.Archive
+	HTMLFiles []*models.HTMLFile
+	Manifest  *models.Manifest
+}
+
+// HTMLPaths returns the path of every html file in the archive
+func (c *Contents) HTMLPaths() []string {
+	var paths []string
+	for _, f := range c.HTMLFiles {
+		paths = append(paths, f.URI)
+	}
+	return paths
+}
+
 //this is a tactical solution - we need to know about html files on new upload
 //so zebedee collection json populated as expected for preview
 //because we process the zip async - zebedee doesnt get this info quick enough
 
-func Open(name string) (*models.Archive, []*models.HTMLFile, error) {
+// Open validates the archive against limits, lists its html files and builds a manifest of every file
+func Open(name string, limits Limits) (*Contents, error) {
 	fi, err := os.Stat(name)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	zipReader, err := zip.OpenReader(name)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	if err = Validate(zipReader.File, limits); err != nil {
+		return nil, err
 	}
 
 	var hasHtmFile bool
 	var htmlFiles []*models.HTMLFile
+	manifest := &models.Manifest{}
 	for _, f := range zipReader.File {
-		filename := filepath.Base(f.Name)
-		if filename[0] == '.' {
-			//skip hidden files
+		if f.FileInfo().IsDir() {
 			continue
 		}
+		entry, err := manifestEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s %w", f.Name, err)
+		}
+		manifest.Files = append(manifest.Files, entry)
 
-		fileExt := filepath.Ext(f.Name)
-		if strings.EqualFold(fileExt, ".html") || strings.EqualFold(fileExt, ".htm") {
+		if htmlFile := htmlFile(f.Name); htmlFile != nil {
 			hasHtmFile = true
-			//we only care about html files right now for preview
-			//the patch from importer will overwrite with full details
-			htmlFiles = append(htmlFiles, &models.HTMLFile{
-				Name: filename,
-				URI:  f.Name,
-			})
+			htmlFiles = append(htmlFiles, htmlFile)
 		}
 	}
 
 	if !hasHtmFile {
-		return nil, nil, ErrNoIndexHtml
+		return nil, ErrNoIndexHtml
 	}
 
-	return &models.Archive{Size: fi.Size()}, htmlFiles, nil
+	if manifest.SHA, err = hashFile(name); err != nil {
+		return nil, err
+	}
+
+	return &Contents{
+		Archive:   &models.Archive{Size: fi.Size()},
+		HTMLFiles: htmlFiles,
+		Manifest:  manifest,
+	}, nil
+}
+
+// htmlFile returns the entry as an html file, or nil if it is not one
+func htmlFile(name string) *models.HTMLFile {
+	filename := filepath.Base(name)
+	if filename[0] == '.' {
+		//skip hidden files
+		return nil
+	}
+
+	fileExt := filepath.Ext(name)
+	if strings.EqualFold(fileExt, ".html") || strings.EqualFold(fileExt, ".htm") {
+		//we only care about html files right now for preview
+		//the patch from importer will overwrite with full details
+		return &models.HTMLFile{
+			Name: filename,
+			URI:  name,
+		}
+	}
+	return nil
+}
+
+// manifestEntry hashes the entry, taking its content type from the extension or failing that its content
+func manifestEntry(f *zip.File) (*models.ArchiveFile, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	hash := sha256.New()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(rc, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	hash.Write(head[:n])
+	if _, err = io.Copy(hash, rc); err != nil {
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(f.Name))
+	if contentType == "" {
+		contentType = http.DetectContentType(head[:n])
+	}
+
+	return &models.ArchiveFile{
+		Path:        f.Name,
+		Size:        int64(f.UncompressedSize64),
+		ContentType: contentType,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func hashFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
@@ -2,6 +2,8 @@ package zip_test
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"fmt"
 	zip2 "github.com/ONSdigital/dp-interactives-api/internal/zip"
 	"io"
 	"os"
@@ -18,26 +20,35 @@ func TestArchive(t *testing.T) {
 		So(err, ShouldBeNil)
 		defer os.Remove(archive.Name())
 		Convey("Then there should an error returned when attempt to open", func() {
-			a, f, err := zip2.Open(archive.Name())
+			c, err := zip2.Open(archive.Name(), zip2.Limits{})
 			So(err, ShouldBeError, zip.ErrFormat)
-			So(a, ShouldBeNil)
-			So(f, ShouldBeNil)
+			So(c, ShouldBeNil)
 		})
 	})
 
 	Convey("Given a valid zip file", t, func() {
-		archiveName, _, err := createTestZip("root.css", "root.html", "root.js", "index.html")
+		archiveName, b, err := createTestZip("root.css", "root.html", "root.js", "index.html")
 		defer os.Remove(archiveName)
 		So(err, ShouldBeNil)
 		So(archiveName, ShouldNotBeEmpty)
 
 		Convey("Then open should run successfully", func() {
-			a, f, err := zip2.Open(archiveName)
+			c, err := zip2.Open(archiveName, zip2.Limits{})
 			So(err, ShouldBeNil)
-			So(a, ShouldNotBeNil)
+			So(c.Archive, ShouldNotBeNil)
 
 			Convey("And files in archive should be 4", func() {
-				So(len(f), ShouldEqual, 2)
+				So(len(c.HTMLFiles), ShouldEqual, 2)
+			})
+
+			Convey("And the manifest lists every file with its checksum", func() {
+				So(c.Manifest.Files, ShouldHaveLength, 4)
+				So(c.Manifest.Files[1].Path, ShouldEqual, "root.html")
+				So(c.Manifest.Files[1].Size, ShouldEqual, len("root.html"))
+				So(c.Manifest.Files[1].ContentType, ShouldEqual, "text/html; charset=utf-8")
+				// sha256 of the content, which is the file name
+				So(c.Manifest.Files[1].SHA256, ShouldEqual, fmt.Sprintf("%x", sha256.Sum256([]byte("root.html"))))
+				So(c.Manifest.SHA, ShouldEqual, fmt.Sprintf("%x", sha256.Sum256(b)))
 			})
 		})
 	})
@@ -49,10 +60,9 @@ func TestArchive(t *testing.T) {
 		So(archiveName, ShouldNotBeEmpty)
 
 		Convey("Then open should run successfully", func() {
-			a, f, err := zip2.Open(archiveName)
+			c, err := zip2.Open(archiveName, zip2.Limits{})
 			So(err, ShouldEqual, zip2.ErrNoIndexHtml)
-			So(a, ShouldBeNil)
-			So(f, ShouldBeNil)
+			So(c, ShouldBeNil)
 		})
 	})
 }
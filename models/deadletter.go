@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// DeadLetter is an outbox event that could not be delivered to the importer. It keeps everything
+// needed to send the identical message again.
+type DeadLetter struct {
+	ID            string       `bson:"_id"            json:"id"`
+	InteractiveID string       `bson:"interactive_id" json:"interactive_id"`
+	Event         *OutboxEvent `bson:"event"          json:"event"`
+	Error         string       `bson:"error"          json:"error"`
+	Attempts      int          `bson:"attempts"       json:"attempts"`
+	Created       *time.Time   `bson:"created,omitempty"      json:"created,omitempty"`
+	LastUpdated   *time.Time   `bson:"last_updated,omitempty" json:"last_updated,omitempty"`
+}
+
+// NewDeadLetter records the event as undeliverable after attempts sends, the last failing with err
+func NewDeadLetter(e *OutboxEvent, attempts int, err error) *DeadLetter {
+	d := &DeadLetter{
+		ID:            e.ID,
+		InteractiveID: e.InteractiveID,
+		Event:         e,
+		Attempts:      attempts,
+	}
+	if err != nil {
+		d.Error = err.Error()
+	}
+	return d
+}
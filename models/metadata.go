@@ -1,7 +1,10 @@
 package models
 
 import (
+	"errors"
 	"fmt"
+	"path"
+	"reflect"
 	"strings"
 	"time"
 
@@ -10,6 +13,11 @@ import (
 
 type State int
 
+// DefaultEntryPoint is the page an interactive opens on unless the uploader chooses another
+const DefaultEntryPoint = "index.html"
+
+var ErrEntryPointNotFound = errors.New("entry point not found in archive")
+
 const (
 	ArchiveUploaded State = iota
 	ArchiveDispatchFailed
@@ -18,6 +26,7 @@ const (
 	ImportSuccess
 	ArchiveUploading
 	ArchiveUploadFailed
+	ArchiveQuarantined
 )
 
 var (
@@ -29,6 +38,7 @@ var (
 		"importsuccess":               ImportSuccess,
 		"archiveuploading":            ArchiveUploading,
 		"archiveuploadfailed":         ArchiveUploadFailed,
+		"archivequarantined":          ArchiveQuarantined,
 	}
 )
 
@@ -48,6 +58,8 @@ func (s State) String() string {
 		return "ArchiveUploading"
 	case ArchiveUploadFailed:
 		return "ArchiveUploadFailed"
+	case ArchiveQuarantined:
+		return "ArchiveQuarantined"
 	default:
 		return "Unknown"
 	}
@@ -68,12 +80,46 @@ type Filter struct {
 }
 
 type Metadata struct {
-	Title             string `bson:"title"                    json:"title"                      mod:"trim" validate:"required"`
-	Label             string `bson:"label"                    json:"label"                      mod:"trim" validate:"required,alphanum"`
-	InternalID        string `bson:"internal_id"              json:"internal_id"                mod:"trim" validate:"required,alphanum"`
-	CollectionID      string `bson:"collection_id,omitempty"  json:"collection_id,omitempty"`
-	HumanReadableSlug string `bson:"slug,omitempty"           json:"slug,omitempty"`
-	ResourceID        string `bson:"resource_id,omitempty"    json:"resource_id,omitempty"`
+	Title             string   `bson:"title"                    json:"title"                      mod:"trim" validate:"required"`
+	Label             string   `bson:"label"                    json:"label"                      mod:"trim" validate:"required,alphanum"`
+	InternalID        string   `bson:"internal_id"              json:"internal_id"                mod:"trim" validate:"required,alphanum"`
+	CollectionID      string   `bson:"collection_id,omitempty"  json:"collection_id,omitempty"`
+	HumanReadableSlug string   `bson:"slug,omitempty"           json:"slug,omitempty"             mod:"trim" validate:"omitempty,max=100,slug"`
+	ResourceID        string   `bson:"resource_id,omitempty"    json:"resource_id,omitempty"`
+	EntryPoint        string   `bson:"entry_point,omitempty"    json:"entry_point,omitempty"      mod:"trim"`
+	PreviousSlugs     []string `bson:"previous_slugs,omitempty" json:"previous_slugs,omitempty"`
+	// Translations holds the metadata in languages other than English (see data.Languages)
+	Translations map[string]*Translation `bson:"translations,omitempty" json:"translations,omitempty" mod:"dive" validate:"omitempty,dive,keys,translation,endkeys,required"`
+	// editorial metadata shown alongside the interactive
+	Summary       string   `bson:"summary,omitempty"       json:"summary,omitempty"       mod:"trim"      validate:"omitempty,max=1000"`
+	Keywords      []string `bson:"keywords,omitempty"      json:"keywords,omitempty"      mod:"dive,trim" validate:"omitempty,max=20,dive,required,max=50"`
+	RelatedLink   *Link    `bson:"related_link,omitempty"  json:"related_link,omitempty"`
+	Topics        []string `bson:"topics,omitempty"        json:"topics,omitempty"        mod:"dive,trim" validate:"omitempty,dive,required"`
+	Contact       *Contact `bson:"contact,omitempty"       json:"contact,omitempty"`
+	Accessibility string   `bson:"accessibility,omitempty" json:"accessibility,omitempty" mod:"trim"      validate:"omitempty,max=2000"`
+	Thumbnail     string   `bson:"thumbnail,omitempty"     json:"thumbnail,omitempty"     mod:"trim"      validate:"omitempty,uri"`
+}
+
+// Link is a link to a related page, such as the dataset or release an interactive is drawn from
+type Link struct {
+	Title string `bson:"title,omitempty" json:"title,omitempty" mod:"trim"`
+	URI   string `bson:"uri"             json:"uri"             mod:"trim" validate:"required,uri"`
+}
+
+// Contact is who to get in touch with about an interactive
+type Contact struct {
+	Name      string `bson:"name"                json:"name"                mod:"trim" validate:"required"`
+	Email     string `bson:"email"               json:"email"               mod:"trim" validate:"required,email"`
+	Telephone string `bson:"telephone,omitempty" json:"telephone,omitempty" mod:"trim" validate:"omitempty,max=30"`
+}
+
+// Translation is the metadata of an interactive in a language other than English. Its slug is generated from its
+// label with the rules of that language.
+type Translation struct {
+	Title             string   `bson:"title"                    json:"title"                    mod:"trim" validate:"required"`
+	Label             string   `bson:"label"                    json:"label"                    mod:"trim" validate:"required,alphanumunicode"`
+	HumanReadableSlug string   `bson:"slug,omitempty"           json:"slug,omitempty"           mod:"trim" validate:"omitempty,max=100,slug"`
+	PreviousSlugs     []string `bson:"previous_slugs,omitempty" json:"previous_slugs,omitempty"`
 }
 
 func (i *Metadata) Update(update *Metadata, slugGen data.Generator) *Metadata {
@@ -83,9 +129,14 @@ func (i *Metadata) Update(update *Metadata, slugGen data.Generator) *Metadata {
 	if i == nil {
 		i = &Metadata{ResourceID: update.ResourceID, HumanReadableSlug: update.HumanReadableSlug}
 	}
+	// a slug given by hand wins over one generated from the label, and is kept until the label changes
+	if update.HumanReadableSlug != "" {
+		i.setSlug(update.HumanReadableSlug)
+	} else if update.Label != "" && update.Label != i.Label {
+		i.setSlug(slugGen(update.Label))
+	}
 	if update.Label != "" {
 		i.Label = update.Label
-		i.HumanReadableSlug = slugGen(update.Label)
 	}
 	if update.Title != "" {
 		i.Title = update.Title
@@ -96,30 +147,228 @@ func (i *Metadata) Update(update *Metadata, slugGen data.Generator) *Metadata {
 	if update.CollectionID != "" {
 		i.CollectionID = update.CollectionID
 	}
+	if update.EntryPoint != "" {
+		i.EntryPoint = update.EntryPoint
+	}
+	if update.Summary != "" {
+		i.Summary = update.Summary
+	}
+	// lists are replaced as a whole - an empty one clears them
+	if update.Keywords != nil {
+		i.Keywords = update.Keywords
+	}
+	if update.Topics != nil {
+		i.Topics = update.Topics
+	}
+	if update.RelatedLink != nil {
+		i.RelatedLink = update.RelatedLink
+	}
+	if update.Contact != nil {
+		i.Contact = update.Contact
+	}
+	if update.Accessibility != "" {
+		i.Accessibility = update.Accessibility
+	}
+	if update.Thumbnail != "" {
+		i.Thumbnail = update.Thumbnail
+	}
+	for lang, t := range update.Translations {
+		if t != nil {
+			i.translate(lang, t)
+		}
+	}
 	return i
 }
 
+// Merge takes every field from metadata that a merge patch has been applied to, so unlike Update it can clear
+// them. The resource id and slug history cannot be patched, and the labels and slugs are kept for Update to
+// change, so that the slugs follow the labels as they do for any other update.
+func (i *Metadata) Merge(patched *Metadata) *Metadata {
+	merged := *patched
+	merged.ResourceID, merged.PreviousSlugs = i.ResourceID, i.PreviousSlugs
+	merged.Label, merged.HumanReadableSlug = i.Label, i.HumanReadableSlug
+	merged.Translations = map[string]*Translation{}
+	for lang, t := range patched.Translations {
+		if t == nil {
+			continue
+		}
+		kept := &Translation{Title: t.Title}
+		if current := i.Translations[lang]; current != nil {
+			kept.Label, kept.HumanReadableSlug, kept.PreviousSlugs = current.Label, current.HumanReadableSlug, current.PreviousSlugs
+		}
+		merged.Translations[lang] = kept
+	}
+	if len(merged.Translations) == 0 {
+		merged.Translations = nil
+	}
+	return &merged
+}
+
+// Clear empties the fields named by their json names, as a null does in a merge patch. As for Merge, the resource
+// id, slug history, labels and slugs are kept.
+func (i *Metadata) Clear(fields []string) *Metadata {
+	cleared := *i
+	v := reflect.ValueOf(&cleared).Elem()
+	for n := 0; n < v.NumField(); n++ {
+		name := strings.Split(v.Type().Field(n).Tag.Get("json"), ",")[0]
+		for _, field := range fields {
+			if field == name {
+				v.Field(n).Set(reflect.Zero(v.Field(n).Type()))
+			}
+		}
+	}
+	return i.Merge(&cleared)
+}
+
+// GenerateSlugs fills in the slugs that were not given by hand, in English and in each translation
+func (i *Metadata) GenerateSlugs(slugGen data.Generator) {
+	if i.HumanReadableSlug == "" {
+		i.HumanReadableSlug = slugGen(i.Label)
+	}
+	for lang, t := range i.Translations {
+		if t != nil && t.HumanReadableSlug == "" {
+			t.HumanReadableSlug = data.GenerateLocalisedSlug(lang)(t.Label)
+		}
+	}
+}
+
+// translate merges an update to the metadata in another language
+func (i *Metadata) translate(lang string, update *Translation) {
+	if i.Translations == nil {
+		i.Translations = map[string]*Translation{}
+	}
+	t := i.Translations[lang]
+	if t == nil {
+		t = &Translation{}
+		i.Translations[lang] = t
+	}
+	if update.HumanReadableSlug != "" {
+		t.setSlug(update.HumanReadableSlug)
+	} else if update.Label != "" && (update.Label != t.Label || t.HumanReadableSlug == "") {
+		t.setSlug(data.GenerateLocalisedSlug(lang)(update.Label))
+	}
+	if update.Label != "" {
+		t.Label = update.Label
+	}
+	if update.Title != "" {
+		t.Title = update.Title
+	}
+}
+
+// setSlug changes the slug, keeping the old one so that links already shared can be redirected
+func (i *Metadata) setSlug(slug string) {
+	i.HumanReadableSlug, i.PreviousSlugs = changeSlug(i.HumanReadableSlug, i.PreviousSlugs, slug)
+}
+
+// HadSlug is true if the slug is one the interactive has had before
+func (i *Metadata) HadSlug(slug string) bool {
+	return hadSlug(i.PreviousSlugs, slug)
+}
+
+// setSlug changes the slug in this language, keeping the old one as the english slug does
+func (t *Translation) setSlug(slug string) {
+	if t.HumanReadableSlug == "" {
+		t.HumanReadableSlug = slug
+		return
+	}
+	t.HumanReadableSlug, t.PreviousSlugs = changeSlug(t.HumanReadableSlug, t.PreviousSlugs, slug)
+}
+
+// HadSlug is true if the slug is one the interactive has had before in this language
+func (t *Translation) HadSlug(slug string) bool {
+	return hadSlug(t.PreviousSlugs, slug)
+}
+
+// changeSlug returns the new slug and the slug history with the current slug added (and the new one taken out)
+func changeSlug(current string, previousSlugs []string, slug string) (string, []string) {
+	if slug == current {
+		return current, previousSlugs
+	}
+	previous := []string{}
+	for _, s := range previousSlugs {
+		if s != slug && s != current {
+			previous = append(previous, s)
+		}
+	}
+	if current != "" {
+		previous = append(previous, current)
+	}
+	return slug, previous
+}
+
+func hadSlug(previousSlugs []string, slug string) bool {
+	for _, s := range previousSlugs {
+		if s == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveEntryPoint picks the html file an interactive opens on from the archive's html files. A requested
+// entry point must be in the archive. Otherwise the current one is kept while it is still there, falling
+// back to index.html when present.
+func ResolveEntryPoint(requested, current string, htmlFiles []string) (string, error) {
+	has := func(name string) bool {
+		for _, f := range htmlFiles {
+			if f == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if requested != "" {
+		requested = strings.TrimPrefix(path.Clean("/"+requested), "/")
+		if !has(requested) {
+			return "", fmt.Errorf("%w: %s", ErrEntryPointNotFound, requested)
+		}
+		return requested, nil
+	}
+	for _, candidate := range []string{current, DefaultEntryPoint} {
+		if candidate != "" && has(candidate) {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
 //(i think) omitempty reuqired for all fields for update to work correctly - otherwise we overwrite incorrectly
 type Interactive struct {
-	ID          string      `bson:"_id,omitempty"               json:"id,omitempty"`
-	Archive     *Archive    `bson:"archive,omitempty"           json:"archive,omitempty"`
-	Metadata    *Metadata   `bson:"metadata,omitempty"          json:"metadata,omitempty"`
-	Published   *bool       `bson:"published,omitempty"         json:"published,omitempty"`
-	State       string      `bson:"state,omitempty"             json:"state,omitempty"`
-	LastUpdated *time.Time  `bson:"last_updated,omitempty"      json:"last_updated,omitempty"`
-	HTMLFiles   []*HTMLFile `bson:"html_files,omitempty"        json:"html_files,omitempty"`
+	ID          string       `bson:"_id,omitempty"               json:"id,omitempty"`
+	Archive     *Archive     `bson:"archive,omitempty"           json:"archive,omitempty"`
+	Metadata    *Metadata    `bson:"metadata,omitempty"          json:"metadata,omitempty"`
+	Published   *bool        `bson:"published,omitempty"         json:"published,omitempty"`
+	State       string       `bson:"state,omitempty"             json:"state,omitempty"`
+	LastUpdated *time.Time   `bson:"last_updated,omitempty"      json:"last_updated,omitempty"`
+	HTMLFiles   []*HTMLFile  `bson:"html_files,omitempty"        json:"html_files,omitempty"`
+	Content     *ContentScan `bson:"content_scan,omitempty"      json:"content_scan,omitempty"`
+	Thumbnails  []*Thumbnail `bson:"thumbnails,omitempty"        json:"thumbnails,omitempty"`
 	//Mongo only
-	Active *bool  `bson:"active,omitempty"            json:"-"`
-	SHA    string `bson:"sha,omitempty"               json:"-"`
+	Active *bool          `bson:"active,omitempty"            json:"-"`
+	SHA    string         `bson:"sha,omitempty"               json:"-"`
+	Files  []*ArchiveFile `bson:"files,omitempty"             json:"-"`
+	Outbox []*OutboxEvent `bson:"outbox,omitempty"            json:"-"`
 	//JSON only
-	URL string `bson:"-" json:"url,omitempty"`
-	URI string `bson:"-" json:"uri,omitempty"`
+	URL       string `bson:"-" json:"url,omitempty"`
+	URI       string `bson:"-" json:"uri,omitempty"`
+	Duplicate bool   `bson:"-" json:"duplicate,omitempty"`
+	Language  string `bson:"-" json:"lang,omitempty"`
 }
 
 func (i *Interactive) SetJSONAttribs(domain string) {
-	if i != nil && i.Metadata != nil {
-		i.URI = fmt.Sprintf("/%s/%s-%s", "interactives", i.Metadata.HumanReadableSlug, i.Metadata.ResourceID)
+	if i == nil {
+		return
+	}
+	for _, t := range i.Thumbnails {
+		t.URI = fmt.Sprintf("/v1/interactives/%s/thumbnails/%s", i.ID, t.Name())
+	}
+	if i.Metadata != nil {
+		i.URI = i.Metadata.uri()
 		i.URL = fmt.Sprintf("%s%s/%s", domain, i.URI, "embed")
+		if entry := i.Metadata.EntryPoint; entry != "" && entry != DefaultEntryPoint {
+			i.URL = fmt.Sprintf("%s/%s", i.URL, entry)
+		}
 
 		for _, f := range i.HTMLFiles {
 			f.URI = fmt.Sprintf("%s/%s", i.URI, f.URI)
@@ -127,16 +376,87 @@ func (i *Interactive) SetJSONAttribs(domain string) {
 	}
 }
 
+func (i *Metadata) uri() string {
+	return fmt.Sprintf("/%s/%s-%s", "interactives", i.HumanReadableSlug, i.ResourceID)
+}
+
+// Localise puts the interactive's title, label and slug (and so its uris) in the given language. English, or a
+// language the interactive has no translation for, leaves them in English.
+func (i *Interactive) Localise(lang string) {
+	if i == nil || i.Metadata == nil {
+		return
+	}
+	t := i.Metadata.Translations[lang]
+	if t == nil {
+		i.Language = data.English
+		return
+	}
+	i.Language = lang
+	i.Metadata.Title, i.Metadata.Label = t.Title, t.Label
+	if t.HumanReadableSlug == "" {
+		return
+	}
+	i.Metadata.HumanReadableSlug = t.HumanReadableSlug
+	if old := i.URI; old != "" {
+		i.URI = i.Metadata.uri()
+		i.URL = strings.Replace(i.URL, old, i.URI, 1)
+		for _, f := range i.HTMLFiles {
+			f.URI = strings.Replace(f.URI, old, i.URI, 1)
+		}
+	}
+}
+
+// ArchiveHTMLFiles returns the paths of the html files relative to the root of the archive
+func (i *Interactive) ArchiveHTMLFiles() []string {
+	var paths []string
+	for _, f := range i.HTMLFiles {
+		paths = append(paths, strings.TrimPrefix(f.URI, i.URI+"/"))
+	}
+	return paths
+}
+
+// IsDuplicateUpload is true if the interactive already holds an archive with the given hash that has not
+// failed to upload or import - uploading it again would only repeat work already done
+func (i *Interactive) IsDuplicateUpload(sha string) bool {
+	if i == nil || i.SHA == "" || i.SHA != sha {
+		return false
+	}
+	state, _ := ParseState(i.State)
+	switch state {
+	case ArchiveUploadFailed, ArchiveDispatchFailed, ImportFailure:
+		return false
+	}
+	return true
+}
+
 func (i *Interactive) CanPublish() (ok bool) {
 	var state State
 	if i != nil {
 		if state, ok = ParseState(i.State); !ok {
 			return
 		}
+		if i.Content != nil && i.Content.Blocked {
+			return false
+		}
 	}
 	return state == ImportSuccess
 }
 
+// SetImportResult records the outcome reported by the importer, moving the state on accordingly
+func (i *Interactive) SetImportResult(result *Archive) {
+	i.State = ImportFailure.String()
+	if result.ImportSuccessful {
+		i.State = ImportSuccess.String()
+	}
+
+	i.Archive = &Archive{
+		Name:                result.Name,
+		Size:                result.Size,
+		ImportMessage:       result.ImportMessage,
+		UploadRootDirectory: result.UploadRootDirectory,
+	}
+}
+
 type Archive struct {
 	Name                string `bson:"name,omitempty"                   json:"name,omitempty"`
 	Size                int64  `bson:"size_in_bytes,omitempty"          json:"size_in_bytes,omitempty"`
@@ -150,3 +470,86 @@ type HTMLFile struct {
 	Name string `bson:"name,omitempty" json:"name,omitempty"`
 	URI  string `bson:"uri,omitempty" json:"uri,omitempty"`
 }
+
+// ArchiveFile is a single file in an uploaded archive
+type ArchiveFile struct {
+	Path        string `bson:"path"          json:"path"`
+	Size        int64  `bson:"size_in_bytes" json:"size_in_bytes"`
+	ContentType string `bson:"content_type"  json:"content_type"`
+	SHA256      string `bson:"sha256"        json:"sha256"`
+}
+
+// Manifest lists every file in an archive along with the SHA-256 of the archive as a whole
+type Manifest struct {
+	SHA   string         `json:"sha"`
+	Files []*ArchiveFile `json:"files"`
+}
+
+// Manifest returns the files recorded for the interactive's current archive
+func (i *Interactive) Manifest() *Manifest {
+	files := i.Files
+	if files == nil {
+		files = []*ArchiveFile{}
+	}
+	return &Manifest{SHA: i.SHA, Files: files}
+}
+
+// Thumbnail is the thumbnail image of an interactive resized to one width, stored in the upload bucket
+type Thumbnail struct {
+	Width       int    `bson:"width"        json:"width"`
+	Height      int    `bson:"height"       json:"height"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Key         string `bson:"key"          json:"-"`
+	//JSON only
+	URI string `bson:"-" json:"uri,omitempty"`
+}
+
+// Name is the file name the thumbnail is served under
+func (t *Thumbnail) Name() string {
+	return path.Base(t.Key)
+}
+
+// Thumbnail finds the variant served under the name
+func (i *Interactive) Thumbnail(name string) *Thumbnail {
+	for _, t := range i.Thumbnails {
+		if t.Name() == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// ContentScan is the outcome of checking an upload against the content policy. Blocked is set when
+// the policy was strict and something was found, and stops the interactive being published.
+type ContentScan struct {
+	Findings []*ContentFinding `bson:"findings,omitempty" json:"findings,omitempty"`
+	Blocked  bool              `bson:"blocked"            json:"blocked"`
+}
+
+// NewContentScan records the findings for an upload, blocking publication if strict
+func NewContentScan(findings []*ContentFinding, strict bool) *ContentScan {
+	return &ContentScan{Findings: findings, Blocked: strict && len(findings) > 0}
+}
+
+type ContentFinding struct {
+	Entry  string `bson:"entry"            json:"entry"`
+	Rule   string `bson:"rule"             json:"rule"`
+	Detail string `bson:"detail,omitempty" json:"detail,omitempty"`
+}
+
+// OutboxEvent is a kafka event waiting to be relayed to the importer. It is written in the same
+// mongo update as the state change that raised it, so the two can never disagree.
+type OutboxEvent struct {
+	ID            string    `bson:"id"                       json:"id"`
+	InteractiveID string    `bson:"interactive_id"           json:"interactive_id"`
+	FilePath      string    `bson:"path"                     json:"path"`
+	Title         string    `bson:"title"                    json:"title"`
+	CollectionID  string    `bson:"collection_id"            json:"collection_id"`
+	Size          int64     `bson:"size_in_bytes"            json:"size_in_bytes"`
+	HTMLFiles     []string  `bson:"html_files"               json:"html_files"`
+	UploadedBy    string    `bson:"uploaded_by"              json:"uploaded_by"`
+	AttemptID     string    `bson:"attempt_id"               json:"attempt_id"`
+	Attempts      int       `bson:"attempts"                 json:"attempts"`
+	NextAttempt   time.Time `bson:"next_attempt,omitempty"   json:"next_attempt,omitempty"`
+	Created       time.Time `bson:"created"                  json:"created"`
+}
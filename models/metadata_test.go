@@ -1,6 +1,7 @@
 package models_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/ONSdigital/dp-interactives-api/models"
@@ -39,5 +40,359 @@ func TestSetJSONAttribs(t *testing.T) {
 		So(len(i.HTMLFiles), ShouldEqual, 2)
 		So(i.HTMLFiles[0].URI, ShouldEqual, "/interactives/slug-resource_id/one")
 		So(i.HTMLFiles[1].URI, ShouldEqual, "/interactives/slug-resource_id/one/two")
+		So(i.ArchiveHTMLFiles(), ShouldResemble, []string{"one", "one/two"})
+	})
+
+	Convey("When we SetJSONAttribs on an interactive with an entry point", t, func() {
+		i := &models.Interactive{
+			Metadata: &models.Metadata{
+				HumanReadableSlug: "slug",
+				ResourceID:        "resource_id",
+				EntryPoint:        "charts/one.html",
+			},
+		}
+		i.SetJSONAttribs(domain)
+		So(i.URL, ShouldEqual, "domain/interactives/slug-resource_id/embed/charts/one.html")
+
+		i.Metadata.EntryPoint = models.DefaultEntryPoint
+		i.SetJSONAttribs(domain)
+		So(i.URL, ShouldEqual, "domain/interactives/slug-resource_id/embed")
+	})
+
+	Convey("When we SetJSONAttribs on an interactive with thumbnails", t, func() {
+		i := &models.Interactive{
+			ID:         "id",
+			Thumbnails: []*models.Thumbnail{{Width: 640, Key: "thumbnails/id/version/640.jpg"}},
+		}
+		i.SetJSONAttribs(domain)
+		So(i.Thumbnails[0].URI, ShouldEqual, "/v1/interactives/id/thumbnails/640.jpg")
+		So(i.Thumbnail("640.jpg"), ShouldEqual, i.Thumbnails[0])
+		So(i.Thumbnail("320.jpg"), ShouldBeNil)
+	})
+}
+
+func TestResolveEntryPoint(t *testing.T) {
+	htmlFiles := []string{"index.html", "charts/one.html", "charts/two.html"}
+
+	Convey("A requested entry point must be in the archive", t, func() {
+		entry, err := models.ResolveEntryPoint("./charts/two.html", "", htmlFiles)
+		So(err, ShouldBeNil)
+		So(entry, ShouldEqual, "charts/two.html")
+
+		_, err = models.ResolveEntryPoint("missing.html", "", htmlFiles)
+		So(errors.Is(err, models.ErrEntryPointNotFound), ShouldBeTrue)
+	})
+
+	Convey("Without a request the current entry point is kept while it is still in the archive", t, func() {
+		entry, err := models.ResolveEntryPoint("", "charts/one.html", htmlFiles)
+		So(err, ShouldBeNil)
+		So(entry, ShouldEqual, "charts/one.html")
+
+		entry, err = models.ResolveEntryPoint("", "gone.html", htmlFiles)
+		So(err, ShouldBeNil)
+		So(entry, ShouldEqual, models.DefaultEntryPoint)
+	})
+
+	Convey("Without index.html there is no default", t, func() {
+		entry, err := models.ResolveEntryPoint("", "", []string{"charts/one.html"})
+		So(err, ShouldBeNil)
+		So(entry, ShouldEqual, "")
+	})
+}
+
+func TestCanPublish(t *testing.T) {
+	Convey("Given an interactive that imported successfully", t, func() {
+		i := &models.Interactive{State: models.ImportSuccess.String()}
+
+		Convey("Then it can be published with no content findings", func() {
+			i.Content = models.NewContentScan(nil, true)
+			So(i.CanPublish(), ShouldBeTrue)
+		})
+
+		Convey("Then it can be published with findings if the policy was not strict", func() {
+			i.Content = models.NewContentScan([]*models.ContentFinding{{Entry: "index.html", Rule: "eval"}}, false)
+			So(i.CanPublish(), ShouldBeTrue)
+		})
+
+		Convey("Then it cannot be published with findings under a strict policy", func() {
+			i.Content = models.NewContentScan([]*models.ContentFinding{{Entry: "index.html", Rule: "eval"}}, true)
+			So(i.CanPublish(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given an interactive that has not imported", t, func() {
+		i := &models.Interactive{State: models.ArchiveDispatchedToImporter.String()}
+		So(i.CanPublish(), ShouldBeFalse)
+	})
+}
+
+func TestIsDuplicateUpload(t *testing.T) {
+	Convey("Given an interactive holding an archive", t, func() {
+		i := &models.Interactive{SHA: "abc", State: models.ArchiveDispatchedToImporter.String()}
+
+		Convey("Then the same archive is a duplicate", func() {
+			So(i.IsDuplicateUpload("abc"), ShouldBeTrue)
+		})
+
+		Convey("Then a different archive is not", func() {
+			So(i.IsDuplicateUpload("def"), ShouldBeFalse)
+		})
+
+		Convey("Then the same archive is not a duplicate if it failed to import", func() {
+			i.State = models.ImportFailure.String()
+			So(i.IsDuplicateUpload("abc"), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given no interactive or one without a hash", t, func() {
+		var none *models.Interactive
+		So(none.IsDuplicateUpload("abc"), ShouldBeFalse)
+		So((&models.Interactive{}).IsDuplicateUpload(""), ShouldBeFalse)
+	})
+}
+
+func TestMetadataUpdateSlugHistory(t *testing.T) {
+	slugGen := func(label string) string { return label + "-slug" }
+
+	Convey("Given metadata with a slug", t, func() {
+		m := &models.Metadata{Label: "one", HumanReadableSlug: "one-slug"}
+
+		Convey("Then changing the label keeps the old slug", func() {
+			m = m.Update(&models.Metadata{Label: "two"}, slugGen)
+			m = m.Update(&models.Metadata{Label: "three"}, slugGen)
+			So(m.HumanReadableSlug, ShouldEqual, "three-slug")
+			So(m.PreviousSlugs, ShouldResemble, []string{"one-slug", "two-slug"})
+			So(m.HadSlug("one-slug"), ShouldBeTrue)
+			So(m.HadSlug("three-slug"), ShouldBeFalse)
+		})
+
+		Convey("Then going back to an old slug takes it out of the history", func() {
+			m = m.Update(&models.Metadata{Label: "two"}, slugGen)
+			m = m.Update(&models.Metadata{Label: "one"}, slugGen)
+			So(m.HumanReadableSlug, ShouldEqual, "one-slug")
+			So(m.PreviousSlugs, ShouldResemble, []string{"two-slug"})
+		})
+
+		Convey("Then the same label leaves the history alone", func() {
+			m = m.Update(&models.Metadata{Label: "one"}, slugGen)
+			So(m.PreviousSlugs, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestMetadataUpdateManualSlug(t *testing.T) {
+	slugGen := func(label string) string { return label + "-slug" }
+
+	Convey("Given metadata with a slug", t, func() {
+		m := &models.Metadata{Label: "one", HumanReadableSlug: "one-slug"}
+
+		Convey("Then a slug given by hand is used instead of the generated one", func() {
+			m = m.Update(&models.Metadata{Label: "two", HumanReadableSlug: "by-hand"}, slugGen)
+			So(m.Label, ShouldEqual, "two")
+			So(m.HumanReadableSlug, ShouldEqual, "by-hand")
+			So(m.PreviousSlugs, ShouldResemble, []string{"one-slug"})
+		})
+
+		Convey("Then a slug can be given without changing the label", func() {
+			m = m.Update(&models.Metadata{HumanReadableSlug: "by-hand"}, slugGen)
+			So(m.Label, ShouldEqual, "one")
+			So(m.HumanReadableSlug, ShouldEqual, "by-hand")
+
+			Convey("And it is kept until the label changes", func() {
+				m = m.Update(&models.Metadata{Label: "one", Title: "title"}, slugGen)
+				So(m.HumanReadableSlug, ShouldEqual, "by-hand")
+				m = m.Update(&models.Metadata{Label: "two"}, slugGen)
+				So(m.HumanReadableSlug, ShouldEqual, "two-slug")
+			})
+		})
+	})
+}
+
+func TestMetadataUpdateTranslations(t *testing.T) {
+	slugGen := func(label string) string { return label + "-slug" }
+
+	Convey("Given metadata in English", t, func() {
+		m := &models.Metadata{Label: "one", HumanReadableSlug: "one-slug"}
+
+		Convey("Then a translation gets a slug in its own language", func() {
+			m = m.Update(&models.Metadata{Translations: map[string]*models.Translation{
+				"cy": {Title: "Twf yr Economi", Label: "Twf yr Economi"},
+			}}, slugGen)
+			So(m.HumanReadableSlug, ShouldEqual, "one-slug")
+			So(m.Translations["cy"], ShouldResemble, &models.Translation{Title: "Twf yr Economi", Label: "Twf yr Economi", HumanReadableSlug: "twf-economi"})
+
+			Convey("And a slug given by hand is kept until the label changes", func() {
+				m = m.Update(&models.Metadata{Translations: map[string]*models.Translation{"cy": {HumanReadableSlug: "twf"}}}, slugGen)
+				So(m.Translations["cy"].HumanReadableSlug, ShouldEqual, "twf")
+				m = m.Update(&models.Metadata{Translations: map[string]*models.Translation{"cy": {Label: "Twf yr Economi"}}}, slugGen)
+				So(m.Translations["cy"].HumanReadableSlug, ShouldEqual, "twf")
+				m = m.Update(&models.Metadata{Translations: map[string]*models.Translation{"cy": {Label: "Y Gyllideb"}}}, slugGen)
+				So(m.Translations["cy"].HumanReadableSlug, ShouldEqual, "gyllideb")
+				So(m.Translations["cy"].Title, ShouldEqual, "Twf yr Economi")
+				So(m.Translations["cy"].PreviousSlugs, ShouldResemble, []string{"twf-economi", "twf"})
+				So(m.Translations["cy"].HadSlug("twf"), ShouldBeTrue)
+				So(m.HadSlug("twf"), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestLocalise(t *testing.T) {
+	Convey("Given an interactive with a Welsh translation", t, func() {
+		i := &models.Interactive{
+			Metadata: &models.Metadata{
+				Title:             "Economic growth",
+				Label:             "growth",
+				HumanReadableSlug: "economic-growth",
+				ResourceID:        "abcd1234",
+				Translations: map[string]*models.Translation{
+					"cy": {Title: "Twf economaidd", Label: "twf", HumanReadableSlug: "twf-economaidd"},
+				},
+			},
+			HTMLFiles: []*models.HTMLFile{{Name: "index.html", URI: "index.html"}},
+		}
+		i.SetJSONAttribs(domain)
+
+		Convey("Then in Welsh its metadata and uris are Welsh", func() {
+			i.Localise("cy")
+			So(i.Language, ShouldEqual, "cy")
+			So(i.Metadata.Title, ShouldEqual, "Twf economaidd")
+			So(i.Metadata.Label, ShouldEqual, "twf")
+			So(i.URI, ShouldEqual, "/interactives/twf-economaidd-abcd1234")
+			So(i.URL, ShouldEqual, domain+"/interactives/twf-economaidd-abcd1234/embed")
+			So(i.HTMLFiles[0].URI, ShouldEqual, "/interactives/twf-economaidd-abcd1234/index.html")
+		})
+
+		Convey("Then in English it is left as it is", func() {
+			i.Localise("en")
+			So(i.Language, ShouldEqual, "en")
+			So(i.Metadata.Title, ShouldEqual, "Economic growth")
+			So(i.URI, ShouldEqual, "/interactives/economic-growth-abcd1234")
+		})
+	})
+}
+
+func TestMetadataUpdateEditorialFields(t *testing.T) {
+	slugGen := func(label string) string { return label }
+
+	Convey("Given metadata with editorial fields", t, func() {
+		m := &models.Metadata{
+			Summary:       "summary",
+			Keywords:      []string{"gdp", "growth"},
+			Topics:        []string{"economy"},
+			RelatedLink:   &models.Link{Title: "GDP", URI: "/datasets/gdp"},
+			Contact:       &models.Contact{Name: "Economic statistics", Email: "gdp@ons.gov.uk"},
+			Accessibility: "A line chart of GDP growth",
+			Thumbnail:     "/thumbnails/gdp.png",
+		}
+
+		Convey("Then fields left out of an update are kept", func() {
+			m = m.Update(&models.Metadata{Title: "title"}, slugGen)
+			So(m.Summary, ShouldEqual, "summary")
+			So(m.Keywords, ShouldResemble, []string{"gdp", "growth"})
+			So(m.Topics, ShouldResemble, []string{"economy"})
+			So(m.RelatedLink.URI, ShouldEqual, "/datasets/gdp")
+			So(m.Contact.Email, ShouldEqual, "gdp@ons.gov.uk")
+			So(m.Accessibility, ShouldEqual, "A line chart of GDP growth")
+			So(m.Thumbnail, ShouldEqual, "/thumbnails/gdp.png")
+		})
+
+		Convey("Then fields in an update replace them", func() {
+			m = m.Update(&models.Metadata{
+				Summary:       "new summary",
+				Keywords:      []string{"inflation"},
+				Topics:        []string{},
+				RelatedLink:   &models.Link{URI: "/releases/gdp"},
+				Contact:       &models.Contact{Name: "Prices", Email: "cpi@ons.gov.uk"},
+				Accessibility: "A bar chart",
+				Thumbnail:     "/thumbnails/cpi.png",
+			}, slugGen)
+			So(m.Summary, ShouldEqual, "new summary")
+			So(m.Keywords, ShouldResemble, []string{"inflation"})
+			So(m.Topics, ShouldBeEmpty)
+			So(m.RelatedLink, ShouldResemble, &models.Link{URI: "/releases/gdp"})
+			So(m.Contact, ShouldResemble, &models.Contact{Name: "Prices", Email: "cpi@ons.gov.uk"})
+			So(m.Accessibility, ShouldEqual, "A bar chart")
+			So(m.Thumbnail, ShouldEqual, "/thumbnails/cpi.png")
+		})
+	})
+}
+
+func TestMetadataMerge(t *testing.T) {
+	Convey("Given metadata with a translation", t, func() {
+		m := &models.Metadata{
+			Title:             "title",
+			Label:             "label",
+			HumanReadableSlug: "label",
+			ResourceID:        "abcd1234",
+			PreviousSlugs:     []string{"old"},
+			CollectionID:      "collection",
+			Summary:           "summary",
+			Translations:      map[string]*models.Translation{"cy": {Title: "teitl", Label: "label", HumanReadableSlug: "label-cy"}},
+		}
+
+		Convey("When merged with patched metadata", func() {
+			merged := m.Merge(&models.Metadata{
+				Title:             "new title",
+				Label:             "newlabel",
+				HumanReadableSlug: "new-slug",
+				ResourceID:        "other",
+				Translations:      map[string]*models.Translation{"cy": {Title: "teitl newydd", Label: "newydd"}},
+			})
+
+			Convey("Then fields left out are cleared", func() {
+				So(merged.Title, ShouldEqual, "new title")
+				So(merged.CollectionID, ShouldBeEmpty)
+				So(merged.Summary, ShouldBeEmpty)
+			})
+
+			Convey("Then the resource id, labels and slugs are kept for Update", func() {
+				So(merged.ResourceID, ShouldEqual, "abcd1234")
+				So(merged.PreviousSlugs, ShouldResemble, []string{"old"})
+				So(merged.Label, ShouldEqual, "label")
+				So(merged.HumanReadableSlug, ShouldEqual, "label")
+				So(merged.Translations["cy"], ShouldResemble, &models.Translation{Title: "teitl newydd", Label: "label", HumanReadableSlug: "label-cy"})
+			})
+
+			Convey("Then the original is unchanged", func() {
+				So(m.Title, ShouldEqual, "title")
+				So(m.Translations["cy"].Title, ShouldEqual, "teitl")
+			})
+		})
+
+		Convey("When the translation is removed by the patch", func() {
+			merged := m.Merge(&models.Metadata{Title: "title"})
+			So(merged.Translations, ShouldBeNil)
+		})
+	})
+}
+
+func TestMetadataClear(t *testing.T) {
+	Convey("Given metadata", t, func() {
+		m := &models.Metadata{
+			Title:             "title",
+			Label:             "label",
+			HumanReadableSlug: "label",
+			ResourceID:        "abcd1234",
+			CollectionID:      "collection",
+			Summary:           "summary",
+			Keywords:          []string{"gdp"},
+		}
+
+		Convey("When fields are cleared by their json names", func() {
+			cleared := m.Clear([]string{"collection_id", "keywords", "slug", "resource_id"})
+
+			Convey("Then they are emptied, other than the resource id and slug", func() {
+				So(cleared.CollectionID, ShouldBeEmpty)
+				So(cleared.Keywords, ShouldBeNil)
+				So(cleared.Summary, ShouldEqual, "summary")
+				So(cleared.HumanReadableSlug, ShouldEqual, "label")
+				So(cleared.ResourceID, ShouldEqual, "abcd1234")
+			})
+
+			Convey("Then the original is unchanged", func() {
+				So(m.CollectionID, ShouldEqual, "collection")
+			})
+		})
 	})
 }
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// resourceIDRegEx matches the resource ids generated for interactives (see data.GenerateResourceId)
+var resourceIDRegEx = regexp.MustCompile("^[A-Za-z0-9]{8}$")
+
+// Resolution is the interactive a public path refers to. Redirect is set when the path is not the
+// interactive's canonical one (e.g. it has an old slug), and callers should send users to CanonicalURI.
+type Resolution struct {
+	Interactive  *Interactive `json:"interactive"`
+	CanonicalURI string       `json:"canonical_uri"`
+	Redirect     bool         `json:"redirect"`
+}
+
+// PublicPathSegment is the last segment of a public interactive uri, ignoring a leading /interactives/
+func PublicPathSegment(p string) string {
+	return strings.Trim(strings.TrimPrefix(strings.TrimPrefix(p, "/"), "interactives/"), "/")
+}
+
+// ParsePublicPath splits the last segment of a public interactive uri, {slug}-{resource_id}, into its parts.
+// The slug may be missing.
+func ParsePublicPath(p string) (slug, resourceID string, ok bool) {
+	p = PublicPathSegment(p)
+	if i := strings.LastIndex(p, "-"); i >= 0 {
+		slug, resourceID = p[:i], p[i+1:]
+	} else {
+		resourceID = p
+	}
+	if !resourceIDRegEx.MatchString(resourceID) {
+		return "", "", false
+	}
+	return slug, resourceID, true
+}
+
+// Resolve works out whether a path with the given slug and resource id (either of which may be missing)
+// is canonical for the interactive. A translated slug, or one a translation had before, resolves to the interactive
+// in that language.
+func (i *Interactive) Resolve(slug, resourceID string) *Resolution {
+	if i.Metadata != nil && slug != "" {
+		if lang, ok := i.Metadata.translatedSlug(slug); ok {
+			i.Localise(lang)
+		}
+	}
+	r := &Resolution{Interactive: i, CanonicalURI: i.URI}
+	r.Redirect = i.Metadata == nil || slug != i.Metadata.HumanReadableSlug || resourceID != i.Metadata.ResourceID
+	return r
+}
+
+// translatedSlug finds the language the slug is, or was, used in. Slugs in use win over old ones, and an old
+// translated slug that was also used in english is left in english.
+func (i *Metadata) translatedSlug(slug string) (string, bool) {
+	for lang, t := range i.Translations {
+		if t != nil && t.HumanReadableSlug == slug {
+			return lang, true
+		}
+	}
+	if slug == i.HumanReadableSlug || i.HadSlug(slug) {
+		return "", false
+	}
+	for lang, t := range i.Translations {
+		if t != nil && t.HadSlug(slug) {
+			return lang, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParsePublicPath(t *testing.T) {
+	Convey("Public paths are split into slug and resource id", t, func() {
+		for path, expected := range map[string][2]string{
+			"gdp-growth-abcd1234":                {"gdp-growth", "abcd1234"},
+			"/interactives/gdp-growth-abcd1234/": {"gdp-growth", "abcd1234"},
+			"abcd1234":                           {"", "abcd1234"},
+			"-abcd1234":                          {"", "abcd1234"},
+		} {
+			slug, resourceID, ok := models.ParsePublicPath(path)
+			So(ok, ShouldBeTrue)
+			So(slug, ShouldEqual, expected[0])
+			So(resourceID, ShouldEqual, expected[1])
+		}
+	})
+
+	Convey("Paths without a valid resource id are rejected", t, func() {
+		for _, path := range []string{"", "gdp-growth", "gdp-growth-abcd123", "gdp-growth-abcd_123"} {
+			_, _, ok := models.ParsePublicPath(path)
+			So(ok, ShouldBeFalse)
+		}
+	})
+}
+
+func TestResolve(t *testing.T) {
+	Convey("Given an interactive", t, func() {
+		i := &models.Interactive{Metadata: &models.Metadata{HumanReadableSlug: "gdp-growth", ResourceID: "abcd1234"}}
+		i.SetJSONAttribs(domain)
+
+		Convey("Then its current slug and resource id are canonical", func() {
+			r := i.Resolve("gdp-growth", "abcd1234")
+			So(r.Redirect, ShouldBeFalse)
+			So(r.CanonicalURI, ShouldEqual, "/interactives/gdp-growth-abcd1234")
+		})
+
+		Convey("Then anything else should be redirected", func() {
+			So(i.Resolve("gdp", "abcd1234").Redirect, ShouldBeTrue)
+			So(i.Resolve("", "abcd1234").Redirect, ShouldBeTrue)
+			So(i.Resolve("gdp-growth", "").Redirect, ShouldBeTrue)
+		})
+	})
+}
+
+func TestResolveTranslation(t *testing.T) {
+	Convey("Given an interactive with a Welsh translation", t, func() {
+		i := &models.Interactive{Metadata: &models.Metadata{
+			HumanReadableSlug: "gdp-growth",
+			ResourceID:        "abcd1234",
+			Translations:      map[string]*models.Translation{"cy": {Title: "Twf CMC", Label: "twf", HumanReadableSlug: "twf-cmc", PreviousSlugs: []string{"twf"}}},
+		}}
+		i.SetJSONAttribs(domain)
+
+		Convey("Then its Welsh slug is canonical in Welsh", func() {
+			r := i.Resolve("twf-cmc", "abcd1234")
+			So(r.Redirect, ShouldBeFalse)
+			So(r.CanonicalURI, ShouldEqual, "/interactives/twf-cmc-abcd1234")
+			So(r.Interactive.Language, ShouldEqual, "cy")
+		})
+
+		Convey("Then its Welsh slug alone redirects to the Welsh uri", func() {
+			r := i.Resolve("twf-cmc", "")
+			So(r.Redirect, ShouldBeTrue)
+			So(r.CanonicalURI, ShouldEqual, "/interactives/twf-cmc-abcd1234")
+		})
+
+		Convey("Then an old Welsh slug redirects to the Welsh uri", func() {
+			r := i.Resolve("twf", "abcd1234")
+			So(r.Redirect, ShouldBeTrue)
+			So(r.CanonicalURI, ShouldEqual, "/interactives/twf-cmc-abcd1234")
+			So(r.Interactive.Language, ShouldEqual, "cy")
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	dpMongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// UpsertDeadLetter stores an undeliverable event, keyed by event id so recording it twice is harmless
+func (m *Mongo) UpsertDeadLetter(ctx context.Context, d *models.DeadLetter) error {
+	_, err := m.Connection.Collection(m.ActualCollectionName(config.DeadLetterCollection)).
+		UpsertById(ctx, d.ID, bson.M{
+			"$set": bson.M{
+				"interactive_id": d.InteractiveID,
+				"event":          d.Event,
+				"error":          d.Error,
+				"attempts":       d.Attempts,
+			},
+			"$currentDate": bson.M{
+				"last_updated": true,
+			},
+			"$setOnInsert": bson.M{
+				"created": time.Now(),
+			},
+		})
+	return err
+}
+
+// ListDeadLetters returns a page of dead letters, most recent first, along with the total count
+func (m *Mongo) ListDeadLetters(ctx context.Context, offset, limit int) ([]*models.DeadLetter, int, error) {
+	values := make([]*models.DeadLetter, 0)
+	total, err := m.Connection.Collection(m.ActualCollectionName(config.DeadLetterCollection)).
+		Find(ctx, bson.M{}, &values,
+			dpMongoDriver.Sort(bson.M{"created": -1}),
+			dpMongoDriver.Offset(offset),
+			dpMongoDriver.Limit(limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	return values, total, nil
+}
+
+// GetDeadLetter retrieves a dead letter by its (event) id
+func (m *Mongo) GetDeadLetter(ctx context.Context, id string) (*models.DeadLetter, error) {
+	var d *models.DeadLetter
+	err := m.Connection.Collection(m.ActualCollectionName(config.DeadLetterCollection)).
+		FindOne(ctx, bson.M{"_id": id}, &d)
+	if err != nil {
+		if errors.Is(err, dpMongoDriver.ErrNoDocumentFound) {
+			return nil, ErrNoRecordFound
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteDeadLetter removes a dead letter once replayed or discarded
+func (m *Mongo) DeleteDeadLetter(ctx context.Context, id string) error {
+	res, err := m.Connection.Collection(m.ActualCollectionName(config.DeadLetterCollection)).
+		DeleteById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return ErrNoRecordFound
+	}
+	return nil
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/interactives"
 	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/internal/data"
 
 	"github.com/ONSdigital/dp-interactives-api/models"
 	dpMongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
@@ -15,7 +18,8 @@ import (
 )
 
 const (
-	State           string = "State"
+	State    string = "State"
+	Dispatch string = "Dispatch"
 )
 
 // GetInteractive retrieves an interactive by its id
@@ -35,12 +39,87 @@ func (m *Mongo) GetInteractive(ctx context.Context, id string) (*models.Interact
 	return interactive, nil
 }
 
+// GetInteractiveBySHA retrieves the most recently updated active interactive whose archive has the given hash
+func (m *Mongo) GetInteractiveBySHA(ctx context.Context, sha string) (*models.Interactive, error) {
+	var interactive *models.Interactive
+	err := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).
+		FindOne(ctx, bson.M{"sha": sha, "active": true}, &interactive, dpMongoDriver.Sort(bson.M{"last_updated": -1}))
+	if err != nil {
+		if errors.Is(err, dpMongoDriver.ErrNoDocumentFound) {
+			return nil, ErrNoRecordFound
+		}
+		return nil, err
+	}
+
+	interactive.SetJSONAttribs(m.PreviewRootURL)
+
+	return interactive, nil
+}
+
+// GetInteractiveByResourceID retrieves the active interactive with the given resource id
+func (m *Mongo) GetInteractiveByResourceID(ctx context.Context, resourceID string) (*models.Interactive, error) {
+	var interactive *models.Interactive
+	err := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).
+		FindOne(ctx, bson.M{"metadata.resource_id": resourceID, "active": true}, &interactive)
+	if err != nil {
+		if errors.Is(err, dpMongoDriver.ErrNoDocumentFound) {
+			return nil, ErrNoRecordFound
+		}
+		return nil, err
+	}
+
+	interactive.SetJSONAttribs(m.PreviewRootURL)
+
+	return interactive, nil
+}
+
+// GetInteractiveBySlug retrieves the active interactive with the given slug (unique in English), or else the most
+// recently updated one with it in a translation. If none has it now, the one that most recently had it before is
+// returned. Only published interactives are considered if publishedOnly is set.
+func (m *Mongo) GetInteractiveBySlug(ctx context.Context, slug string, publishedOnly bool) (*models.Interactive, error) {
+	var interactive *models.Interactive
+	collection := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection))
+	for _, field := range slugFields() {
+		filter := bson.M{field: slug, "active": true}
+		if publishedOnly {
+			filter["published"] = true
+		}
+		err := collection.FindOne(ctx, filter, &interactive, dpMongoDriver.Sort(bson.M{"last_updated": -1}))
+		if err == nil {
+			interactive.SetJSONAttribs(m.PreviewRootURL)
+			return interactive, nil
+		}
+		if !errors.Is(err, dpMongoDriver.ErrNoDocumentFound) {
+			return nil, err
+		}
+	}
+	return nil, ErrNoRecordFound
+}
+
+// slugFields are the fields a slug is looked up in, those in use (english first) before those used before
+func slugFields() []string {
+	fields := []string{"metadata.slug"}
+	for _, lang := range data.Languages {
+		if lang != data.English {
+			fields = append(fields, fmt.Sprintf("metadata.translations.%s.slug", lang))
+		}
+	}
+	fields = append(fields, "metadata.previous_slugs")
+	for _, lang := range data.Languages {
+		if lang != data.English {
+			fields = append(fields, fmt.Sprintf("metadata.translations.%s.previous_slugs", lang))
+		}
+	}
+	return fields
+}
+
+// ListInteractives returns the interactives matching the filter, leaving out their (potentially large) file manifests
 func (m *Mongo) ListInteractives(ctx context.Context, modelFilter *models.Filter) ([]*models.Interactive, error) {
 	filter := generateFilter(modelFilter)
 
 	var values []*models.Interactive
 	_, err := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).
-		Find(ctx, filter, &values, dpMongoDriver.Sort(bson.M{"_id": -1}))
+		Find(ctx, filter, &values, dpMongoDriver.Sort(bson.M{"_id": -1}), dpMongoDriver.Projection(bson.M{"files": 0}))
 	if err != nil {
 		return values, err
 	}
@@ -66,7 +145,7 @@ func (m *Mongo) UpsertInteractive(ctx context.Context, id string, i *models.Inte
 
 	_, err = m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).
 		UpsertById(ctx, id, update)
-	return
+	return slugTaken(err)
 }
 
 // PatchInteractive patches an existing interactive
@@ -83,6 +162,8 @@ func (m *Mongo) PatchInteractive(ctx context.Context, attribute interactives.Pat
 		patch = bson.M{"metadata.collection_id": i.Metadata.CollectionID}
 	case interactives.PatchAttribute(State):
 		patch = bson.M{"state": i.State}
+	case interactives.PatchAttribute(Dispatch): // archive + state + outbox event(s) in a single (atomic) write
+		patch = bson.M{"archive": i.Archive, "state": i.State}
 	default:
 		return fmt.Errorf("unsupported attribute %s", attribute)
 	}
@@ -93,7 +174,72 @@ func (m *Mongo) PatchInteractive(ctx context.Context, attribute interactives.Pat
 			"last_updated": true,
 		},
 	}
+	if attribute == interactives.PatchAttribute(Dispatch) {
+		update["$push"] = bson.M{"outbox": bson.M{"$each": i.Outbox}}
+	}
 
 	_, err := m.Connection.Collection(collection).UpdateById(ctx, i.ID, update)
-	return err
+	return slugTaken(err)
+}
+
+// PatchMetadata writes the metadata fields a patch changed from the current metadata (the metadata it was applied
+// to), removing those it cleared, and published if given. Fields the patch left alone are not written, so are
+// kept if they have been changed since the current metadata was read.
+func (m *Mongo) PatchMetadata(ctx context.Context, id string, current, patched *models.Metadata, published *bool) error {
+	set, unset := metadataUpdate(current, patched)
+	if published != nil {
+		set["published"] = *published
+	}
+
+	update := bson.M{
+		"$currentDate": bson.M{
+			"last_updated": true,
+		},
+	}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	_, err := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).UpdateById(ctx, id, update)
+	return slugTaken(err)
+}
+
+// metadataUpdate sets each metadata field that a patch changed to a value and unsets those it emptied, so that a
+// cleared field is removed from the document rather than relying on omitempty to leave it out
+func metadataUpdate(current, patched *models.Metadata) (set, unset bson.M) {
+	set, unset = bson.M{}, bson.M{}
+	if current == nil {
+		current = &models.Metadata{}
+	}
+	if patched == nil {
+		patched = &models.Metadata{}
+	}
+	was, now := reflect.ValueOf(*current), reflect.ValueOf(*patched)
+	for n := 0; n < now.NumField(); n++ {
+		name := strings.Split(now.Type().Field(n).Tag.Get("bson"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		field := now.Field(n)
+		switch {
+		case isEmpty(field) && isEmpty(was.Field(n)):
+		case isEmpty(field):
+			unset["metadata."+name] = ""
+		case !reflect.DeepEqual(field.Interface(), was.Field(n).Interface()):
+			set["metadata."+name] = field.Interface()
+		}
+	}
+	return set, unset
+}
+
+// isEmpty is true for the values omitempty leaves out
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
 }
@@ -3,20 +3,26 @@ package mongo
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-interactives-api/config"
 	"github.com/ONSdigital/dp-interactives-api/models"
 	mongohealth "github.com/ONSdigital/dp-mongodb/v3/health"
 	mongodriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
 	"go.mongodb.org/mongo-driver/bson"
+	mongoerrors "go.mongodb.org/mongo-driver/mongo"
 	"reflect"
 	"strings"
 )
 
 var (
 	ErrNoRecordFound = errors.New("no record exists")
+	ErrSlugTaken     = errors.New("slug is already used by another interactive")
 )
 
+// slugIndex keeps the slug of each active interactive unique
+const slugIndex = "slug"
+
 type Mongo struct {
 	config.MongoConfig
 	PreviewRootURL string
@@ -35,10 +41,75 @@ func (m *Mongo) Init(ctx context.Context) (err error) {
 	databaseCollectionBuilder := map[mongohealth.Database][]mongohealth.Collection{
 		(mongohealth.Database)(m.Database): {
 			mongohealth.Collection(m.ActualCollectionName(config.MetadataCollection)),
+			mongohealth.Collection(m.ActualCollectionName(config.DeadLetterCollection)),
 		},
 	}
 	m.healthClient = mongohealth.NewClientWithCollections(m.Connection, databaseCollectionBuilder)
 
+	if err = m.dedupeSlugs(ctx); err != nil {
+		return err
+	}
+	return m.createIndexes(ctx)
+}
+
+// dedupeSlugs renames the slugs shared by more than one active interactive, which the unique slug index
+// cannot be built over. The interactive that resolves the slug now (published first, then the most recently
+// updated) keeps it, the others get the first free "<slug>-<n>".
+func (m *Mongo) dedupeSlugs(ctx context.Context) error {
+	collection := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection))
+
+	var duplicates []struct {
+		Slug string   `bson:"_id"`
+		IDs  []string `bson:"ids"`
+	}
+	err := collection.Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"active": true, "metadata.slug": bson.M{"$exists": true}}},
+		bson.M{"$sort": bson.D{{Key: "published", Value: -1}, {Key: "last_updated", Value: -1}}},
+		bson.M{"$group": bson.M{"_id": "$metadata.slug", "ids": bson.M{"$push": "$_id"}}},
+		bson.M{"$match": bson.M{"ids.1": bson.M{"$exists": true}}},
+	}, &duplicates)
+	if err != nil {
+		return fmt.Errorf("error finding duplicate slugs %w", err)
+	}
+
+	for _, duplicate := range duplicates {
+		n := 2
+		for _, id := range duplicate.IDs[1:] {
+			var slug string
+			for ; slug == ""; n++ {
+				candidate := fmt.Sprintf("%s-%d", duplicate.Slug, n)
+				if _, err = m.GetInteractiveBySlug(ctx, candidate, false); errors.Is(err, ErrNoRecordFound) {
+					slug = candidate
+				} else if err != nil {
+					return fmt.Errorf("error checking slug %s %w", candidate, err)
+				}
+			}
+			if _, err = collection.UpdateById(ctx, id, bson.M{"$set": bson.M{"metadata.slug": slug}}); err != nil {
+				return fmt.Errorf("error renaming duplicate slug %s of %s %w", duplicate.Slug, id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// createIndexes adds the indexes the metadata lookups rely on - mongo leaves any that already exist untouched
+func (m *Mongo) createIndexes(ctx context.Context) error {
+	indexes := []bson.M{
+		{"key": bson.D{{Key: "sha", Value: 1}}, "name": "sha"},
+		{
+			"key":                     bson.D{{Key: "metadata.slug", Value: 1}},
+			"name":                    slugIndex,
+			"unique":                  true,
+			"partialFilterExpression": bson.M{"active": true, "metadata.slug": bson.M{"$exists": true}},
+		},
+	}
+	err := m.Connection.RunCommand(ctx, bson.D{
+		{Key: "createIndexes", Value: m.ActualCollectionName(config.MetadataCollection)},
+		{Key: "indexes", Value: indexes},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating indexes %w", err)
+	}
 	return nil
 }
 
@@ -52,6 +123,14 @@ func (m *Mongo) Checker(ctx context.Context, state *healthcheck.CheckState) erro
 	return m.healthClient.Checker(ctx, state)
 }
 
+// slugTaken is ErrSlugTaken if err is a write rejected by the slug index, otherwise err
+func slugTaken(err error) error {
+	if mongoerrors.IsDuplicateKeyError(err) && strings.Contains(err.Error(), "index: "+slugIndex+" ") {
+		return ErrSlugTaken
+	}
+	return err
+}
+
 // Reflect into the metadata structure
 // generate filter string depending on data type
 // string eq value
@@ -81,20 +160,31 @@ func generateFilter(model *models.Filter) bson.M {
 	}
 
 	// else filter using other metadata
-	v := reflect.ValueOf(*(model.Metadata))
+	addMetadataFilters(filter, "metadata.", reflect.ValueOf(*(model.Metadata)))
+	return filter
+}
+
+// addMetadataFilters adds a filter for each field set, descending into nested structs (e.g. contact.email)
+func addMetadataFilters(filter bson.M, prefix string, v reflect.Value) {
 	typeOfS := v.Type()
 
 	for i := 0; i < v.NumField(); i++ {
 		tag := strings.Split(typeOfS.Field(i).Tag.Get("json"), ",")[0]
-		valType := typeOfS.Field(i).Type.Kind()
-		val := v.Field(i).Interface()
+		field := v.Field(i)
 
-		switch valType {
+		switch field.Kind() {
 		case reflect.String:
-			if val != "" {
-				filter["metadata."+tag] = bson.M{"$regex": val, "$options": "i"}
+			if val := field.String(); val != "" {
+				filter[prefix+tag] = bson.M{"$regex": val, "$options": "i"}
+			}
+		case reflect.Slice:
+			if vals, ok := field.Interface().([]string); ok && len(vals) > 0 {
+				filter[prefix+tag] = bson.M{"$in": vals}
+			}
+		case reflect.Ptr:
+			if !field.IsNil() && field.Elem().Kind() == reflect.Struct {
+				addMetadataFilters(filter, prefix+tag+".", field.Elem())
 			}
 		}
 	}
-	return filter
 }
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.mongodb.org/mongo-driver/bson"
+	mongoerrors "go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestGenerateFilter(t *testing.T) {
+	active := bson.M{"$eq": true}
+
+	Convey("Without metadata only active interactives are filtered", t, func() {
+		So(generateFilter(nil), ShouldResemble, bson.M{"active": active})
+	})
+
+	Convey("Text fields are matched case insensitively", t, func() {
+		filter := generateFilter(&models.Filter{Metadata: &models.Metadata{Title: "gdp", Summary: "growth"}})
+		So(filter, ShouldResemble, bson.M{
+			"active":           active,
+			"metadata.title":   bson.M{"$regex": "gdp", "$options": "i"},
+			"metadata.summary": bson.M{"$regex": "growth", "$options": "i"},
+		})
+	})
+
+	Convey("Lists match any of their values", t, func() {
+		filter := generateFilter(&models.Filter{Metadata: &models.Metadata{Keywords: []string{"gdp", "cpi"}, Topics: []string{"economy"}}})
+		So(filter, ShouldResemble, bson.M{
+			"active":            active,
+			"metadata.keywords": bson.M{"$in": []string{"gdp", "cpi"}},
+			"metadata.topics":   bson.M{"$in": []string{"economy"}},
+		})
+	})
+
+	Convey("Nested fields are matched by their path", t, func() {
+		filter := generateFilter(&models.Filter{Metadata: &models.Metadata{
+			Contact:     &models.Contact{Email: "gdp@ons.gov.uk"},
+			RelatedLink: &models.Link{URI: "/datasets/gdp"},
+		}})
+		So(filter, ShouldResemble, bson.M{
+			"active":                    active,
+			"metadata.contact.email":    bson.M{"$regex": "gdp@ons.gov.uk", "$options": "i"},
+			"metadata.related_link.uri": bson.M{"$regex": "/datasets/gdp", "$options": "i"},
+		})
+	})
+
+	Convey("A resource id is matched exactly, ignoring the rest", t, func() {
+		filter := generateFilter(&models.Filter{Metadata: &models.Metadata{ResourceID: "abcd1234", Title: "gdp"}})
+		So(filter, ShouldResemble, bson.M{"active": active, "metadata.resource_id": bson.M{"$eq": "abcd1234"}})
+	})
+}
+
+func TestMetadataUpdate(t *testing.T) {
+	Convey("Given metadata that a patch changed some fields of and cleared others", t, func() {
+		current := &models.Metadata{
+			Title:        "title",
+			Label:        "label",
+			InternalID:   "id",
+			CollectionID: "collection",
+			Keywords:     []string{"gdp"},
+			Contact:      &models.Contact{Name: "name", Email: "a@b.com"},
+		}
+		set, unset := metadataUpdate(current, &models.Metadata{
+			Title:      "new title",
+			Label:      "label",
+			InternalID: "id",
+			Keywords:   []string{},
+			Contact:    &models.Contact{Name: "name", Email: "c@d.com"},
+			Summary:    "summary",
+		})
+
+		Convey("Then only the changed fields with values are set", func() {
+			So(set, ShouldResemble, bson.M{
+				"metadata.title":   "new title",
+				"metadata.contact": &models.Contact{Name: "name", Email: "c@d.com"},
+				"metadata.summary": "summary",
+			})
+		})
+
+		Convey("Then only the fields emptied, including emptied lists, are unset", func() {
+			So(unset, ShouldResemble, bson.M{
+				"metadata.collection_id": "",
+				"metadata.keywords":      "",
+			})
+		})
+	})
+
+	Convey("Given metadata a patch did not change", t, func() {
+		current := &models.Metadata{Title: "title", Keywords: []string{"gdp"}}
+		set, unset := metadataUpdate(current, &models.Metadata{Title: "title", Keywords: []string{"gdp"}})
+
+		Convey("Then nothing is written", func() {
+			So(set, ShouldBeEmpty)
+			So(unset, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestSlugTaken(t *testing.T) {
+	duplicate := func(index string) error {
+		return mongoerrors.WriteException{WriteErrors: mongoerrors.WriteErrors{{
+			Code:    11000,
+			Message: `E11000 duplicate key error collection: interactives.metadata index: ` + index + ` dup key: { metadata.slug: "gdp" }`,
+		}}}
+	}
+
+	Convey("A write refused by the slug index is ErrSlugTaken", t, func() {
+		So(slugTaken(duplicate(slugIndex)), ShouldEqual, ErrSlugTaken)
+	})
+
+	Convey("Other errors are left as they are", t, func() {
+		err := duplicate("metadata.resource_id_1")
+		So(slugTaken(err), ShouldResemble, err)
+		So(slugTaken(nil), ShouldBeNil)
+	})
+}
@@ -0,0 +1,66 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	dpMongoDriver "github.com/ONSdigital/dp-mongodb/v3/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ListOutboxEvents returns the events waiting to be relayed for up to limit interactives with an event due, least
+// recently updated first
+func (m *Mongo) ListOutboxEvents(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+	var values []*models.Interactive
+	_, err := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).
+		Find(ctx, bson.M{"outbox": bson.M{"$elemMatch": bson.M{"$or": bson.A{
+			bson.M{"next_attempt": bson.M{"$exists": false}},
+			bson.M{"next_attempt": bson.M{"$lte": time.Now()}},
+		}}}}, &values,
+			dpMongoDriver.Projection(bson.M{"outbox": 1}),
+			dpMongoDriver.Sort(bson.M{"last_updated": 1}),
+			dpMongoDriver.Limit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*models.OutboxEvent
+	for _, i := range values {
+		events = append(events, i.Outbox...)
+	}
+	return events, nil
+}
+
+// RetryOutboxEvent records a failed delivery attempt, leaving the event in the outbox until its next attempt
+func (m *Mongo) RetryOutboxEvent(ctx context.Context, e *models.OutboxEvent) error {
+	_, err := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection)).
+		Update(ctx, bson.M{"_id": e.InteractiveID, "outbox.id": e.ID}, bson.M{
+			"$inc": bson.M{"outbox.$.attempts": 1},
+			"$set": bson.M{"outbox.$.next_attempt": e.NextAttempt},
+		})
+	return err
+}
+
+// CompleteOutboxEvent removes the event from the outbox and moves the interactive to the given state. The state is
+// only changed while the archive is waiting to be dispatched, so that an import result the importer reported before
+// the event was completed (or for an event sent again) is never overwritten. It is changed before the event is
+// removed, so that if removing it fails the event is sent again and completed then.
+func (m *Mongo) CompleteOutboxEvent(ctx context.Context, e *models.OutboxEvent, state models.State) error {
+	collection := m.Connection.Collection(m.ActualCollectionName(config.MetadataCollection))
+	_, err := collection.Update(ctx, bson.M{"_id": e.InteractiveID, "outbox.id": e.ID, "state": models.ArchiveUploaded.String()}, bson.M{
+		"$set": bson.M{"state": state.String()},
+		"$currentDate": bson.M{
+			"last_updated": true,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.Update(ctx, bson.M{"_id": e.InteractiveID, "outbox.id": e.ID}, bson.M{
+		"$pull": bson.M{"outbox": bson.M{"id": e.ID}},
+	})
+	return err
+}
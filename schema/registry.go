@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Registry stores schema definitions by subject, handing back a unique id for each definition
+type Registry interface {
+	Register(ctx context.Context, subject, definition string) (int, error)
+}
+
+// HTTPRegistry talks to a (confluent compatible) schema registry
+type HTTPRegistry struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPRegistry returns a registry client for the given url
+func NewHTTPRegistry(url string) *HTTPRegistry {
+	return &HTTPRegistry{URL: url, Client: http.DefaultClient}
+}
+
+// Register adds the definition under subject - registering an existing definition returns its current id
+func (r *HTTPRegistry) Register(ctx context.Context, subject, definition string) (int, error) {
+	body, err := json.Marshal(map[string]string{"schema": definition})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/subjects/%s/versions", r.URL, url.PathEscape(subject)), bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("schema registry request error %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("schema registry returned status %d for subject %s", resp.StatusCode, subject)
+	}
+
+	var registered struct {
+		ID int `json:"id"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&registered); err != nil {
+		return 0, fmt.Errorf("cannot decode schema registry response %w", err)
+	}
+	return registered.ID, nil
+}
+
+// MemoryRegistry is an in-process stand-in for a schema registry (local development and tests).
+// Like a real registry it rejects a definition that is not compatible with the subject's latest.
+type MemoryRegistry struct {
+	mu       sync.Mutex
+	ids      map[string]int
+	subjects map[string][]string
+}
+
+// NewMemoryRegistry returns an empty in-memory registry
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{
+		ids:      map[string]int{},
+		subjects: map[string][]string{},
+	}
+}
+
+// Register adds the definition under subject - registering an existing definition returns its current id
+func (r *MemoryRegistry) Register(_ context.Context, subject, definition string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions := r.subjects[subject]
+	for _, d := range versions {
+		if d == definition {
+			return r.ids[d], nil
+		}
+	}
+	if len(versions) > 0 {
+		if err := checkCompatible(versions[len(versions)-1], definition); err != nil {
+			return 0, fmt.Errorf("incompatible schema for subject %s %w", subject, err)
+		}
+	}
+
+	if _, ok := r.ids[definition]; !ok {
+		r.ids[definition] = len(r.ids) + 1
+	}
+	r.subjects[subject] = append(versions, definition)
+	return r.ids[definition], nil
+}
+
+// Versions returns the definitions registered under subject, oldest first
+func (r *MemoryRegistry) Versions(subject string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.subjects[subject]...)
+}
@@ -4,7 +4,7 @@ import (
 	"github.com/ONSdigital/dp-kafka/v3/avro"
 )
 
-var interactiveUploadedEvent = `{
+var interactiveUploadedEventV1 = `{
   "type": "record",
   "name": "interactive-uploaded",
   "fields": [
@@ -15,7 +15,52 @@ var interactiveUploadedEvent = `{
   ]
 }`
 
-// InteractiveUploadedEvent is the Avro schema
-var InteractiveUploadedEvent = &avro.Schema{
-	Definition: interactiveUploadedEvent,
+var interactiveUploadedEventV2 = `{
+  "type": "record",
+  "name": "interactive-uploaded",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "path", "type": "string"},
+    {"name": "title", "type": "string"},
+    {"name": "collection_id", "type": "string"},
+    {"name": "event_id", "type": "string", "default": ""},
+    {"name": "size_in_bytes", "type": "long", "default": 0},
+    {"name": "html_files", "type": {"type": "array", "items": "string"}, "default": []},
+    {"name": "uploaded_by", "type": "string", "default": ""},
+    {"name": "attempt_id", "type": "string", "default": ""}
+  ]
+}`
+
+// InteractiveUploadedEventV1 is the original Avro schema, still understood by older importers
+var InteractiveUploadedEventV1 = &avro.Schema{
+	Definition: interactiveUploadedEventV1,
+}
+
+// InteractiveUploadedEventV2 adds delivery and archive details, all with defaults
+var InteractiveUploadedEventV2 = &avro.Schema{
+	Definition: interactiveUploadedEventV2,
+}
+
+// InteractiveUploadedVersions holds every revision of the InteractiveUploaded schema
+var InteractiveUploadedVersions = NewVersions(InteractiveUploadedEventV1, InteractiveUploadedEventV2)
+
+// InteractiveUploadedEvent is the Avro schema (latest version)
+var InteractiveUploadedEvent = InteractiveUploadedEventV2
+
+var interactiveImportedEvent = `{
+  "type": "record",
+  "name": "interactive-imported",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "path", "type": "string"},
+    {"name": "size_in_bytes", "type": "long"},
+    {"name": "upload_root_directory", "type": "string"},
+    {"name": "import_message", "type": "string"},
+    {"name": "import_successful", "type": "boolean"}
+  ]
+}`
+
+// InteractiveImportedEvent is the Avro schema for results reported back by the importer
+var InteractiveImportedEvent = &avro.Schema{
+	Definition: interactiveImportedEvent,
 }
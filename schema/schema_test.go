@@ -0,0 +1,101 @@
+package schema_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ONSdigital/dp-interactives-api/schema"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInteractiveUploadedVersions(t *testing.T) {
+	Convey("Each revision of the uploaded event is compatible with the previous one", t, func() {
+		So(schema.InteractiveUploadedVersions.Validate(), ShouldBeNil)
+		So(schema.InteractiveUploadedVersions.Latest(), ShouldEqual, 2)
+
+		latest, err := schema.InteractiveUploadedVersions.Get(0)
+		So(err, ShouldBeNil)
+		So(latest, ShouldEqual, schema.InteractiveUploadedEvent)
+	})
+
+	Convey("Versions are read from the kafka header", t, func() {
+		v, err := schema.InteractiveUploadedVersions.FromHeader("")
+		So(err, ShouldBeNil)
+		So(v, ShouldEqual, 1)
+
+		v, err = schema.InteractiveUploadedVersions.FromHeader("2")
+		So(err, ShouldBeNil)
+		So(v, ShouldEqual, 2)
+
+		_, err = schema.InteractiveUploadedVersions.FromHeader("3")
+		So(err, ShouldNotBeNil)
+		_, err = schema.InteractiveUploadedVersions.FromHeader("latest")
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMemoryRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	Convey("Given an in-memory registry with the uploaded event registered", t, func() {
+		registry := schema.NewMemoryRegistry()
+		ids, err := schema.InteractiveUploadedVersions.Register(ctx, registry, "interactives-import-value")
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []int{1, 2})
+		So(registry.Versions("interactives-import-value"), ShouldHaveLength, 2)
+
+		Convey("Registering again returns the same ids", func() {
+			ids, err = schema.InteractiveUploadedVersions.Register(ctx, registry, "interactives-import-value")
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []int{1, 2})
+			So(registry.Versions("interactives-import-value"), ShouldHaveLength, 2)
+		})
+
+		Convey("A revision adding a field without a default is rejected", func() {
+			_, err = registry.Register(ctx, "interactives-import-value", `{
+  "type": "record",
+  "name": "interactive-uploaded",
+  "fields": [
+    {"name": "id", "type": "string"},
+    {"name": "path", "type": "string"},
+    {"name": "title", "type": "string"},
+    {"name": "collection_id", "type": "string"},
+    {"name": "event_id", "type": "string", "default": ""},
+    {"name": "size_in_bytes", "type": "long", "default": 0},
+    {"name": "html_files", "type": {"type": "array", "items": "string"}, "default": []},
+    {"name": "uploaded_by", "type": "string", "default": ""},
+    {"name": "attempt_id", "type": "string", "default": ""},
+    {"name": "checksum", "type": "string"}
+  ]
+}`)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("A revision removing a field is rejected", func() {
+			_, err = registry.Register(ctx, "interactives-import-value", `{
+  "type": "record",
+  "name": "interactive-uploaded",
+  "fields": [
+    {"name": "id", "type": "string"}
+  ]
+}`)
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("Versions that break compatibility fail validation before anything is registered", t, func() {
+		broken := schema.NewVersions(schema.InteractiveUploadedEventV2, schema.InteractiveUploadedEventV1)
+		registry := schema.NewMemoryRegistry()
+		_, err := broken.Register(ctx, registry, "subject")
+		So(err, ShouldNotBeNil)
+		So(registry.Versions("subject"), ShouldBeEmpty)
+	})
+
+	Convey("Different subjects are independent", t, func() {
+		registry := schema.NewMemoryRegistry()
+		_, err := registry.Register(ctx, "a", schema.InteractiveUploadedEventV2.Definition)
+		So(err, ShouldBeNil)
+		_, err = registry.Register(ctx, "b", schema.InteractiveImportedEvent.Definition)
+		So(err, ShouldBeNil)
+	})
+}
@@ -0,0 +1,128 @@
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ONSdigital/dp-kafka/v3/avro"
+)
+
+const (
+	// VersionHeader is the kafka header carrying the schema version a message was written with.
+	// Messages without it were written with version 1.
+	VersionHeader = "avro-schema-version"
+	// IDHeader is the kafka header carrying the registry id of the schema a message was written with
+	IDHeader = "avro-schema-id"
+)
+
+// Versions holds every revision of a record schema, oldest first. A revision may only append
+// fields, each with a default, so consumers still on an older revision can read newer messages.
+type Versions struct {
+	schemas []*avro.Schema
+}
+
+// NewVersions returns the given revisions, version 1 first
+func NewVersions(schemas ...*avro.Schema) *Versions {
+	return &Versions{schemas: schemas}
+}
+
+// Latest returns the most recent version number
+func (v *Versions) Latest() int {
+	return len(v.schemas)
+}
+
+// Get returns the schema for the given version - 0 being the latest
+func (v *Versions) Get(version int) (*avro.Schema, error) {
+	if version == 0 {
+		version = v.Latest()
+	}
+	if version < 1 || version > v.Latest() {
+		return nil, fmt.Errorf("unknown schema version %d", version)
+	}
+	return v.schemas[version-1], nil
+}
+
+// FromHeader returns the version named by a VersionHeader value
+func (v *Versions) FromHeader(header string) (int, error) {
+	if header == "" {
+		return 1, nil
+	}
+	version, err := strconv.Atoi(header)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version header %s %w", header, err)
+	}
+	if _, err = v.Get(version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// Validate checks each revision only appends defaulted fields to the previous one
+func (v *Versions) Validate() error {
+	for i := 1; i < len(v.schemas); i++ {
+		if err := checkCompatible(v.schemas[i-1].Definition, v.schemas[i].Definition); err != nil {
+			return fmt.Errorf("version %d %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Register registers every revision under subject, returning the registry id of each (by version)
+func (v *Versions) Register(ctx context.Context, registry Registry, subject string) ([]int, error) {
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(v.schemas))
+	for i, s := range v.schemas {
+		id, err := registry.Register(ctx, subject, s.Definition)
+		if err != nil {
+			return nil, fmt.Errorf("cannot register version %d of %s %w", i+1, subject, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+type record struct {
+	Name   string  `json:"name"`
+	Fields []field `json:"fields"`
+}
+
+type field struct {
+	Name    string           `json:"name"`
+	Type    json.RawMessage  `json:"type"`
+	Default *json.RawMessage `json:"default"`
+}
+
+// checkCompatible makes sure next keeps every field of prev (same name, same type, same order)
+// and that any new field has a default
+func checkCompatible(prev, next string) error {
+	var p, n record
+	if err := json.Unmarshal([]byte(prev), &p); err != nil {
+		return fmt.Errorf("cannot parse schema %w", err)
+	}
+	if err := json.Unmarshal([]byte(next), &n); err != nil {
+		return fmt.Errorf("cannot parse schema %w", err)
+	}
+
+	if p.Name != n.Name {
+		return fmt.Errorf("record renamed from %s to %s", p.Name, n.Name)
+	}
+	if len(n.Fields) < len(p.Fields) {
+		return fmt.Errorf("fields removed")
+	}
+	for i, f := range p.Fields {
+		if n.Fields[i].Name != f.Name || string(n.Fields[i].Type) != string(f.Type) {
+			return fmt.Errorf("field %s changed", f.Name)
+		}
+	}
+	for _, f := range n.Fields[len(p.Fields):] {
+		if f.Default == nil {
+			return fmt.Errorf("new field %s has no default", f.Name)
+		}
+	}
+	return nil
+}
@@ -8,11 +8,15 @@ import (
 	"github.com/ONSdigital/dp-api-clients-go/v2/files"
 	"github.com/ONSdigital/dp-api-clients-go/v2/health"
 	"github.com/ONSdigital/dp-authorisation/v2/authorisation"
+	"github.com/ONSdigital/dp-authorisation/v2/permissions"
 	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-interactives-api/api"
 	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/internal/content"
 	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
 	"github.com/ONSdigital/dp-interactives-api/mongo"
+	"github.com/ONSdigital/dp-interactives-api/schema"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
 	dphttp "github.com/ONSdigital/dp-net/http"
 	"github.com/ONSdigital/dp-net/v2/responder"
@@ -20,12 +24,14 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 )
 
 type ExternalServiceList struct {
 	MongoDB       bool
 	HealthCheck   bool
 	KafkaProducer bool
+	KafkaConsumer bool
 	S3Client      bool
 	FilesService  bool
 	Init          Initialiser
@@ -63,6 +69,16 @@ func (e *ExternalServiceList) GetKafkaProducer(ctx context.Context, cfg *config.
 	return producer, nil
 }
 
+// GetKafkaConsumer returns a kafka consumer group
+func (e *ExternalServiceList) GetKafkaConsumer(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+	consumer, err := e.Init.DoGetKafkaConsumer(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	e.KafkaConsumer = true
+	return consumer, nil
+}
+
 // GetS3Uploaded creates a S3 client and sets the S3Uploaded flag to true
 func (e *ExternalServiceList) GetS3Client(ctx context.Context, cfg *config.Config) (api.S3Interface, error) {
 	s3, err := e.Init.DoGetS3Client(ctx, cfg)
@@ -73,6 +89,11 @@ func (e *ExternalServiceList) GetS3Client(ctx context.Context, cfg *config.Confi
 	return s3, nil
 }
 
+// GetContentStore returns the store imported interactives are served from, or nil if no bucket is configured
+func (e *ExternalServiceList) GetContentStore(ctx context.Context, cfg *config.Config) (api.ContentStore, error) {
+	return e.Init.DoGetContentStore(ctx, cfg)
+}
+
 // GetHealthClient returns a healthclient for the provided URL
 func (e *ExternalServiceList) GetHealthClient(name, url string) *health.Client {
 	return e.Init.DoGetHealthClient(name, url)
@@ -93,6 +114,11 @@ func (e *ExternalServiceList) GetAuthorisationMiddleware(ctx context.Context, au
 	return e.Init.DoGetAuthorisationMiddleware(ctx, authorisationConfig)
 }
 
+// GetPermissionsChecker creates a checker of the caller's permissions, for handlers that behave differently by permission
+func (e *ExternalServiceList) GetPermissionsChecker(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.PermissionsChecker, error) {
+	return e.Init.DoGetPermissionsChecker(ctx, authorisationConfig)
+}
+
 // GetGenerators returns all the attribute generators necessary - i.e. uuid, resourceId and slug
 func (e *ExternalServiceList) GetGenerators() (data.Generator, data.Generator, data.Generator) {
 	return e.Init.DoGetGenerators()
@@ -159,6 +185,34 @@ func (e *Init) DoGetKafkaProducer(ctx context.Context, cfg *config.Config) (kafk
 	return kafka.NewProducer(ctx, pConfig)
 }
 
+// DoGetKafkaConsumer creates a kafka consumer group for the import results topic, with a worker pool sized by config
+func (e *Init) DoGetKafkaConsumer(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+	cgConfig := &kafka.ConsumerGroupConfig{
+		KafkaVersion: &cfg.KafkaVersion,
+		NumWorkers:   &cfg.KafkaConsumerWorkers,
+		Topic:        cfg.InteractivesReadTopic,
+		GroupName:    cfg.InteractivesGroup,
+		BrokerAddrs:  cfg.Brokers,
+	}
+	if cfg.MinBrokers > 0 {
+		cgConfig.MinBrokersHealthy = &cfg.MinBrokers
+	}
+	if cfg.KafkaSecProtocol == "TLS" {
+		cgConfig.SecurityConfig = kafka.GetSecurityConfig(
+			cfg.KafkaSecCACerts,
+			cfg.KafkaSecClientCert,
+			cfg.KafkaSecClientKey,
+			cfg.KafkaSecSkipVerify,
+		)
+	}
+	return kafka.NewConsumerGroup(ctx, cgConfig)
+}
+
+// GetSchemaRegistry returns the registry the kafka message schemas are registered with
+func (e *ExternalServiceList) GetSchemaRegistry(ctx context.Context, cfg *config.Config) (schema.Registry, error) {
+	return e.Init.DoGetSchemaRegistry(ctx, cfg)
+}
+
 // DoGetS3Uploaded returns a S3Client
 func (e *Init) DoGetS3Client(ctx context.Context, cfg *config.Config) (api.S3Interface, error) {
 	if cfg.AwsEndpoint != "" {
@@ -184,6 +238,26 @@ func (e *Init) DoGetS3Client(ctx context.Context, cfg *config.Config) (api.S3Int
 	return s3Client, nil
 }
 
+// DoGetContentStore returns a store reading from the content bucket, or nil if it is not configured
+func (e *Init) DoGetContentStore(ctx context.Context, cfg *config.Config) (api.ContentStore, error) {
+	if cfg.ContentBucketName == "" {
+		return nil, nil
+	}
+
+	awsConfig := &aws.Config{Region: aws.String(cfg.AwsRegion)}
+	if cfg.AwsEndpoint != "" {
+		//for local development only - set env var to initialise
+		awsConfig.Endpoint = aws.String(cfg.AwsEndpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+		awsConfig.Credentials = credentials.NewStaticCredentials("na", "na", "")
+	}
+	s, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return content.NewStore(s3.New(s), cfg.ContentBucketName), nil
+}
+
 // DoGetHealthClient creates a new Health Client for the provided name and url
 func (e *Init) DoGetHealthClient(name, url string) *health.Client {
 	return health.NewClient(name, url)
@@ -204,6 +278,11 @@ func (e *Init) DoGetAuthorisationMiddleware(ctx context.Context, authorisationCo
 	return authorisation.NewFeatureFlaggedMiddleware(ctx, authorisationConfig, nil)
 }
 
+// DoGetPermissionsChecker creates a permissions checker, caching permissions from the permissions api
+func (e *Init) DoGetPermissionsChecker(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.PermissionsChecker, error) {
+	return permissions.NewChecker(ctx, authorisationConfig.PermissionsAPIURL, authorisationConfig.PermissionsCacheUpdateInterval, authorisationConfig.PermissionsMaxCacheTime), nil
+}
+
 // DoGetGenerators creates authorisation middleware for the given config
 func (e *Init) DoGetGenerators() (data.Generator, data.Generator, data.Generator) {
 	return data.GenerateUUID(), data.GenerateResourceId(), data.GenerateHumanReadableSlug()
@@ -213,3 +292,25 @@ func (e *Init) DoGetGenerators() (data.Generator, data.Generator, data.Generator
 func (e *Init) DoGetResponder(_ context.Context, _ *config.Config) (*responder.Responder, error) {
 	return responder.New(), nil
 }
+
+// DoGetSchemaRegistry returns a schema registry client, or an in-memory registry if no url is configured
+func (e *Init) DoGetSchemaRegistry(_ context.Context, cfg *config.Config) (schema.Registry, error) {
+	if cfg.SchemaRegistryURL == "" {
+		//for local development only
+		return schema.NewMemoryRegistry(), nil
+	}
+	return schema.NewHTTPRegistry(cfg.SchemaRegistryURL), nil
+}
+
+// GetScanner returns the malware scanner for uploads, nil if scanning is disabled
+func (e *ExternalServiceList) GetScanner(ctx context.Context, cfg *config.Config) (api.Scanner, error) {
+	return e.Init.DoGetScanner(ctx, cfg)
+}
+
+// DoGetScanner returns a clamd scanner, or nil if no clamd address is configured
+func (e *Init) DoGetScanner(_ context.Context, cfg *config.Config) (api.Scanner, error) {
+	if cfg.ClamAVAddr == "" {
+		return nil, nil
+	}
+	return scan.NewClamAV(cfg.ClamAVAddr, cfg.ClamAVTimeout), nil
+}
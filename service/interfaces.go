@@ -8,6 +8,7 @@ import (
 	"github.com/ONSdigital/dp-interactives-api/api"
 	"github.com/ONSdigital/dp-interactives-api/config"
 	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/schema"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
 	"github.com/ONSdigital/dp-net/v2/responder"
 	"net/http"
@@ -22,13 +23,18 @@ type Initialiser interface {
 	DoGetHTTPServer(bindAddr string, router http.Handler) HTTPServer
 	DoGetMongoDB(ctx context.Context, cfg *config.Config) (api.MongoServer, error)
 	DoGetKafkaProducer(ctx context.Context, cfg *config.Config) (kafka.IProducer, error)
+	DoGetKafkaConsumer(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error)
 	DoGetHealthClient(name, url string) *health.Client
 	DoGetHealthCheck(cfg *config.Config, buildTime, gitCommit, version string) (HealthChecker, error)
 	DoGetS3Client(ctx context.Context, cfg *config.Config) (api.S3Interface, error)
 	DoGetAuthorisationMiddleware(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.Middleware, error)
+	DoGetPermissionsChecker(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.PermissionsChecker, error)
 	DoGetGenerators() (data.Generator, data.Generator, data.Generator)
 	DoGetFilesService(ctx context.Context, cfg *config.Config) (api.FilesService, error)
 	DoGetResponder(ctx context.Context, cfg *config.Config) (*responder.Responder, error)
+	DoGetSchemaRegistry(ctx context.Context, cfg *config.Config) (schema.Registry, error)
+	DoGetScanner(ctx context.Context, cfg *config.Config) (api.Scanner, error)
+	DoGetContentStore(ctx context.Context, cfg *config.Config) (api.ContentStore, error)
 }
 
 // HTTPServer defines the required methods from the HTTP server
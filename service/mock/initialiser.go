@@ -10,6 +10,7 @@ import (
 	"github.com/ONSdigital/dp-interactives-api/api"
 	"github.com/ONSdigital/dp-interactives-api/config"
 	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/schema"
 	"github.com/ONSdigital/dp-interactives-api/service"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
 	"github.com/ONSdigital/dp-net/v2/responder"
@@ -23,50 +24,68 @@ var _ service.Initialiser = &InitialiserMock{}
 
 // InitialiserMock is a mock implementation of service.Initialiser.
 //
-// 	func TestSomethingThatUsesInitialiser(t *testing.T) {
+//	func TestSomethingThatUsesInitialiser(t *testing.T) {
 //
-// 		// make and configure a mocked service.Initialiser
-// 		mockedInitialiser := &InitialiserMock{
-// 			DoGetAuthorisationMiddlewareFunc: func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.Middleware, error) {
-// 				panic("mock out the DoGetAuthorisationMiddleware method")
-// 			},
-// 			DoGetFilesServiceFunc: func(ctx context.Context, cfg *config.Config) (api.FilesService, error) {
-// 				panic("mock out the DoGetFilesService method")
-// 			},
-// 			DoGetGeneratorsFunc: func() (data.Generator, data.Generator, data.Generator) {
-// 				panic("mock out the DoGetGenerators method")
-// 			},
-// 			DoGetHTTPServerFunc: func(bindAddr string, router http.Handler) service.HTTPServer {
-// 				panic("mock out the DoGetHTTPServer method")
-// 			},
-// 			DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
-// 				panic("mock out the DoGetHealthCheck method")
-// 			},
-// 			DoGetHealthClientFunc: func(name string, url string) *health.Client {
-// 				panic("mock out the DoGetHealthClient method")
-// 			},
-// 			DoGetKafkaProducerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IProducer, error) {
-// 				panic("mock out the DoGetKafkaProducer method")
-// 			},
-// 			DoGetMongoDBFunc: func(ctx context.Context, cfg *config.Config) (api.MongoServer, error) {
-// 				panic("mock out the DoGetMongoDB method")
-// 			},
-// 			DoGetResponderFunc: func(ctx context.Context, cfg *config.Config) (*responder.Responder, error) {
-// 				panic("mock out the DoGetResponder method")
-// 			},
-// 			DoGetS3ClientFunc: func(ctx context.Context, cfg *config.Config) (api.S3Interface, error) {
-// 				panic("mock out the DoGetS3Client method")
-// 			},
-// 		}
+//		// make and configure a mocked service.Initialiser
+//		mockedInitialiser := &InitialiserMock{
+//			DoGetAuthorisationMiddlewareFunc: func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.Middleware, error) {
+//				panic("mock out the DoGetAuthorisationMiddleware method")
+//			},
+//			DoGetContentStoreFunc: func(ctx context.Context, cfg *config.Config) (api.ContentStore, error) {
+//				panic("mock out the DoGetContentStore method")
+//			},
+//			DoGetFilesServiceFunc: func(ctx context.Context, cfg *config.Config) (api.FilesService, error) {
+//				panic("mock out the DoGetFilesService method")
+//			},
+//			DoGetGeneratorsFunc: func() (data.Generator, data.Generator, data.Generator) {
+//				panic("mock out the DoGetGenerators method")
+//			},
+//			DoGetHTTPServerFunc: func(bindAddr string, router http.Handler) service.HTTPServer {
+//				panic("mock out the DoGetHTTPServer method")
+//			},
+//			DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
+//				panic("mock out the DoGetHealthCheck method")
+//			},
+//			DoGetHealthClientFunc: func(name string, url string) *health.Client {
+//				panic("mock out the DoGetHealthClient method")
+//			},
+//			DoGetKafkaConsumerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+//				panic("mock out the DoGetKafkaConsumer method")
+//			},
+//			DoGetKafkaProducerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IProducer, error) {
+//				panic("mock out the DoGetKafkaProducer method")
+//			},
+//			DoGetMongoDBFunc: func(ctx context.Context, cfg *config.Config) (api.MongoServer, error) {
+//				panic("mock out the DoGetMongoDB method")
+//			},
+//			DoGetPermissionsCheckerFunc: func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.PermissionsChecker, error) {
+//				panic("mock out the DoGetPermissionsChecker method")
+//			},
+//			DoGetResponderFunc: func(ctx context.Context, cfg *config.Config) (*responder.Responder, error) {
+//				panic("mock out the DoGetResponder method")
+//			},
+//			DoGetS3ClientFunc: func(ctx context.Context, cfg *config.Config) (api.S3Interface, error) {
+//				panic("mock out the DoGetS3Client method")
+//			},
+//			DoGetScannerFunc: func(ctx context.Context, cfg *config.Config) (api.Scanner, error) {
+//				panic("mock out the DoGetScanner method")
+//			},
+//			DoGetSchemaRegistryFunc: func(ctx context.Context, cfg *config.Config) (schema.Registry, error) {
+//				panic("mock out the DoGetSchemaRegistry method")
+//			},
+//		}
 //
-// 		// use mockedInitialiser in code that requires service.Initialiser
-// 		// and then make assertions.
+//		// use mockedInitialiser in code that requires service.Initialiser
+//		// and then make assertions.
 //
-// 	}
+//	}
 type InitialiserMock struct {
 	// DoGetAuthorisationMiddlewareFunc mocks the DoGetAuthorisationMiddleware method.
 	DoGetAuthorisationMiddlewareFunc func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.Middleware, error)
 
+	// DoGetContentStoreFunc mocks the DoGetContentStore method.
+	DoGetContentStoreFunc func(ctx context.Context, cfg *config.Config) (api.ContentStore, error)
+
 	// DoGetFilesServiceFunc mocks the DoGetFilesService method.
 	DoGetFilesServiceFunc func(ctx context.Context, cfg *config.Config) (api.FilesService, error)
 
@@ -82,18 +101,30 @@ type InitialiserMock struct {
 	// DoGetHealthClientFunc mocks the DoGetHealthClient method.
 	DoGetHealthClientFunc func(name string, url string) *health.Client
 
+	// DoGetKafkaConsumerFunc mocks the DoGetKafkaConsumer method.
+	DoGetKafkaConsumerFunc func(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error)
+
 	// DoGetKafkaProducerFunc mocks the DoGetKafkaProducer method.
 	DoGetKafkaProducerFunc func(ctx context.Context, cfg *config.Config) (kafka.IProducer, error)
 
 	// DoGetMongoDBFunc mocks the DoGetMongoDB method.
 	DoGetMongoDBFunc func(ctx context.Context, cfg *config.Config) (api.MongoServer, error)
 
+	// DoGetPermissionsCheckerFunc mocks the DoGetPermissionsChecker method.
+	DoGetPermissionsCheckerFunc func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.PermissionsChecker, error)
+
 	// DoGetResponderFunc mocks the DoGetResponder method.
 	DoGetResponderFunc func(ctx context.Context, cfg *config.Config) (*responder.Responder, error)
 
 	// DoGetS3ClientFunc mocks the DoGetS3Client method.
 	DoGetS3ClientFunc func(ctx context.Context, cfg *config.Config) (api.S3Interface, error)
 
+	// DoGetScannerFunc mocks the DoGetScanner method.
+	DoGetScannerFunc func(ctx context.Context, cfg *config.Config) (api.Scanner, error)
+
+	// DoGetSchemaRegistryFunc mocks the DoGetSchemaRegistry method.
+	DoGetSchemaRegistryFunc func(ctx context.Context, cfg *config.Config) (schema.Registry, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// DoGetAuthorisationMiddleware holds details about calls to the DoGetAuthorisationMiddleware method.
@@ -103,6 +134,13 @@ type InitialiserMock struct {
 			// AuthorisationConfig is the authorisationConfig argument value.
 			AuthorisationConfig *authorisation.Config
 		}
+		// DoGetContentStore holds details about calls to the DoGetContentStore method.
+		DoGetContentStore []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cfg is the cfg argument value.
+			Cfg *config.Config
+		}
 		// DoGetFilesService holds details about calls to the DoGetFilesService method.
 		DoGetFilesService []struct {
 			// Ctx is the ctx argument value.
@@ -138,6 +176,13 @@ type InitialiserMock struct {
 			// URL is the url argument value.
 			URL string
 		}
+		// DoGetKafkaConsumer holds details about calls to the DoGetKafkaConsumer method.
+		DoGetKafkaConsumer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cfg is the cfg argument value.
+			Cfg *config.Config
+		}
 		// DoGetKafkaProducer holds details about calls to the DoGetKafkaProducer method.
 		DoGetKafkaProducer []struct {
 			// Ctx is the ctx argument value.
@@ -152,6 +197,13 @@ type InitialiserMock struct {
 			// Cfg is the cfg argument value.
 			Cfg *config.Config
 		}
+		// DoGetPermissionsChecker holds details about calls to the DoGetPermissionsChecker method.
+		DoGetPermissionsChecker []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// AuthorisationConfig is the authorisationConfig argument value.
+			AuthorisationConfig *authorisation.Config
+		}
 		// DoGetResponder holds details about calls to the DoGetResponder method.
 		DoGetResponder []struct {
 			// Ctx is the ctx argument value.
@@ -166,17 +218,36 @@ type InitialiserMock struct {
 			// Cfg is the cfg argument value.
 			Cfg *config.Config
 		}
+		// DoGetScanner holds details about calls to the DoGetScanner method.
+		DoGetScanner []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cfg is the cfg argument value.
+			Cfg *config.Config
+		}
+		// DoGetSchemaRegistry holds details about calls to the DoGetSchemaRegistry method.
+		DoGetSchemaRegistry []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cfg is the cfg argument value.
+			Cfg *config.Config
+		}
 	}
 	lockDoGetAuthorisationMiddleware sync.RWMutex
+	lockDoGetContentStore            sync.RWMutex
 	lockDoGetFilesService            sync.RWMutex
 	lockDoGetGenerators              sync.RWMutex
 	lockDoGetHTTPServer              sync.RWMutex
 	lockDoGetHealthCheck             sync.RWMutex
 	lockDoGetHealthClient            sync.RWMutex
+	lockDoGetKafkaConsumer           sync.RWMutex
 	lockDoGetKafkaProducer           sync.RWMutex
 	lockDoGetMongoDB                 sync.RWMutex
+	lockDoGetPermissionsChecker      sync.RWMutex
 	lockDoGetResponder               sync.RWMutex
 	lockDoGetS3Client                sync.RWMutex
+	lockDoGetScanner                 sync.RWMutex
+	lockDoGetSchemaRegistry          sync.RWMutex
 }
 
 // DoGetAuthorisationMiddleware calls DoGetAuthorisationMiddlewareFunc.
@@ -199,7 +270,8 @@ func (mock *InitialiserMock) DoGetAuthorisationMiddleware(ctx context.Context, a
 
 // DoGetAuthorisationMiddlewareCalls gets all the calls that were made to DoGetAuthorisationMiddleware.
 // Check the length with:
-//     len(mockedInitialiser.DoGetAuthorisationMiddlewareCalls())
+//
+//	len(mockedInitialiser.DoGetAuthorisationMiddlewareCalls())
 func (mock *InitialiserMock) DoGetAuthorisationMiddlewareCalls() []struct {
 	Ctx                 context.Context
 	AuthorisationConfig *authorisation.Config
@@ -214,6 +286,42 @@ func (mock *InitialiserMock) DoGetAuthorisationMiddlewareCalls() []struct {
 	return calls
 }
 
+// DoGetContentStore calls DoGetContentStoreFunc.
+func (mock *InitialiserMock) DoGetContentStore(ctx context.Context, cfg *config.Config) (api.ContentStore, error) {
+	if mock.DoGetContentStoreFunc == nil {
+		panic("InitialiserMock.DoGetContentStoreFunc: method is nil but Initialiser.DoGetContentStore was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}{
+		Ctx: ctx,
+		Cfg: cfg,
+	}
+	mock.lockDoGetContentStore.Lock()
+	mock.calls.DoGetContentStore = append(mock.calls.DoGetContentStore, callInfo)
+	mock.lockDoGetContentStore.Unlock()
+	return mock.DoGetContentStoreFunc(ctx, cfg)
+}
+
+// DoGetContentStoreCalls gets all the calls that were made to DoGetContentStore.
+// Check the length with:
+//
+//	len(mockedInitialiser.DoGetContentStoreCalls())
+func (mock *InitialiserMock) DoGetContentStoreCalls() []struct {
+	Ctx context.Context
+	Cfg *config.Config
+} {
+	var calls []struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}
+	mock.lockDoGetContentStore.RLock()
+	calls = mock.calls.DoGetContentStore
+	mock.lockDoGetContentStore.RUnlock()
+	return calls
+}
+
 // DoGetFilesService calls DoGetFilesServiceFunc.
 func (mock *InitialiserMock) DoGetFilesService(ctx context.Context, cfg *config.Config) (api.FilesService, error) {
 	if mock.DoGetFilesServiceFunc == nil {
@@ -234,7 +342,8 @@ func (mock *InitialiserMock) DoGetFilesService(ctx context.Context, cfg *config.
 
 // DoGetFilesServiceCalls gets all the calls that were made to DoGetFilesService.
 // Check the length with:
-//     len(mockedInitialiser.DoGetFilesServiceCalls())
+//
+//	len(mockedInitialiser.DoGetFilesServiceCalls())
 func (mock *InitialiserMock) DoGetFilesServiceCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
@@ -264,7 +373,8 @@ func (mock *InitialiserMock) DoGetGenerators() (data.Generator, data.Generator,
 
 // DoGetGeneratorsCalls gets all the calls that were made to DoGetGenerators.
 // Check the length with:
-//     len(mockedInitialiser.DoGetGeneratorsCalls())
+//
+//	len(mockedInitialiser.DoGetGeneratorsCalls())
 func (mock *InitialiserMock) DoGetGeneratorsCalls() []struct {
 } {
 	var calls []struct {
@@ -295,7 +405,8 @@ func (mock *InitialiserMock) DoGetHTTPServer(bindAddr string, router http.Handle
 
 // DoGetHTTPServerCalls gets all the calls that were made to DoGetHTTPServer.
 // Check the length with:
-//     len(mockedInitialiser.DoGetHTTPServerCalls())
+//
+//	len(mockedInitialiser.DoGetHTTPServerCalls())
 func (mock *InitialiserMock) DoGetHTTPServerCalls() []struct {
 	BindAddr string
 	Router   http.Handler
@@ -334,7 +445,8 @@ func (mock *InitialiserMock) DoGetHealthCheck(cfg *config.Config, buildTime stri
 
 // DoGetHealthCheckCalls gets all the calls that were made to DoGetHealthCheck.
 // Check the length with:
-//     len(mockedInitialiser.DoGetHealthCheckCalls())
+//
+//	len(mockedInitialiser.DoGetHealthCheckCalls())
 func (mock *InitialiserMock) DoGetHealthCheckCalls() []struct {
 	Cfg       *config.Config
 	BuildTime string
@@ -373,7 +485,8 @@ func (mock *InitialiserMock) DoGetHealthClient(name string, url string) *health.
 
 // DoGetHealthClientCalls gets all the calls that were made to DoGetHealthClient.
 // Check the length with:
-//     len(mockedInitialiser.DoGetHealthClientCalls())
+//
+//	len(mockedInitialiser.DoGetHealthClientCalls())
 func (mock *InitialiserMock) DoGetHealthClientCalls() []struct {
 	Name string
 	URL  string
@@ -388,6 +501,42 @@ func (mock *InitialiserMock) DoGetHealthClientCalls() []struct {
 	return calls
 }
 
+// DoGetKafkaConsumer calls DoGetKafkaConsumerFunc.
+func (mock *InitialiserMock) DoGetKafkaConsumer(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+	if mock.DoGetKafkaConsumerFunc == nil {
+		panic("InitialiserMock.DoGetKafkaConsumerFunc: method is nil but Initialiser.DoGetKafkaConsumer was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}{
+		Ctx: ctx,
+		Cfg: cfg,
+	}
+	mock.lockDoGetKafkaConsumer.Lock()
+	mock.calls.DoGetKafkaConsumer = append(mock.calls.DoGetKafkaConsumer, callInfo)
+	mock.lockDoGetKafkaConsumer.Unlock()
+	return mock.DoGetKafkaConsumerFunc(ctx, cfg)
+}
+
+// DoGetKafkaConsumerCalls gets all the calls that were made to DoGetKafkaConsumer.
+// Check the length with:
+//
+//	len(mockedInitialiser.DoGetKafkaConsumerCalls())
+func (mock *InitialiserMock) DoGetKafkaConsumerCalls() []struct {
+	Ctx context.Context
+	Cfg *config.Config
+} {
+	var calls []struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}
+	mock.lockDoGetKafkaConsumer.RLock()
+	calls = mock.calls.DoGetKafkaConsumer
+	mock.lockDoGetKafkaConsumer.RUnlock()
+	return calls
+}
+
 // DoGetKafkaProducer calls DoGetKafkaProducerFunc.
 func (mock *InitialiserMock) DoGetKafkaProducer(ctx context.Context, cfg *config.Config) (kafka.IProducer, error) {
 	if mock.DoGetKafkaProducerFunc == nil {
@@ -408,7 +557,8 @@ func (mock *InitialiserMock) DoGetKafkaProducer(ctx context.Context, cfg *config
 
 // DoGetKafkaProducerCalls gets all the calls that were made to DoGetKafkaProducer.
 // Check the length with:
-//     len(mockedInitialiser.DoGetKafkaProducerCalls())
+//
+//	len(mockedInitialiser.DoGetKafkaProducerCalls())
 func (mock *InitialiserMock) DoGetKafkaProducerCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
@@ -443,7 +593,8 @@ func (mock *InitialiserMock) DoGetMongoDB(ctx context.Context, cfg *config.Confi
 
 // DoGetMongoDBCalls gets all the calls that were made to DoGetMongoDB.
 // Check the length with:
-//     len(mockedInitialiser.DoGetMongoDBCalls())
+//
+//	len(mockedInitialiser.DoGetMongoDBCalls())
 func (mock *InitialiserMock) DoGetMongoDBCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
@@ -458,6 +609,42 @@ func (mock *InitialiserMock) DoGetMongoDBCalls() []struct {
 	return calls
 }
 
+// DoGetPermissionsChecker calls DoGetPermissionsCheckerFunc.
+func (mock *InitialiserMock) DoGetPermissionsChecker(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.PermissionsChecker, error) {
+	if mock.DoGetPermissionsCheckerFunc == nil {
+		panic("InitialiserMock.DoGetPermissionsCheckerFunc: method is nil but Initialiser.DoGetPermissionsChecker was just called")
+	}
+	callInfo := struct {
+		Ctx                 context.Context
+		AuthorisationConfig *authorisation.Config
+	}{
+		Ctx:                 ctx,
+		AuthorisationConfig: authorisationConfig,
+	}
+	mock.lockDoGetPermissionsChecker.Lock()
+	mock.calls.DoGetPermissionsChecker = append(mock.calls.DoGetPermissionsChecker, callInfo)
+	mock.lockDoGetPermissionsChecker.Unlock()
+	return mock.DoGetPermissionsCheckerFunc(ctx, authorisationConfig)
+}
+
+// DoGetPermissionsCheckerCalls gets all the calls that were made to DoGetPermissionsChecker.
+// Check the length with:
+//
+//	len(mockedInitialiser.DoGetPermissionsCheckerCalls())
+func (mock *InitialiserMock) DoGetPermissionsCheckerCalls() []struct {
+	Ctx                 context.Context
+	AuthorisationConfig *authorisation.Config
+} {
+	var calls []struct {
+		Ctx                 context.Context
+		AuthorisationConfig *authorisation.Config
+	}
+	mock.lockDoGetPermissionsChecker.RLock()
+	calls = mock.calls.DoGetPermissionsChecker
+	mock.lockDoGetPermissionsChecker.RUnlock()
+	return calls
+}
+
 // DoGetResponder calls DoGetResponderFunc.
 func (mock *InitialiserMock) DoGetResponder(ctx context.Context, cfg *config.Config) (*responder.Responder, error) {
 	if mock.DoGetResponderFunc == nil {
@@ -478,7 +665,8 @@ func (mock *InitialiserMock) DoGetResponder(ctx context.Context, cfg *config.Con
 
 // DoGetResponderCalls gets all the calls that were made to DoGetResponder.
 // Check the length with:
-//     len(mockedInitialiser.DoGetResponderCalls())
+//
+//	len(mockedInitialiser.DoGetResponderCalls())
 func (mock *InitialiserMock) DoGetResponderCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
@@ -513,7 +701,8 @@ func (mock *InitialiserMock) DoGetS3Client(ctx context.Context, cfg *config.Conf
 
 // DoGetS3ClientCalls gets all the calls that were made to DoGetS3Client.
 // Check the length with:
-//     len(mockedInitialiser.DoGetS3ClientCalls())
+//
+//	len(mockedInitialiser.DoGetS3ClientCalls())
 func (mock *InitialiserMock) DoGetS3ClientCalls() []struct {
 	Ctx context.Context
 	Cfg *config.Config
@@ -527,3 +716,75 @@ func (mock *InitialiserMock) DoGetS3ClientCalls() []struct {
 	mock.lockDoGetS3Client.RUnlock()
 	return calls
 }
+
+// DoGetScanner calls DoGetScannerFunc.
+func (mock *InitialiserMock) DoGetScanner(ctx context.Context, cfg *config.Config) (api.Scanner, error) {
+	if mock.DoGetScannerFunc == nil {
+		panic("InitialiserMock.DoGetScannerFunc: method is nil but Initialiser.DoGetScanner was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}{
+		Ctx: ctx,
+		Cfg: cfg,
+	}
+	mock.lockDoGetScanner.Lock()
+	mock.calls.DoGetScanner = append(mock.calls.DoGetScanner, callInfo)
+	mock.lockDoGetScanner.Unlock()
+	return mock.DoGetScannerFunc(ctx, cfg)
+}
+
+// DoGetScannerCalls gets all the calls that were made to DoGetScanner.
+// Check the length with:
+//
+//	len(mockedInitialiser.DoGetScannerCalls())
+func (mock *InitialiserMock) DoGetScannerCalls() []struct {
+	Ctx context.Context
+	Cfg *config.Config
+} {
+	var calls []struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}
+	mock.lockDoGetScanner.RLock()
+	calls = mock.calls.DoGetScanner
+	mock.lockDoGetScanner.RUnlock()
+	return calls
+}
+
+// DoGetSchemaRegistry calls DoGetSchemaRegistryFunc.
+func (mock *InitialiserMock) DoGetSchemaRegistry(ctx context.Context, cfg *config.Config) (schema.Registry, error) {
+	if mock.DoGetSchemaRegistryFunc == nil {
+		panic("InitialiserMock.DoGetSchemaRegistryFunc: method is nil but Initialiser.DoGetSchemaRegistry was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}{
+		Ctx: ctx,
+		Cfg: cfg,
+	}
+	mock.lockDoGetSchemaRegistry.Lock()
+	mock.calls.DoGetSchemaRegistry = append(mock.calls.DoGetSchemaRegistry, callInfo)
+	mock.lockDoGetSchemaRegistry.Unlock()
+	return mock.DoGetSchemaRegistryFunc(ctx, cfg)
+}
+
+// DoGetSchemaRegistryCalls gets all the calls that were made to DoGetSchemaRegistry.
+// Check the length with:
+//
+//	len(mockedInitialiser.DoGetSchemaRegistryCalls())
+func (mock *InitialiserMock) DoGetSchemaRegistryCalls() []struct {
+	Ctx context.Context
+	Cfg *config.Config
+} {
+	var calls []struct {
+		Ctx context.Context
+		Cfg *config.Config
+	}
+	mock.lockDoGetSchemaRegistry.RLock()
+	calls = mock.calls.DoGetSchemaRegistry
+	mock.lockDoGetSchemaRegistry.RUnlock()
+	return calls
+}
@@ -3,10 +3,14 @@ package service
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/ONSdigital/dp-authorisation/v2/authorisation"
+	"github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-interactives-api/api"
 	"github.com/ONSdigital/dp-interactives-api/config"
+	"github.com/ONSdigital/dp-interactives-api/event"
+	"github.com/ONSdigital/dp-interactives-api/schema"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
 	"github.com/ONSdigital/log.go/v2/log"
 	"github.com/gorilla/mux"
@@ -23,7 +27,9 @@ type Service struct {
 	healthCheck               HealthChecker
 	mongoDB                   api.MongoServer
 	interactivesKafkaProducer kafka.IProducer
+	interactivesKafkaConsumer kafka.IConsumerGroup
 	authorisationMiddleware   authorisation.Middleware
+	permissionsChecker        authorisation.PermissionsChecker
 	filesService              api.FilesService
 }
 
@@ -41,8 +47,11 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 
 	var s3Client api.S3Interface
 	var producer kafka.IProducer
+	var consumer kafka.IConsumerGroup
 	var filesService api.FilesService
+	var scanner api.Scanner
 	var authorisationMiddleware authorisation.Middleware
+	var permissionsChecker authorisation.PermissionsChecker
 	if cfg.PublishingEnabled {
 		// Get S3Uploaded client
 		s3Client, err = serviceList.GetS3Client(ctx, cfg)
@@ -58,23 +67,73 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 			return nil, err
 		}
 
+		// Register the uploaded event schemas and tag every message with the version written
+		if err = registerSchemas(ctx, cfg, serviceList, producer); err != nil {
+			log.Fatal(ctx, "failed to register kafka schemas", err)
+			return nil, err
+		}
+
+		// Get Kafka consumer (import results - an alternative to the importer patching over http)
+		consumer, err = serviceList.GetKafkaConsumer(ctx, cfg)
+		if err != nil {
+			log.Fatal(ctx, "failed to initialise kafka consumer", err)
+			return nil, err
+		}
+
 		filesService, err = serviceList.GetFilesService(ctx, cfg)
 		if err != nil {
 			log.Fatal(ctx, "failed to initialise files service", err)
 			return nil, err
 		}
 
+		// Malware scanning of uploads (optional)
+		scanner, err = serviceList.GetScanner(ctx, cfg)
+		if err != nil {
+			log.Fatal(ctx, "failed to initialise malware scanner", err)
+			return nil, err
+		}
+
 		// Auth - only needed in publish
 		authorisationMiddleware, err = serviceList.GetAuthorisationMiddleware(ctx, cfg.AuthorisationConfig)
 		if err != nil {
 			log.Fatal(ctx, "could not instantiate authorisation middleware", err)
 			return nil, err
 		}
+
+		// some handlers behave differently by the caller's permissions - everyone has them with authorisation off
+		if cfg.AuthorisationConfig.Enabled {
+			permissionsChecker, err = serviceList.GetPermissionsChecker(ctx, cfg.AuthorisationConfig)
+			if err != nil {
+				log.Fatal(ctx, "could not instantiate permissions checker", err)
+				return nil, err
+			}
+		}
+	}
+
+	// Imported interactives are served from the content bucket in both modes (optional)
+	contentStore, err := serviceList.GetContentStore(ctx, cfg)
+	if err != nil {
+		log.Fatal(ctx, "failed to initialise content store", err)
+		return nil, err
 	}
 
 	uuidGen, resourceIdGen, slugGen := serviceList.GetGenerators()
 	responder, _ := serviceList.GetResponder(ctx, cfg)
-	a := api.Setup(ctx, cfg, r, authorisationMiddleware, mongoDB, producer, s3Client, filesService, uuidGen, resourceIdGen, slugGen, responder)
+	a := api.Setup(ctx, cfg, r, authorisationMiddleware, permissionsChecker, mongoDB, producer, s3Client, filesService, scanner, contentStore, uuidGen, resourceIdGen, slugGen, responder)
+	if cfg.PublishingEnabled {
+		a.StartOutboxRelay(ctx)
+
+		handler := event.NewInteractiveImportedHandler(a.MongoDB(), schema.InteractiveImportedEvent)
+		if err = consumer.RegisterHandler(ctx, handler.Handle); err != nil {
+			log.Fatal(ctx, "could not register kafka consumer handler", err)
+			return nil, err
+		}
+		consumer.LogErrors(ctx)
+		if err = consumer.Start(); err != nil {
+			log.Fatal(ctx, "could not start kafka consumer", err)
+			return nil, err
+		}
+	}
 
 	//heathcheck
 	hc, err := serviceList.GetHealthCheck(cfg, buildTime, gitCommit, version)
@@ -82,7 +141,7 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		log.Fatal(ctx, "could not instantiate healthcheck", err)
 		return nil, err
 	}
-	err = registerCheckers(ctx, cfg, hc, mongoDB, producer, s3Client, authorisationMiddleware, filesService)
+	err = registerCheckers(ctx, cfg, hc, mongoDB, producer, consumer, s3Client, authorisationMiddleware, filesService, scanner, contentStore, a.UploadsChecker)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to register checkers")
 	}
@@ -106,11 +165,38 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		healthCheck:               hc,
 		mongoDB:                   mongoDB,
 		interactivesKafkaProducer: producer,
+		interactivesKafkaConsumer: consumer,
 		authorisationMiddleware:   authorisationMiddleware,
+		permissionsChecker:        permissionsChecker,
 		filesService:              filesService,
 	}, nil
 }
 
+func registerSchemas(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceList, producer kafka.IProducer) error {
+	registry, err := serviceList.GetSchemaRegistry(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	version := cfg.UploadedSchemaVersion
+	if version == 0 {
+		version = schema.InteractiveUploadedVersions.Latest()
+	}
+	if _, err = schema.InteractiveUploadedVersions.Get(version); err != nil {
+		return err
+	}
+
+	ids, err := schema.InteractiveUploadedVersions.Register(ctx, registry, cfg.InteractivesWriteTopic+"-value")
+	if err != nil {
+		return err
+	}
+
+	producer.AddHeader(schema.VersionHeader, strconv.Itoa(version))
+	producer.AddHeader(schema.IDHeader, strconv.Itoa(ids[version-1]))
+	log.Info(ctx, "registered kafka schemas", log.Data{"topic": cfg.InteractivesWriteTopic, "version": version, "schema_id": ids[version-1]})
+	return nil
+}
+
 // Close gracefully shuts the service down in the required order, with timeout
 func (svc *Service) Close(ctx context.Context) error {
 	timeout := svc.config.GracefulShutdownTimeout
@@ -129,6 +215,14 @@ func (svc *Service) Close(ctx context.Context) error {
 			svc.healthCheck.Stop()
 		}
 
+		// stop consuming import results, letting in-flight messages finish
+		if svc.serviceList.KafkaConsumer {
+			if err := svc.interactivesKafkaConsumer.StopAndWait(); err != nil {
+				log.Error(ctx, "error stopping Kafka consumer", err)
+				hasShutdownError = true
+			}
+		}
+
 		// stop any incoming requests before closing any outbound connections
 		if err := svc.server.Shutdown(ctx); err != nil {
 			log.Error(ctx, "failed to shutdown http server", err)
@@ -149,6 +243,13 @@ func (svc *Service) Close(ctx context.Context) error {
 			}
 		}
 
+		if svc.serviceList.KafkaConsumer {
+			if err := svc.interactivesKafkaConsumer.Close(ctx); err != nil {
+				log.Error(ctx, "error closing Kafka consumer", err)
+				hasShutdownError = true
+			}
+		}
+
 		if svc.serviceList.KafkaProducer {
 			if err := svc.interactivesKafkaProducer.Close(ctx); err != nil {
 				log.Error(ctx, "error closing Kafka producer", err)
@@ -161,6 +262,12 @@ func (svc *Service) Close(ctx context.Context) error {
 				log.Error(ctx, "failed to close authorisation middleware", err)
 				hasShutdownError = true
 			}
+			if svc.permissionsChecker != nil {
+				if err := svc.permissionsChecker.Close(ctx); err != nil {
+					log.Error(ctx, "failed to close permissions checker", err)
+					hasShutdownError = true
+				}
+			}
 		}
 
 		if !hasShutdownError {
@@ -186,9 +293,13 @@ func registerCheckers(ctx context.Context,
 	hc HealthChecker,
 	mongoDB api.MongoServer,
 	producer kafka.IProducer,
+	consumer kafka.IConsumerGroup,
 	s3 api.S3Interface,
 	authorisationMiddleware authorisation.Middleware,
-	filesService api.FilesService) (err error) {
+	filesService api.FilesService,
+	scanner api.Scanner,
+	contentStore api.ContentStore,
+	uploads healthcheck.Checker) (err error) {
 
 	hasErrors := false
 
@@ -197,12 +308,24 @@ func registerCheckers(ctx context.Context,
 		log.Error(ctx, "error adding check for mongo db", err)
 	}
 
+	if contentStore != nil {
+		if err = hc.AddCheck("Content store", contentStore.Checker); err != nil {
+			hasErrors = true
+			log.Error(ctx, "error adding check for content store", err)
+		}
+	}
+
 	if cfg.PublishingEnabled {
 		if err = hc.AddCheck("Uploaded Kafka Producer", producer.Checker); err != nil {
 			hasErrors = true
 			log.Error(ctx, "error adding check for uploaded kafka producer", err, log.Data{"topic": cfg.InteractivesWriteTopic})
 		}
 
+		if err = hc.AddCheck("Imported Kafka Consumer", consumer.Checker); err != nil {
+			hasErrors = true
+			log.Error(ctx, "error adding check for imported kafka consumer", err, log.Data{"topic": cfg.InteractivesReadTopic})
+		}
+
 		if err = hc.AddCheck("S3 checker", s3.Checker); err != nil {
 			hasErrors = true
 			log.Error(ctx, "error adding check for s3", err)
@@ -217,6 +340,18 @@ func registerCheckers(ctx context.Context,
 			hasErrors = true
 			log.Error(ctx, "error adding check for permissions cache", err)
 		}
+
+		if err = hc.AddCheck("Upload limiter", uploads); err != nil {
+			hasErrors = true
+			log.Error(ctx, "error adding check for upload limiter", err)
+		}
+
+		if scanner != nil {
+			if err = hc.AddCheck("Malware scanner", scanner.Checker); err != nil {
+				hasErrors = true
+				log.Error(ctx, "error adding check for malware scanner", err)
+			}
+		}
 	}
 
 	if hasErrors {
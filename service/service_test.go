@@ -15,6 +15,9 @@ import (
 	apiMock "github.com/ONSdigital/dp-interactives-api/api/mock"
 	"github.com/ONSdigital/dp-interactives-api/config"
 	"github.com/ONSdigital/dp-interactives-api/internal/data"
+	"github.com/ONSdigital/dp-interactives-api/internal/scan"
+	"github.com/ONSdigital/dp-interactives-api/models"
+	"github.com/ONSdigital/dp-interactives-api/schema"
 	"github.com/ONSdigital/dp-interactives-api/service"
 	serviceMock "github.com/ONSdigital/dp-interactives-api/service/mock"
 	kafka "github.com/ONSdigital/dp-kafka/v3"
@@ -26,7 +29,7 @@ import (
 )
 
 const (
-	expectedChecks = 5
+	expectedChecks = 7
 )
 
 var (
@@ -57,6 +60,18 @@ var (
 	funcDoGetResponder = func(ctx context.Context, cfg *config.Config) (*responder.Responder, error) {
 		return responder.New(), nil
 	}
+
+	funcDoGetSchemaRegistry = func(ctx context.Context, cfg *config.Config) (schema.Registry, error) {
+		return schema.NewMemoryRegistry(), nil
+	}
+
+	funcDoGetScannerNone = func(ctx context.Context, cfg *config.Config) (api.Scanner, error) {
+		return nil, nil
+	}
+
+	funcDoGetContentStoreNone = func(ctx context.Context, cfg *config.Config) (api.ContentStore, error) {
+		return nil, nil
+	}
 )
 
 func TestRun(t *testing.T) {
@@ -77,6 +92,9 @@ func TestRun(t *testing.T) {
 
 		mongoDbMock := &apiMock.MongoServerMock{
 			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error { return nil },
+			ListOutboxEventsFunc: func(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+				return nil, nil
+			},
 		}
 
 		channels := &kafka.ProducerChannels{
@@ -84,11 +102,18 @@ func TestRun(t *testing.T) {
 		}
 		kafkaProducerMock := &kafkatest.IProducerMock{
 			LogErrorsFunc: func(ctx context.Context) {},
+			AddHeaderFunc: func(key, value string) {},
 			ChannelsFunc: func() *kafka.ProducerChannels {
 				return channels
 			},
 		}
 
+		kafkaConsumerMock := &kafkatest.IConsumerGroupMock{
+			RegisterHandlerFunc: func(ctx context.Context, h kafka.Handler) error { return nil },
+			LogErrorsFunc:       func(ctx context.Context) {},
+			StartFunc:           func() error { return nil },
+		}
+
 		hcMock := &serviceMock.HealthCheckerMock{
 			AddCheckFunc: func(name string, checker healthcheck.Checker) error { return nil },
 			StartFunc:    func(ctx context.Context) {},
@@ -141,6 +166,10 @@ func TestRun(t *testing.T) {
 			return kafkaProducerMock, nil
 		}
 
+		funcDoGetKafkaConsumerOk := func(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+			return kafkaConsumerMock, nil
+		}
+
 		funcDoGetKafkaProducerErr := func(ctx context.Context, cfg *config.Config) (kafka.IProducer, error) {
 			return nil, errKafkaProducer
 		}
@@ -169,11 +198,15 @@ func TestRun(t *testing.T) {
 				DoGetHTTPServerFunc:              funcDoGetHTTPServerNil,
 				DoGetMongoDBFunc:                 funcDoGetMongoDbErr,
 				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
 				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
 				DoGetS3ClientFunc:                funcDoGetS3Ok,
 				DoGetAuthorisationMiddlewareFunc: funcDoGetAuthOk,
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -198,6 +231,9 @@ func TestRun(t *testing.T) {
 				DoGetAuthorisationMiddlewareFunc: funcDoGetAuthOk,
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -217,11 +253,15 @@ func TestRun(t *testing.T) {
 				DoGetHTTPServerFunc:              funcDoGetHTTPServerNil,
 				DoGetMongoDBFunc:                 funcDoGetMongoDbOk,
 				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
 				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
 				DoGetS3ClientFunc:                funcDoGetS3Err,
 				DoGetAuthorisationMiddlewareFunc: funcDoGetAuthOk,
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -242,12 +282,16 @@ func TestRun(t *testing.T) {
 				DoGetMongoDBFunc:                 funcDoGetMongoDbOk,
 				DoGetHealthCheckFunc:             funcDoGetHealthcheckErr,
 				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
 				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
 				DoGetS3ClientFunc:                funcDoGetS3Ok,
 				DoGetAuthorisationMiddlewareFunc: funcDoGetAuthOk,
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetFilesServiceFunc:            funcDoGetFilesServiceOk,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -274,6 +318,7 @@ func TestRun(t *testing.T) {
 				DoGetHTTPServerFunc:    funcDoGetHTTPServerNil,
 				DoGetMongoDBFunc:       funcDoGetMongoDbOk,
 				DoGetKafkaProducerFunc: funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc: funcDoGetKafkaConsumerOk,
 				DoGetS3ClientFunc:      funcDoGetS3Ok,
 				DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
 					return hcMockAddFail, nil
@@ -283,6 +328,9 @@ func TestRun(t *testing.T) {
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetFilesServiceFunc:            funcDoGetFilesServiceOk,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -296,9 +344,11 @@ func TestRun(t *testing.T) {
 				So(hcMockAddFail.AddCheckCalls(), ShouldHaveLength, expectedChecks)
 				So(hcMockAddFail.AddCheckCalls()[0].Name, ShouldResemble, "Mongo DB")
 				So(hcMockAddFail.AddCheckCalls()[1].Name, ShouldResemble, "Uploaded Kafka Producer")
-				So(hcMockAddFail.AddCheckCalls()[2].Name, ShouldResemble, "S3 checker")
-				So(hcMockAddFail.AddCheckCalls()[3].Name, ShouldResemble, "FilesService checker")
-				So(hcMockAddFail.AddCheckCalls()[4].Name, ShouldResemble, "permissions cache health check")
+				So(hcMockAddFail.AddCheckCalls()[2].Name, ShouldResemble, "Imported Kafka Consumer")
+				So(hcMockAddFail.AddCheckCalls()[3].Name, ShouldResemble, "S3 checker")
+				So(hcMockAddFail.AddCheckCalls()[4].Name, ShouldResemble, "FilesService checker")
+				So(hcMockAddFail.AddCheckCalls()[5].Name, ShouldResemble, "permissions cache health check")
+				So(hcMockAddFail.AddCheckCalls()[6].Name, ShouldResemble, "Upload limiter")
 			})
 		})
 
@@ -308,6 +358,7 @@ func TestRun(t *testing.T) {
 				DoGetHTTPServerFunc:              funcDoGetHTTPServer,
 				DoGetMongoDBFunc:                 funcDoGetMongoDbOk,
 				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
 				DoGetHealthCheckFunc:             funcDoGetHealthcheckOk,
 				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
 				DoGetS3ClientFunc:                funcDoGetS3Ok,
@@ -315,6 +366,9 @@ func TestRun(t *testing.T) {
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetFilesServiceFunc:            funcDoGetFilesServiceOk,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -325,6 +379,12 @@ func TestRun(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(svcList.MongoDB, ShouldBeTrue)
 				So(svcList.KafkaProducer, ShouldBeTrue)
+				So(svcList.KafkaConsumer, ShouldBeTrue)
+				So(kafkaConsumerMock.RegisterHandlerCalls(), ShouldHaveLength, 1)
+				So(kafkaConsumerMock.StartCalls(), ShouldHaveLength, 1)
+				So(kafkaProducerMock.AddHeaderCalls(), ShouldHaveLength, 2)
+				So(kafkaProducerMock.AddHeaderCalls()[0].Key, ShouldEqual, schema.VersionHeader)
+				So(kafkaProducerMock.AddHeaderCalls()[0].Value, ShouldEqual, "2")
 				So(svcList.HealthCheck, ShouldBeTrue)
 				So(svcList.S3Client, ShouldBeTrue)
 			})
@@ -333,9 +393,11 @@ func TestRun(t *testing.T) {
 				So(hcMock.AddCheckCalls(), ShouldHaveLength, expectedChecks)
 				So(hcMock.AddCheckCalls()[0].Name, ShouldResemble, "Mongo DB")
 				So(hcMock.AddCheckCalls()[1].Name, ShouldResemble, "Uploaded Kafka Producer")
-				So(hcMock.AddCheckCalls()[2].Name, ShouldResemble, "S3 checker")
-				So(hcMock.AddCheckCalls()[3].Name, ShouldResemble, "FilesService checker")
-				So(hcMock.AddCheckCalls()[4].Name, ShouldResemble, "permissions cache health check")
+				So(hcMock.AddCheckCalls()[2].Name, ShouldResemble, "Imported Kafka Consumer")
+				So(hcMock.AddCheckCalls()[3].Name, ShouldResemble, "S3 checker")
+				So(hcMock.AddCheckCalls()[4].Name, ShouldResemble, "FilesService checker")
+				So(hcMock.AddCheckCalls()[5].Name, ShouldResemble, "permissions cache health check")
+				So(hcMock.AddCheckCalls()[6].Name, ShouldResemble, "Upload limiter")
 				So(initMock.DoGetHTTPServerCalls(), ShouldHaveLength, 1)
 				So(initMock.DoGetHTTPServerCalls()[0].BindAddr, ShouldEqual, ":27500")
 				So(hcMock.StartCalls(), ShouldHaveLength, 1)
@@ -344,12 +406,79 @@ func TestRun(t *testing.T) {
 			})
 		})
 
+		Convey("Given that a malware scanner is configured", func() {
+
+			initMock := &serviceMock.InitialiserMock{
+				DoGetHTTPServerFunc:              funcDoGetHTTPServer,
+				DoGetMongoDBFunc:                 funcDoGetMongoDbOk,
+				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
+				DoGetHealthCheckFunc:             funcDoGetHealthcheckOk,
+				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
+				DoGetS3ClientFunc:                funcDoGetS3Ok,
+				DoGetAuthorisationMiddlewareFunc: funcDoGetAuthOk,
+				DoGetGeneratorsFunc:              funcDoGetGenerator,
+				DoGetFilesServiceFunc:            funcDoGetFilesServiceOk,
+				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc: func(ctx context.Context, cfg *config.Config) (api.Scanner, error) {
+					return &scan.Fake{}, nil
+				},
+			}
+			svcErrors := make(chan error, 1)
+			svcList := service.NewServiceList(initMock)
+			serverWg.Add(1)
+			_, err := service.Run(ctx, cfg, svcList, testBuildTime, testGitCommit, testVersion, svcErrors)
+
+			Convey("Then its health is checked too", func() {
+				So(err, ShouldBeNil)
+				So(hcMock.AddCheckCalls(), ShouldHaveLength, expectedChecks+1)
+				So(hcMock.AddCheckCalls()[expectedChecks].Name, ShouldResemble, "Malware scanner")
+				serverWg.Wait()
+			})
+		})
+
+		Convey("Given that a content bucket is configured", func() {
+
+			initMock := &serviceMock.InitialiserMock{
+				DoGetHTTPServerFunc:              funcDoGetHTTPServer,
+				DoGetMongoDBFunc:                 funcDoGetMongoDbOk,
+				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
+				DoGetHealthCheckFunc:             funcDoGetHealthcheckOk,
+				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
+				DoGetS3ClientFunc:                funcDoGetS3Ok,
+				DoGetAuthorisationMiddlewareFunc: funcDoGetAuthOk,
+				DoGetGeneratorsFunc:              funcDoGetGenerator,
+				DoGetFilesServiceFunc:            funcDoGetFilesServiceOk,
+				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
+				DoGetContentStoreFunc: func(ctx context.Context, cfg *config.Config) (api.ContentStore, error) {
+					return &apiMock.ContentStoreMock{}, nil
+				},
+			}
+			svcErrors := make(chan error, 1)
+			svcList := service.NewServiceList(initMock)
+			serverWg.Add(1)
+			_, err := service.Run(ctx, cfg, svcList, testBuildTime, testGitCommit, testVersion, svcErrors)
+
+			Convey("Then its health is checked too", func() {
+				So(err, ShouldBeNil)
+				So(hcMock.AddCheckCalls(), ShouldHaveLength, expectedChecks+1)
+				So(hcMock.AddCheckCalls()[1].Name, ShouldResemble, "Content store")
+				serverWg.Wait()
+			})
+		})
+
 		Convey("Given that all dependencies are successfully initialised but the http server fails", func() {
 
 			initMock := &serviceMock.InitialiserMock{
 				DoGetHTTPServerFunc:              funcDoGetFailingHTTPSerer,
 				DoGetMongoDBFunc:                 funcDoGetMongoDbOk,
 				DoGetKafkaProducerFunc:           funcDoGetKafkaProducerOk,
+				DoGetKafkaConsumerFunc:           funcDoGetKafkaConsumerOk,
 				DoGetHealthCheckFunc:             funcDoGetHealthcheckOk,
 				DoGetHealthClientFunc:            funcDoGetHealthClientOk,
 				DoGetS3ClientFunc:                funcDoGetS3Ok,
@@ -357,6 +486,9 @@ func TestRun(t *testing.T) {
 				DoGetGeneratorsFunc:              funcDoGetGenerator,
 				DoGetFilesServiceFunc:            funcDoGetFilesServiceOk,
 				DoGetResponderFunc:               funcDoGetResponder,
+				DoGetSchemaRegistryFunc:          funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:            funcDoGetContentStoreNone,
+				DoGetScannerFunc:                 funcDoGetScannerNone,
 			}
 			svcErrors := make(chan error, 1)
 			svcList := service.NewServiceList(initMock)
@@ -416,6 +548,9 @@ func TestClose(t *testing.T) {
 		// mongoDB Close will fail if healthcheck and http server are not already closed
 		mongoDbMock := &apiMock.MongoServerMock{
 			CheckerFunc: func(ctx context.Context, state *healthcheck.CheckState) error { return nil },
+			ListOutboxEventsFunc: func(ctx context.Context, limit int) ([]*models.OutboxEvent, error) {
+				return nil, nil
+			},
 			CloseFunc: func(ctx context.Context) error {
 				if !hcStopped || !serverStopped {
 					return errors.New("MongoDB closed before stopping healthcheck or HTTP server")
@@ -442,7 +577,23 @@ func TestClose(t *testing.T) {
 				}
 				return nil
 			},
-			ChannelsFunc: func() *kafka.ProducerChannels { return kafka.CreateProducerChannels() },
+			ChannelsFunc:  func() *kafka.ProducerChannels { return kafka.CreateProducerChannels() },
+			AddHeaderFunc: func(key, value string) {},
+		}
+
+		// kafkaConsumerMock must stop consuming before the http server and mongo are closed
+		kafkaConsumerMock := &kafkatest.IConsumerGroupMock{
+			CheckerFunc:         func(ctx context.Context, state *healthcheck.CheckState) error { return nil },
+			RegisterHandlerFunc: func(ctx context.Context, h kafka.Handler) error { return nil },
+			LogErrorsFunc:       func(ctx context.Context) {},
+			StartFunc:           func() error { return nil },
+			StopAndWaitFunc: func() error {
+				if !hcStopped || serverStopped || mongoStopped {
+					return errors.New("KafkaConsumer stopped in the wrong order")
+				}
+				return nil
+			},
+			CloseFunc: func(ctx context.Context, optFuncs ...kafka.OptFunc) error { return nil },
 		}
 
 		Convey("Closing the service results in all the dependencies being closed in the expected order", func() {
@@ -451,6 +602,9 @@ func TestClose(t *testing.T) {
 				DoGetHTTPServerFunc:    func(bindAddr string, router http.Handler) service.HTTPServer { return serverMock },
 				DoGetMongoDBFunc:       func(ctx context.Context, cfg *config.Config) (api.MongoServer, error) { return mongoDbMock, nil },
 				DoGetKafkaProducerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IProducer, error) { return kafkaProducerMock, nil },
+				DoGetKafkaConsumerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+					return kafkaConsumerMock, nil
+				},
 				DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
 					return hcMock, nil
 				},
@@ -459,9 +613,12 @@ func TestClose(t *testing.T) {
 				DoGetAuthorisationMiddlewareFunc: func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.Middleware, error) {
 					return authorisationMiddleware, nil
 				},
-				DoGetGeneratorsFunc:   funcDoGetGenerator,
-				DoGetFilesServiceFunc: func(ctx context.Context, cfg *config.Config) (api.FilesService, error) { return fsMock, nil },
-				DoGetResponderFunc:    funcDoGetResponder,
+				DoGetGeneratorsFunc:     funcDoGetGenerator,
+				DoGetFilesServiceFunc:   func(ctx context.Context, cfg *config.Config) (api.FilesService, error) { return fsMock, nil },
+				DoGetResponderFunc:      funcDoGetResponder,
+				DoGetSchemaRegistryFunc: funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:   funcDoGetContentStoreNone,
+				DoGetScannerFunc:        funcDoGetScannerNone,
 			}
 
 			svcErrors := make(chan error, 1)
@@ -475,6 +632,8 @@ func TestClose(t *testing.T) {
 			So(serverMock.ShutdownCalls(), ShouldHaveLength, 1)
 			So(mongoDbMock.CloseCalls(), ShouldHaveLength, 1)
 			So(kafkaProducerMock.CloseCalls(), ShouldHaveLength, 1)
+			So(kafkaConsumerMock.StopAndWaitCalls(), ShouldHaveLength, 1)
+			So(kafkaConsumerMock.CloseCalls(), ShouldHaveLength, 1)
 		})
 
 		Convey("If services fail to stop, the Close operation tries to close all dependencies and returns an error", func() {
@@ -490,6 +649,9 @@ func TestClose(t *testing.T) {
 				DoGetHTTPServerFunc:    func(bindAddr string, router http.Handler) service.HTTPServer { return failingserverMock },
 				DoGetMongoDBFunc:       func(ctx context.Context, cfg *config.Config) (api.MongoServer, error) { return mongoDbMock, nil },
 				DoGetKafkaProducerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IProducer, error) { return kafkaProducerMock, nil },
+				DoGetKafkaConsumerFunc: func(ctx context.Context, cfg *config.Config) (kafka.IConsumerGroup, error) {
+					return kafkaConsumerMock, nil
+				},
 				DoGetHealthCheckFunc: func(cfg *config.Config, buildTime string, gitCommit string, version string) (service.HealthChecker, error) {
 					return hcMock, nil
 				},
@@ -498,9 +660,12 @@ func TestClose(t *testing.T) {
 				DoGetAuthorisationMiddlewareFunc: func(ctx context.Context, authorisationConfig *authorisation.Config) (authorisation.Middleware, error) {
 					return authorisationMiddleware, nil
 				},
-				DoGetGeneratorsFunc:   funcDoGetGenerator,
-				DoGetFilesServiceFunc: func(ctx context.Context, cfg *config.Config) (api.FilesService, error) { return fsMock, nil },
-				DoGetResponderFunc:    funcDoGetResponder,
+				DoGetGeneratorsFunc:     funcDoGetGenerator,
+				DoGetFilesServiceFunc:   func(ctx context.Context, cfg *config.Config) (api.FilesService, error) { return fsMock, nil },
+				DoGetResponderFunc:      funcDoGetResponder,
+				DoGetSchemaRegistryFunc: funcDoGetSchemaRegistry,
+				DoGetContentStoreFunc:   funcDoGetContentStoreNone,
+				DoGetScannerFunc:        funcDoGetScannerNone,
 			}
 
 			svcErrors := make(chan error, 1)